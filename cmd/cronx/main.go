@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/peractio/gdk/pkg/cronx/cli"
+)
+
+// Validate, dry-run, and inspect cronx schedules.
+// Example:
+//
+//	cronx validate -separator "#" "0 0 1 * * *#0 0 2 * * *"
+//	cronx next -n 5 -location Asia/Jakarta "@every 5m"
+//	cronx jobs -address http://localhost:8998
+func main() {
+	if err := cli.Run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		if err != cli.ErrUsage {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
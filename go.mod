@@ -3,14 +3,16 @@ module github.com/peractio/gdk
 go 1.15
 
 require (
-	github.com/dgraph-io/ristretto v0.0.3
+	github.com/alicebob/miniredis/v2 v2.14.3
+	github.com/dgraph-io/ristretto v0.1.0
 	github.com/go-redis/redis/v8 v8.8.2
 	github.com/golang/mock v1.4.4
-	github.com/gomodule/redigo v1.8.4
+	github.com/gomodule/redigo v1.8.5
 	github.com/gorilla/mux v1.8.0
 	github.com/json-iterator/go v1.1.10
 	github.com/labstack/echo/v4 v4.2.2
 	github.com/mattn/go-colorable v0.1.8 // indirect
+	github.com/mna/redisc v1.3.2
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.1 // indirect
 	github.com/pingcap/go-ycsb v0.0.0-20210129115622-04d8656123e4
@@ -19,6 +21,7 @@ require (
 	github.com/segmentio/ksuid v1.0.3
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
 	golang.org/x/net v0.0.0-20201224014010-6772e930b67b // indirect
 	golang.org/x/sys v0.0.0-20210403161142-5e06dd20ab57 // indirect
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/peractio/gdk/pkg/cronx"
+	"github.com/peractio/gdk/pkg/jsonx"
+)
+
+// Available sub commands.
+const (
+	CommandValidate = "validate"
+	CommandNext     = "next"
+	CommandJobs     = "jobs"
+)
+
+// Default values of the command flags.
+const (
+	DefaultRuns    = 5
+	DefaultAddress = "http://localhost:8998"
+	DefaultTimeout = 5 * time.Second
+)
+
+// ErrUsage is returned when the command is called with invalid arguments.
+var ErrUsage = errors.New("invalid usage")
+
+// Run executes the cronx command line tool.
+// It can be used as a standalone binary or exposed as a subcommand of a service binary.
+// Args should not contain the program name.
+//
+// Example:
+//
+//	validate -separator "#" "0 0 1 * * *#0 0 2 * * *"
+//	next -n 5 -location Asia/Jakarta "@every 5m"
+//	jobs -address http://localhost:8998
+func Run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		usage(out)
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case CommandValidate:
+		return validate(args[1:], out)
+	case CommandNext:
+		return next(args[1:], out)
+	case CommandJobs:
+		return jobs(ctx, args[1:], out)
+	default:
+		usage(out)
+		return ErrUsage
+	}
+}
+
+// usage prints the list of available commands.
+func usage(out io.Writer) {
+	_, _ = fmt.Fprintln(out, "usage: cronx <command> [flags] [spec...]")
+	_, _ = fmt.Fprintln(out, "")
+	_, _ = fmt.Fprintln(out, "commands:")
+	_, _ = fmt.Fprintln(out, "  validate  validate schedule specifications")
+	_, _ = fmt.Fprintln(out, "  next      print the next execution time of a schedule specification")
+	_, _ = fmt.Fprintln(out, "  jobs      print the job status of a running service")
+}
+
+// validate checks all the given specs.
+// Specs with separator are split the same way as cronx.Schedules,
+// and the separators that could split a single spec are rejected.
+func validate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(CommandValidate, flag.ContinueOnError)
+	fs.SetOutput(out)
+	separator := fs.String("separator", "", "separator for multiple schedules in a single spec")
+	if err := fs.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	invalid := 0
+	for _, spec := range fs.Args() {
+		var err error
+		if *separator != "" {
+			_, err = cronx.ValidateSchedules(spec, *separator)
+		} else {
+			err = cronx.ValidateSpec(spec)
+		}
+
+		if err != nil {
+			invalid++
+			_, _ = fmt.Fprintf(out, "INVALID\t%s\t%s\n", spec, err.Error())
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "OK\t%s\n", spec)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d invalid specification(s)", invalid)
+	}
+	return nil
+}
+
+// next prints the next execution time of the given specs.
+func next(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(CommandNext, flag.ContinueOnError)
+	fs.SetOutput(out)
+	n := fs.Int("n", DefaultRuns, "number of the next execution time")
+	location := fs.String("location", "Local", "timezone of the schedule, e.g. Asia/Jakarta")
+	separator := fs.String("separator", "", "separator for multiple schedules in a single spec")
+	if err := fs.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	loc, err := time.LoadLocation(*location)
+	if err != nil {
+		return err
+	}
+
+	specs := fs.Args()
+	if *separator != "" {
+		var split []string
+		for _, spec := range fs.Args() {
+			res, err := cronx.SplitSchedules(spec, *separator)
+			if err != nil {
+				return err
+			}
+			split = append(split, res...)
+		}
+		specs = split
+	}
+
+	now := time.Now()
+	for _, spec := range specs {
+		runs, err := cronx.NextRuns(spec, loc, now, *n)
+		if err != nil {
+			return fmt.Errorf("invalid specification %s: %w", spec, err)
+		}
+
+		_, _ = fmt.Fprintln(out, spec)
+		for _, v := range runs {
+			_, _ = fmt.Fprintf(out, "  %s\n", v.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// jobs queries the status of a running service and prints it as a table.
+func jobs(ctx context.Context, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(CommandJobs, flag.ContinueOnError)
+	fs.SetOutput(out)
+	address := fs.String("address", DefaultAddress, "address of the cronx server")
+	timeout := fs.Duration("timeout", DefaultTimeout, "request timeout")
+	if err := fs.Parse(args); err != nil {
+		return ErrUsage
+	}
+
+	data, err := GetStatusData(ctx, *address, *timeout)
+	if err != nil {
+		return err
+	}
+
+	return WriteStatusTable(out, data)
+}
+
+// GetStatusData returns the job status of a running service from its /api/jobs endpoint.
+func GetStatusData(ctx context.Context, address string, timeout time.Duration) ([]cronx.StatusData, error) {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(address, "/")+"/api/jobs", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", res.StatusCode, string(body))
+	}
+
+	var payload struct {
+		Data []cronx.StatusData `json:"data"`
+	}
+	if err := jsonx.New().Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.Data, nil
+}
+
+// WriteStatusTable writes the job status as a table.
+func WriteStatusTable(out io.Writer, data []cronx.StatusData) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tSTATUS\tWAVE\tLATENCY\tPREV RUN\tNEXT RUN\tERROR")
+	for _, v := range data {
+		if v.Job == nil {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%d/%d\t%s\t%s\t%s\t%s\n",
+			v.ID,
+			v.Job.Name,
+			v.Job.Status,
+			v.Job.Wave,
+			v.Job.TotalWave,
+			dash(v.Job.Latency),
+			formatTime(v.Prev),
+			formatTime(v.Next),
+			dash(v.Job.Error),
+		)
+	}
+
+	return w.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
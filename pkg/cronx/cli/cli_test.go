@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/jobs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[` +
+			`{"job":{"name":"PayBill","status":"DOWN","wave":1,"total_wave":1,"error":"broken spec"}},` +
+			`{"id":1,"job":{"name":"SendEmail","status":"IDLE","wave":1,"total_wave":1,"latency":"1s"},` +
+			`"next_run":"2020-12-11T22:36:35+07:00","prev_run":"2020-12-11T22:36:30+07:00"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains []string
+		wantErr  bool
+	}{
+		{
+			name:     "Missing command",
+			args:     nil,
+			contains: []string{"usage"},
+			wantErr:  true,
+		},
+		{
+			name:     "Unknown command",
+			args:     []string{"unknown"},
+			contains: []string{"usage"},
+			wantErr:  true,
+		},
+		{
+			name:     "Validate without spec",
+			args:     []string{CommandValidate},
+			contains: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Validate broken spec",
+			args:     []string{CommandValidate, "@every 5m", "this is broken"},
+			contains: []string{"OK\t@every 5m", "INVALID\tthis is broken"},
+			wantErr:  true,
+		},
+		{
+			name:     "Validate reserved separator",
+			args:     []string{CommandValidate, "-separator", ",", "0 0 1 * * *,0 0 2 * * *"},
+			contains: []string{"INVALID"},
+			wantErr:  true,
+		},
+		{
+			name:     "Validate schedules",
+			args:     []string{CommandValidate, "-separator", "#", "0 0 1 * * *#0 0 2 * * *"},
+			contains: []string{"OK\t0 0 1 * * *#0 0 2 * * *"},
+			wantErr:  false,
+		},
+		{
+			name:     "Next with unknown location",
+			args:     []string{CommandNext, "-location", "Unknown/Location", "@every 5m"},
+			contains: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Next with broken spec",
+			args:     []string{CommandNext, "this is broken"},
+			contains: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Next schedules",
+			args:     []string{CommandNext, "-n", "2", "-location", "UTC", "-separator", "#", "0 0 1 * * *#@every 5m"},
+			contains: []string{"0 0 1 * * *\n", "@every 5m\n", "Z\n"},
+			wantErr:  false,
+		},
+		{
+			name:     "Jobs with unavailable server",
+			args:     []string{CommandJobs, "-address", server.URL + "/unavailable"},
+			contains: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "Jobs",
+			args:     []string{CommandJobs, "-address", server.URL},
+			contains: []string{"NAME", "PayBill", "DOWN", "broken spec", "SendEmail", "IDLE", "2020-12-11T22:36:35+07:00"},
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := Run(context.Background(), tt.args, out); (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, v := range tt.contains {
+				assert.Contains(t, out.String(), v)
+			}
+		})
+	}
+}
@@ -33,9 +33,7 @@ func NewCommandController(config Config, interceptors ...Interceptor) *CommandCo
 	}
 
 	// Support the v1 where the first parameter is second.
-	parser := cron.NewParser(
-		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
-	)
+	parser := NewParser()
 
 	// Create the commander.
 	commander := cron.New(
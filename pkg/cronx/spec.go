@@ -0,0 +1,91 @@
+package cronx
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ReservedSymbols are the characters used by the cron specification.
+// They should never be used as a separator for Schedules.
+const ReservedSymbols = "*/,-?"
+
+// NewParser returns the parser used by the command controller.
+// It supports the v1 specification where the first parameter is second,
+// and the predefined schedules like @every, @daily, @hourly.
+func NewParser() cron.Parser {
+	return cron.NewParser(
+		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	)
+}
+
+// ValidateSpec checks whether the spec can be parsed by the command controller parser.
+func ValidateSpec(spec string) error {
+	_, err := NewParser().Parse(spec)
+	return err
+}
+
+// SplitSchedules splits the spec into a list of single specs, the same way as Schedules.
+// It does not validate each of the returned specs.
+func SplitSchedules(spec, separator string) ([]string, error) {
+	if spec == "" {
+		return nil, errors.New("invalid specification")
+	}
+	if separator == "" {
+		return nil, errors.New("invalid separator")
+	}
+
+	return strings.Split(spec, separator), nil
+}
+
+// ValidateSchedules checks whether all the specs inside a Schedules spec are valid,
+// and rejects the separators that could split a single spec, a space or one of the ReservedSymbols.
+// It returns the list of single specs on success.
+func ValidateSchedules(spec, separator string) ([]string, error) {
+	specs, err := SplitSchedules(spec, separator)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(separator) == "" || strings.ContainsAny(separator, ReservedSymbols) {
+		return nil, errors.New("invalid separator, separator must not be a space or contain " + ReservedSymbols)
+	}
+
+	for _, v := range specs {
+		if err := ValidateSpec(v); err != nil {
+			return nil, errors.New("invalid specification " + v + ": " + err.Error())
+		}
+	}
+
+	return specs, nil
+}
+
+// NextRuns returns the next n execution time of a spec after the given time.
+// The returned time is in the given location.
+// Nil location means the job is running in the server location.
+func NextRuns(spec string, location *time.Location, from time.Time, n int) ([]time.Time, error) {
+	if n <= 0 {
+		return nil, errors.New("invalid number of runs")
+	}
+	if location == nil {
+		location = defaultConfig.Location
+	}
+
+	schedule, err := NewParser().Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]time.Time, 0, n)
+	next := from.In(location)
+	for i := 0; i < n; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,207 @@
+package cronx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{
+			name:    "Broken specification",
+			spec:    "this is not specification",
+			wantErr: true,
+		},
+		{
+			name:    "Success with seconds",
+			spec:    "0 0 1 * * *",
+			wantErr: false,
+		},
+		{
+			name:    "Success without seconds",
+			spec:    "0 */1 * * *",
+			wantErr: false,
+		},
+		{
+			name:    "Success with descriptor",
+			spec:    "@every 5m",
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSpec(tt.spec); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSchedules(t *testing.T) {
+	type args struct {
+		spec      string
+		separator string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Empty specification",
+			args:    args{spec: "", separator: "#"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Empty separator",
+			args:    args{spec: "0 0 1 * * *", separator: ""},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Reserved separator",
+			args:    args{spec: "0 0 1 * * *,0 0 2 * * *", separator: ","},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Space separator",
+			args:    args{spec: "0 0 1 * * *", separator: " "},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Partial broken specification",
+			args:    args{spec: "0 0 1 * * *#this is broken", separator: "#"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Success",
+			args:    args{spec: "0 0 1 * * *#@every 5m", separator: "#"},
+			want:    []string{"0 0 1 * * *", "@every 5m"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateSchedules(tt.args.spec, tt.args.separator)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSchedules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitSchedules(t *testing.T) {
+	type args struct {
+		spec      string
+		separator string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Empty specification",
+			args:    args{spec: "", separator: "#"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Empty separator",
+			args:    args{spec: "@daily", separator: ""},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Reserved separator is split as Schedules does",
+			args:    args{spec: "@daily,@hourly", separator: ","},
+			want:    []string{"@daily", "@hourly"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitSchedules(tt.args.spec, tt.args.separator)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitSchedules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNextRuns(t *testing.T) {
+	from := time.Date(2020, 12, 11, 22, 36, 30, 0, time.UTC)
+	jakarta := time.FixedZone("WIB", int((7 * time.Hour).Seconds()))
+
+	type args struct {
+		spec     string
+		location *time.Location
+		n        int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			name:    "Invalid number of runs",
+			args:    args{spec: "@every 5m", location: time.UTC, n: 0},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Broken specification",
+			args:    args{spec: "this is broken", location: time.UTC, n: 1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Success with interval",
+			args: args{spec: "@every 5m", location: time.UTC, n: 2},
+			want: []time.Time{
+				time.Date(2020, 12, 11, 22, 41, 30, 0, time.UTC),
+				time.Date(2020, 12, 11, 22, 46, 30, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Success with location",
+			args: args{spec: "0 0 1 * * *", location: jakarta, n: 2},
+			want: []time.Time{
+				time.Date(2020, 12, 13, 1, 0, 0, 0, jakarta),
+				time.Date(2020, 12, 14, 1, 0, 0, 0, jakarta),
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextRuns(tt.args.spec, tt.args.location, from, tt.args.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NextRuns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, len(tt.want), len(got))
+			for i := range tt.want {
+				assert.True(t, tt.want[i].Equal(got[i]), "want %v, got %v", tt.want[i], got[i])
+			}
+		})
+	}
+}
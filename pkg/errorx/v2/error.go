@@ -50,3 +50,8 @@ func (e *Error) Error() string {
 func (e *Error) GetFields() Fields {
 	return e.Fields
 }
+
+// Unwrap returns the underlying error, so errors.Is and errors.As inspect it.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
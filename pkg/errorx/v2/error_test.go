@@ -86,3 +86,34 @@ func TestError_GetFields(t *testing.T) {
 		})
 	}
 }
+
+func TestError_Unwrap(t *testing.T) {
+	standardErr := errors.New("standard-error")
+
+	tests := []struct {
+		name  string
+		input error
+		want  error
+	}{
+		{
+			name:  "standard error",
+			input: E(standardErr, Op("op"), CodeGateway),
+			want:  standardErr,
+		},
+		{
+			name:  "2 layer with standard error",
+			input: E(E(standardErr, Op("op1")), Op("op2")),
+			want:  standardErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.Unwrap(tt.input)
+			if got != tt.want || !errors.Is(tt.input, tt.want) {
+				msg := "\nwant = %#v" + "\ngot  = %#v\n"
+				t.Errorf(msg, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/storage/cache/ratelimit"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+type (
+	// RateLimitConfig defines the config for RateLimit middleware.
+	RateLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper middleware.Skipper
+
+		// Limiter counts the requests.
+		// Required.
+		Limiter ratelimit.Limiter
+
+		// KeyFunc defines a function to get the key of the request to be limited.
+		// Optional. Default value is the client IP, see TrustProxyHeaders.
+		KeyFunc func(c echo.Context) string
+
+		// TrustProxyHeaders takes the client IP from echo.Context#RealIP,
+		// the X-Forwarded-For or X-Real-IP header unless Echo#IPExtractor is set.
+		// Any client can set these headers to bypass the limit,
+		// so it must only be enabled behind a proxy overwriting them.
+		// Optional. Default value is false, the client IP is the remote address.
+		TrustProxyHeaders bool
+	}
+)
+
+var (
+	// DefaultRateLimitConfig is the default RateLimit middleware config.
+	DefaultRateLimitConfig = RateLimitConfig{
+		Skipper:           middleware.DefaultSkipper,
+		KeyFunc:           nil,
+		TrustProxyHeaders: false,
+	}
+)
+
+// RateLimit returns a middleware that limits the rate of requests by client IP.
+// The limited request is replied with "429 - Too Many Requests" and the Retry-After header.
+// The request is allowed when the limiter fails, so the service stays available without its storage.
+func RateLimit(limiter ratelimit.Limiter) echo.MiddlewareFunc {
+	config := DefaultRateLimitConfig
+	config.Limiter = limiter
+	return RateLimitWithConfig(config)
+}
+
+// RateLimitWithConfig returns a RateLimit middleware with config.
+func RateLimitWithConfig(config RateLimitConfig) echo.MiddlewareFunc {
+	const op errorx.Op = "middleware.RateLimit"
+
+	// Defaults
+	if config.Limiter == nil {
+		panic("rate limit middleware requires a limiter")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultRateLimitConfig.Skipper
+	}
+	if config.KeyFunc == nil {
+		extractIP := echo.ExtractIPDirect()
+		config.KeyFunc = func(c echo.Context) string {
+			if config.TrustProxyHeaders {
+				return c.RealIP()
+			}
+			return extractIP(c.Request())
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := config.KeyFunc(c)
+
+			result, err := config.Limiter.Allow(ctx, key)
+			if err != nil {
+				logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" failed, request is allowed")
+				return next(c)
+			}
+
+			ratelimit.SetHeaders(c.Response().Header(), result)
+			if !result.Allowed {
+				return echo.NewHTTPError(http.StatusTooManyRequests)
+			}
+
+			return next(c)
+		}
+	}
+}
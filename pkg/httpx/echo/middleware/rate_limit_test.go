@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLimiter records the limited keys and replies the same result.
+type fakeLimiter struct {
+	result ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (l *fakeLimiter) Allow(_ context.Context, key string) (ratelimit.Result, error) {
+	l.keys = append(l.keys, key)
+	return l.result, l.err
+}
+
+func TestRateLimitWithConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		limiter    *fakeLimiter
+		trustProxy bool
+		wantStatus int
+		wantKey    string
+	}{
+		{
+			name:       "Allowed",
+			limiter:    &fakeLimiter{result: ratelimit.Result{Limit: 2, Allowed: true, Remaining: 1}},
+			wantStatus: http.StatusOK,
+			wantKey:    "10.0.0.1",
+		},
+		{
+			name:       "Limited",
+			limiter:    &fakeLimiter{result: ratelimit.Result{Limit: 2, ResetAfter: time.Second}},
+			wantStatus: http.StatusTooManyRequests,
+			wantKey:    "10.0.0.1",
+		},
+		{
+			name:       "Limiter error",
+			limiter:    &fakeLimiter{err: errorx.E("redis error", errorx.CodeGateway)},
+			wantStatus: http.StatusOK,
+			wantKey:    "10.0.0.1",
+		},
+		{
+			name:       "Trusted proxy headers",
+			limiter:    &fakeLimiter{result: ratelimit.Result{Limit: 2, Allowed: true, Remaining: 1}},
+			trustProxy: true,
+			wantStatus: http.StatusOK,
+			wantKey:    "192.168.0.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(RateLimitWithConfig(RateLimitConfig{
+				Limiter:           tt.limiter,
+				TrustProxyHeaders: tt.trustProxy,
+			}))
+			e.GET("/", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			// The client sets the header, it is only trusted behind a proxy.
+			req.Header.Set(echo.HeaderXForwardedFor, "192.168.0.1, 10.0.0.2")
+			res := httptest.NewRecorder()
+			e.ServeHTTP(res, req)
+
+			assert.Equal(t, tt.wantStatus, res.Code)
+			assert.Equal(t, []string{tt.wantKey}, tt.limiter.keys)
+			if tt.wantStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "1", res.Header().Get(ratelimit.HeaderRetryAfter))
+			}
+		})
+	}
+}
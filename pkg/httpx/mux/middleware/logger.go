@@ -3,9 +3,7 @@ package middleware
 import (
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/peractio/gdk/pkg/env"
@@ -72,19 +70,6 @@ func NewLogger(opts ...LogOptions) *LoggingMiddleware {
 	}
 }
 
-// realIP get the real IP from http request
-func realIP(req *http.Request) string {
-	ra := req.RemoteAddr
-	if ip := req.Header.Get(mux.HeaderXForwardedFor); ip != "" {
-		ra = strings.Split(ip, ", ")[0]
-	} else if ip := req.Header.Get(mux.HeaderXRealIP); ip != "" {
-		ra = ip
-	} else {
-		ra, _, _ = net.SplitHostPort(ra)
-	}
-	return ra
-}
-
 type loggingResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
@@ -118,7 +103,7 @@ func (m *LoggingMiddleware) Middleware(next http.Handler) http.Handler {
 			entry = entry.WithField("request_id", reqID)
 		}
 
-		if remoteAddr := realIP(r); remoteAddr != "" {
+		if remoteAddr := realIP(r, true); remoteAddr != "" {
 			entry = entry.WithField("remote_addr", remoteAddr)
 		}
 
@@ -1,7 +1,11 @@
 package middleware
 
 import (
+	"net"
 	"net/http"
+	"strings"
+
+	"github.com/peractio/gdk/pkg/httpx/mux"
 )
 
 type (
@@ -14,3 +18,23 @@ type (
 func DefaultSkipper(res http.ResponseWriter, req *http.Request) bool {
 	return false
 }
+
+// realIP returns the client IP of the request from its remote address.
+// When trustProxy is true, the X-Forwarded-For or X-Real-IP header set by the proxy takes precedence.
+// Any client can set these headers, so they must only be trusted behind a proxy overwriting them.
+func realIP(req *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if ip := req.Header.Get(mux.HeaderXForwardedFor); ip != "" {
+			return strings.TrimSpace(strings.Split(ip, ",")[0])
+		}
+		if ip := req.Header.Get(mux.HeaderXRealIP); ip != "" {
+			return ip
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return ip
+}
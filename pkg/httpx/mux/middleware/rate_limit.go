@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/storage/cache/ratelimit"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+type (
+	// RateLimitConfig defines the config for RateLimit middleware.
+	RateLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Limiter counts the requests.
+		// Required.
+		Limiter ratelimit.Limiter
+
+		// KeyFunc defines a function to get the key of the request to be limited.
+		// Optional. Default value is the client IP, see TrustProxyHeaders.
+		KeyFunc func(req *http.Request) string
+
+		// TrustProxyHeaders takes the client IP from the X-Forwarded-For or X-Real-IP header.
+		// Any client can set these headers to bypass the limit,
+		// so it must only be enabled behind a proxy overwriting them.
+		// Optional. Default value is false, the client IP is the remote address.
+		TrustProxyHeaders bool
+	}
+)
+
+var (
+	// DefaultRateLimitConfig is the default RateLimit middleware config.
+	DefaultRateLimitConfig = RateLimitConfig{
+		Skipper:           DefaultSkipper,
+		KeyFunc:           nil,
+		TrustProxyHeaders: false,
+	}
+)
+
+// RateLimit returns a middleware that limits the rate of requests by client IP.
+// The limited request is replied with "429 - Too Many Requests" and the Retry-After header.
+// The request is allowed when the limiter fails, so the service stays available without its storage.
+func RateLimit(next http.Handler, limiter ratelimit.Limiter) http.Handler {
+	config := DefaultRateLimitConfig
+	config.Limiter = limiter
+	return RateLimitWithConfig(next, config)
+}
+
+// RateLimitWithConfig returns a RateLimit middleware with config.
+func RateLimitWithConfig(next http.Handler, config RateLimitConfig) http.Handler {
+	const op errorx.Op = "middleware.RateLimit"
+
+	// Defaults
+	if config.Limiter == nil {
+		panic("rate limit middleware requires a limiter")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultRateLimitConfig.Skipper
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(req *http.Request) string {
+			return realIP(req, config.TrustProxyHeaders)
+		}
+	}
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if config.Skipper(res, req) {
+			next.ServeHTTP(res, req)
+			return
+		}
+
+		ctx := req.Context()
+		key := config.KeyFunc(req)
+
+		result, err := config.Limiter.Allow(ctx, key)
+		if err != nil {
+			logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" failed, request is allowed")
+			next.ServeHTTP(res, req)
+			return
+		}
+
+		ratelimit.SetHeaders(res.Header(), result)
+		if !result.Allowed {
+			http.Error(res, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(res, req)
+	})
+}
@@ -0,0 +1,249 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// BatchItem is a key-value pair to be set by a batch operation.
+type BatchItem struct {
+	Key   string
+	Value string
+	// Seconds is the time to live of the key.
+	Seconds int64
+}
+
+// BatchResult is the result of a key in a batch operation.
+type BatchResult struct {
+	Key string
+	// Value is the value of the key, only filled by the read operations.
+	Value []byte
+	// Err is the error of the key.
+	// Missing key is reported as an error with errorx.CodeNotFound.
+	Err error
+}
+
+// PipelineCmd is a command queued in a pipeline.
+// The reply is available once the pipeline is executed.
+type PipelineCmd struct {
+	args []interface{}
+	val  interface{}
+	err  error
+}
+
+// Args returns the command name followed by the arguments.
+func (c *PipelineCmd) Args() []interface{} {
+	return c.args
+}
+
+// Val returns the raw reply of the command.
+func (c *PipelineCmd) Val() (interface{}, error) {
+	return c.val, c.err
+}
+
+// Bytes returns the reply of the command in []byte form.
+// Nil reply is reported as an error with errorx.CodeNotFound.
+func (c *PipelineCmd) Bytes() ([]byte, error) {
+	const op errorx.Op = "cache/PipelineCmd.Bytes"
+
+	if c.err != nil {
+		return nil, errorx.E(c.err, op)
+	}
+
+	res, err := replyBytes(c.val)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// String returns the reply of the command in string form.
+// Nil reply is reported as an error with errorx.CodeNotFound.
+func (c *PipelineCmd) String() (string, error) {
+	res, err := c.Bytes()
+	return string(res), err
+}
+
+// Int64 returns the reply of the command in int64 form.
+// Nil reply is reported as an error with errorx.CodeNotFound.
+func (c *PipelineCmd) Int64() (int64, error) {
+	const op errorx.Op = "cache/PipelineCmd.Int64"
+
+	if c.err != nil {
+		return 0, errorx.E(c.err, op)
+	}
+
+	res, err := replyInt64(c.val)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// pipelineExecFunc sends the commands in a single round trip and fills their replies.
+type pipelineExecFunc func(ctx context.Context, cmds []*PipelineCmd) error
+
+// Pipeline queues commands and sends them in a single round trip.
+// Every command reports its own error, so a failed command doesn't fail the others.
+// Pipeline is not safe for concurrent use.
+type Pipeline struct {
+	exec pipelineExecFunc
+	cmds []*PipelineCmd
+}
+
+// newPipeline returns a pipeline executed by the exec function.
+func newPipeline(exec pipelineExecFunc) *Pipeline {
+	return &Pipeline{
+		exec: exec,
+	}
+}
+
+// Do queues a command, e.g. Do("SET", "key", "value", "EX", 10).
+func (p *Pipeline) Do(command string, args ...interface{}) *PipelineCmd {
+	cmd := &PipelineCmd{
+		args: append([]interface{}{command}, args...),
+	}
+	p.cmds = append(p.cmds, cmd)
+
+	return cmd
+}
+
+// Len returns the number of queued commands.
+func (p *Pipeline) Len() int {
+	return len(p.cmds)
+}
+
+// Exec sends the queued commands and returns them in the queued order.
+// The error is only returned when the commands can't be sent,
+// check the error of every command for the command failure.
+// The pipeline is emptied and can be reused.
+func (p *Pipeline) Exec(ctx context.Context) ([]*PipelineCmd, error) {
+	const op errorx.Op = "cache/Pipeline.Exec"
+
+	cmds := p.cmds
+	p.cmds = nil
+
+	if len(cmds) == 0 {
+		return cmds, nil
+	}
+
+	if err := p.exec(ctx, cmds); err != nil {
+		return cmds, errorx.E(err, op)
+	}
+
+	return cmds, nil
+}
+
+// batchMGet gets the keys with one MGET command for every group of keys,
+// and returns the results in the order of the keys.
+// Keys are grouped by the hash slot in cluster mode, since MGET can't span multiple slots.
+func batchMGet(ctx context.Context, p *Pipeline, keys []string, group func(key string) int) ([]BatchResult, error) {
+	const op errorx.Op = "cache.batchMGet"
+
+	res := make([]BatchResult, len(keys))
+	indexes := make(map[int][]int)
+	var groups []int
+	for i, key := range keys {
+		res[i].Key = key
+
+		g := group(key)
+		if _, ok := indexes[g]; !ok {
+			groups = append(groups, g)
+		}
+		indexes[g] = append(indexes[g], i)
+	}
+
+	cmds := make([]*PipelineCmd, len(groups))
+	for j, g := range groups {
+		args := make([]interface{}, len(indexes[g]))
+		for k, i := range indexes[g] {
+			args[k] = keys[i]
+		}
+		cmds[j] = p.Do("MGET", args...)
+	}
+
+	if _, err := p.Exec(ctx); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	for j, g := range groups {
+		val, err := cmds[j].Val()
+		values, ok := val.([]interface{})
+		for k, i := range indexes[g] {
+			switch {
+			case err != nil:
+				res[i].Err = errorx.E(err, op)
+			case !ok || len(values) != len(indexes[g]):
+				res[i].Err = errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+			default:
+				res[i].Value, res[i].Err = replyBytes(values[k])
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// batchSetEX sets the items with one SET command for every item,
+// and returns the results in the order of the items.
+func batchSetEX(ctx context.Context, p *Pipeline, items []BatchItem) ([]BatchResult, error) {
+	const op errorx.Op = "cache.batchSetEX"
+
+	cmds := make([]*PipelineCmd, len(items))
+	for i, v := range items {
+		cmds[i] = p.Do("SET", v.Key, v.Value, "EX", v.Seconds)
+	}
+
+	if _, err := p.Exec(ctx); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res := make([]BatchResult, len(items))
+	for i, v := range items {
+		res[i].Key = v.Key
+
+		data, err := cmds[i].String()
+		switch {
+		case err != nil:
+			res[i].Err = errorx.E(err, op)
+		case data != "OK":
+			res[i].Err = errorx.E("redis operation ended unsuccessfully", op, errorx.CodeGateway)
+		}
+	}
+
+	return res, nil
+}
+
+// batchDel deletes the keys with one DEL command for every key,
+// and returns the results in the order of the keys.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func batchDel(ctx context.Context, p *Pipeline, keys []string) ([]BatchResult, error) {
+	const op errorx.Op = "cache.batchDel"
+
+	cmds := make([]*PipelineCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = p.Do("DEL", key)
+	}
+
+	if _, err := p.Exec(ctx); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res := make([]BatchResult, len(keys))
+	for i, key := range keys {
+		res[i].Key = key
+
+		data, err := cmds[i].Int64()
+		switch {
+		case err != nil:
+			res[i].Err = errorx.E(err, op)
+		case data == 0:
+			res[i].Err = errorx.E("key not found", op, errorx.CodeNotFound)
+		}
+	}
+
+	return res, nil
+}
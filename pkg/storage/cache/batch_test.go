@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePipeline returns a pipeline that replies every command with the reply function.
+func fakePipeline(reply func(args []interface{}) (interface{}, error)) *Pipeline {
+	return newPipeline(func(_ context.Context, cmds []*PipelineCmd) error {
+		for _, cmd := range cmds {
+			cmd.val, cmd.err = reply(cmd.Args())
+		}
+		return nil
+	})
+}
+
+func TestPipelineCmd(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       *PipelineCmd
+		wantBytes []byte
+		wantInt   int64
+		wantCode  errorx.Code
+	}{
+		{
+			name:      "Error",
+			cmd:       &PipelineCmd{err: errorx.E("redis error", errorx.CodeGateway)},
+			wantBytes: nil,
+			wantInt:   0,
+			wantCode:  errorx.CodeGateway,
+		},
+		{
+			name:      "Nil reply",
+			cmd:       &PipelineCmd{val: nil},
+			wantBytes: nil,
+			wantInt:   0,
+			wantCode:  errorx.CodeNotFound,
+		},
+		{
+			name:      "Bytes reply",
+			cmd:       &PipelineCmd{val: []byte("12")},
+			wantBytes: []byte("12"),
+			wantInt:   12,
+			wantCode:  errorx.CodeUnknown,
+		},
+		{
+			name:      "String reply",
+			cmd:       &PipelineCmd{val: "12"},
+			wantBytes: []byte("12"),
+			wantInt:   12,
+			wantCode:  errorx.CodeUnknown,
+		},
+		{
+			name:      "Integer reply",
+			cmd:       &PipelineCmd{val: int64(12)},
+			wantBytes: []byte("12"),
+			wantInt:   12,
+			wantCode:  errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBytes, err := tt.cmd.Bytes()
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.wantBytes, gotBytes)
+
+			gotInt, err := tt.cmd.Int64()
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.wantInt, gotInt)
+		})
+	}
+}
+
+func TestPipeline_Exec(t *testing.T) {
+	var calls int
+	p := newPipeline(func(_ context.Context, cmds []*PipelineCmd) error {
+		calls++
+		return errorx.E("connection refused", errorx.CodeGateway)
+	})
+
+	cmds, err := p.Exec(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, cmds)
+	assert.Equal(t, 0, calls)
+
+	cmd := p.Do("GET", "key")
+	assert.Equal(t, []interface{}{"GET", "key"}, cmd.Args())
+	assert.Equal(t, 1, p.Len())
+
+	cmds, err = p.Exec(context.Background())
+	assert.True(t, errorx.Is(errorx.CodeGateway, err))
+	assert.Equal(t, []*PipelineCmd{cmd}, cmds)
+	assert.Equal(t, 0, p.Len())
+}
+
+func TestBatchMGet(t *testing.T) {
+	data := map[string]string{"{a}1": "1", "{a}2": "2", "{b}1": "3"}
+
+	var mgets [][]interface{}
+	p := fakePipeline(func(args []interface{}) (interface{}, error) {
+		mgets = append(mgets, args[1:])
+		if args[1] == "{c}1" {
+			return nil, errorx.E("CROSSSLOT", errorx.CodeGateway)
+		}
+
+		res := make([]interface{}, 0, len(args)-1)
+		for _, key := range args[1:] {
+			if v, ok := data[key.(string)]; ok {
+				res = append(res, v)
+			} else {
+				res = append(res, nil)
+			}
+		}
+		return res, nil
+	})
+
+	keys := []string{"{a}1", "{b}1", "{c}1", "{a}2", "{b}2"}
+	got, err := batchMGet(context.Background(), p, keys, Slot)
+	assert.NoError(t, err)
+
+	// Keys are grouped by slot in the order of their first appearance.
+	assert.Equal(t, [][]interface{}{{"{a}1", "{a}2"}, {"{b}1", "{b}2"}, {"{c}1"}}, mgets)
+
+	wantValues := [][]byte{[]byte("1"), []byte("3"), nil, []byte("2"), nil}
+	wantCodes := []errorx.Code{errorx.CodeUnknown, errorx.CodeUnknown, errorx.CodeGateway, errorx.CodeUnknown, errorx.CodeNotFound}
+	for i, v := range got {
+		assert.Equal(t, keys[i], v.Key)
+		assert.Equal(t, wantValues[i], v.Value)
+		assert.Equal(t, wantCodes[i], errorx.GetCode(v.Err))
+	}
+}
+
+func TestBatchSetEX(t *testing.T) {
+	p := fakePipeline(func(args []interface{}) (interface{}, error) {
+		if args[4] == int64(0) {
+			return nil, errorx.E("invalid expire time", errorx.CodeGateway)
+		}
+		return "OK", nil
+	})
+
+	got, err := batchSetEX(context.Background(), p, []BatchItem{
+		{Key: "a", Value: "1", Seconds: 10},
+		{Key: "b", Value: "2", Seconds: 0},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].Key)
+	assert.NoError(t, got[0].Err)
+	assert.Equal(t, "b", got[1].Key)
+	assert.True(t, errorx.Is(errorx.CodeGateway, got[1].Err))
+}
+
+func TestBatchDel(t *testing.T) {
+	p := fakePipeline(func(args []interface{}) (interface{}, error) {
+		if args[1] == "missing" {
+			return int64(0), nil
+		}
+		return int64(1), nil
+	})
+
+	got, err := batchDel(context.Background(), p, []string{"a", "missing"})
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.NoError(t, got[0].Err)
+	assert.True(t, IsNotFound(got[1].Err))
+}
@@ -0,0 +1,216 @@
+// Package bulk provides the bulk operations on the redis keys matching a pattern, e.g. to clean up a namespace.
+// The keys are iterated with SCAN rather than KEYS, and processed at a limited rate,
+// so the operations don't block nor overload a production redis.
+package bulk
+
+import (
+	"context"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/peractio/gdk/pkg/storage/cache/ratelimit"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// limiterKey is the key of the rate limiter, shared by all the bulk operations.
+const limiterKey = "bulk"
+
+// Client is a redis client able to iterate the keys.
+type Client interface {
+	cache.RedisItf
+	cache.ScannerItf
+}
+
+// Config configuration.
+type Config struct {
+	// Match is the pattern of the keys, e.g. "order:production:*".
+	// It is required, so every key is only processed on purpose with "*".
+	Match string
+	// Count is the number of keys scanned at a time.
+	Count int64
+	// Limit is the maximum rate of the processed keys.
+	Limit ratelimit.Limit
+	// Limiter limits the rate of the processed keys instead of Limit,
+	// e.g. a ratelimit.RedisLimiter sharing the rate between several processes.
+	Limiter ratelimit.Limiter
+	// DryRun scans and counts the keys without changing them.
+	DryRun bool
+	// Progress is called after every batch of keys.
+	Progress func(progress Progress)
+}
+
+// DefaultConfig is the default configuration of the bulk operations.
+var DefaultConfig = Config{
+	Match:    "",
+	Count:    100,
+	Limit:    ratelimit.PerSecond(1000),
+	Limiter:  nil,
+	DryRun:   false,
+	Progress: nil,
+}
+
+// Progress is the progress of a bulk operation.
+type Progress struct {
+	// Scanned is the number of scanned keys, a key may be scanned more than once.
+	Scanned int64
+	// Processed is the number of keys deleted, expired or exported.
+	Processed int64
+	// Skipped is the number of keys missing when processed, e.g. expired or scanned twice.
+	Skipped int64
+	// Failed is the number of keys failed to be processed.
+	Failed int64
+}
+
+// Delete deletes the keys matching the pattern of the configuration.
+// The failed keys are logged and counted, without stopping the operation.
+func Delete(ctx context.Context, client Client, config Config) (Progress, error) {
+	const op errorx.Op = "bulk.Delete"
+
+	progress, err := run(ctx, op, client, config, func(keys []string, progress *Progress) error {
+		n, err := client.Del(ctx, toInterfaces(keys)...)
+		if err != nil {
+			progress.Failed += int64(len(keys))
+			logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Count: len(keys)}), string(op)+" keys failed")
+			return nil
+		}
+
+		progress.Processed += n
+		progress.Skipped += int64(len(keys)) - n
+		return nil
+	})
+	if err != nil {
+		return progress, errorx.E(err, op)
+	}
+
+	return progress, nil
+}
+
+// Expire sets the ttl of the keys matching the pattern of the configuration, rounded down to seconds.
+// The failed keys are logged and counted, without stopping the operation.
+func Expire(ctx context.Context, client Client, config Config, ttl time.Duration) (Progress, error) {
+	const op errorx.Op = "bulk.Expire"
+
+	seconds := int64(ttl / time.Second)
+	if seconds <= 0 {
+		return Progress{}, errorx.E("ttl must be at least one second", op, errorx.CodeInvalid)
+	}
+
+	progress, err := run(ctx, op, client, config, func(keys []string, progress *Progress) error {
+		for _, key := range keys {
+			ok, err := client.Expire(ctx, key, seconds)
+			switch {
+			case err != nil:
+				progress.Failed++
+				logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" key failed")
+			case ok:
+				progress.Processed++
+			default:
+				progress.Skipped++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return progress, errorx.E(err, op)
+	}
+
+	return progress, nil
+}
+
+// run scans the keys matching the pattern, waits for the rate limiter, and calls fn with every batch of keys,
+// until all the keys are scanned, the context is done or fn fails.
+// In the dry-run mode, the keys are counted as processed without calling fn.
+func run(
+	ctx context.Context,
+	op errorx.Op,
+	client cache.ScannerItf,
+	config Config,
+	fn func(keys []string, progress *Progress) error,
+) (Progress, error) {
+	var progress Progress
+
+	if config.Match == "" {
+		return progress, errorx.E("missing match pattern", errorx.CodeInvalid)
+	}
+
+	// Defaults
+	if config.Count <= 0 {
+		config.Count = DefaultConfig.Count
+	}
+	if config.Limit.Rate <= 0 || config.Limit.Period <= 0 {
+		config.Limit = DefaultConfig.Limit
+	}
+
+	limiter := config.Limiter
+	if limiter == nil {
+		local, err := ratelimit.NewLocalLimiter(ratelimit.Config{Algorithm: ratelimit.TokenBucket, Limit: config.Limit})
+		if err != nil {
+			return progress, err
+		}
+		limiter = local
+	}
+
+	began := time.Now()
+	err := client.ScanKeys(ctx, config.Match, config.Count, func(keys []string) error {
+		for range keys {
+			if err := wait(ctx, limiter); err != nil {
+				return err
+			}
+		}
+
+		progress.Scanned += int64(len(keys))
+		if config.DryRun {
+			progress.Processed += int64(len(keys))
+		} else if err := fn(keys, &progress); err != nil {
+			return err
+		}
+
+		if config.Progress != nil {
+			config.Progress(progress)
+		}
+		return nil
+	})
+	if err != nil {
+		return progress, err
+	}
+
+	logx.INF(ctx, logx.KV{
+		tags.Key:     config.Match,
+		tags.Count:   progress.Processed,
+		tags.Latency: time.Since(began).String(),
+	}, string(op)+" done")
+	return progress, nil
+}
+
+// wait waits until the limiter allows a key, or the context is done.
+func wait(ctx context.Context, limiter ratelimit.Limiter) error {
+	for {
+		res, err := limiter.Allow(ctx, limiterKey)
+		if err != nil {
+			return err
+		}
+		if res.Allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(res.RetryAfter())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errorx.E(ctx.Err(), errorx.CodeGateway)
+		case <-timer.C:
+		}
+	}
+}
+
+// toInterfaces converts the keys into the variadic arguments of Del.
+func toInterfaces(keys []string) []interface{} {
+	res := make([]interface{}, len(keys))
+	for i, v := range keys {
+		res[i] = v
+	}
+
+	return res
+}
@@ -0,0 +1,197 @@
+package bulk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/peractio/gdk/pkg/storage/cache/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newClient returns an in-memory redis with the keys "order:1" to "order:3" and "user:1".
+func newClient(t *testing.T) *cache.MemoryRedis {
+	client := cache.NewMemoryRedis()
+	t.Cleanup(client.Close)
+
+	ctx := context.Background()
+	for _, key := range []string{"order:1", "order:2", "order:3", "user:1"} {
+		require.NoError(t, client.SimpleSet(ctx, key, "value"))
+	}
+
+	return client
+}
+
+// keys returns all the keys of the client.
+func keys(t *testing.T, client cache.ScannerItf) []string {
+	res := make([]string, 0)
+	err := client.ScanKeys(context.Background(), "*", 10, func(keys []string) error {
+		res = append(res, keys...)
+		return nil
+	})
+	require.NoError(t, err)
+
+	return res
+}
+
+// limiter allows a fixed number of keys, then reports a retry after a millisecond.
+type limiter struct {
+	allowed int
+	calls   int
+}
+
+func (l *limiter) Allow(_ context.Context, _ string) (ratelimit.Result, error) {
+	l.calls++
+	if l.allowed <= 0 {
+		return ratelimit.Result{Allowed: false, ResetAfter: time.Millisecond}, nil
+	}
+
+	l.allowed--
+	return ratelimit.Result{Allowed: true}, nil
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		want     Progress
+		wantErr  errorx.Code
+		wantKeys []string
+	}{
+		{
+			name:     "Missing match",
+			config:   Config{},
+			want:     Progress{},
+			wantErr:  errorx.CodeInvalid,
+			wantKeys: []string{"order:1", "order:2", "order:3", "user:1"},
+		},
+		{
+			name:     "Matching keys",
+			config:   Config{Match: "order:*", Count: 2},
+			want:     Progress{Scanned: 3, Processed: 3},
+			wantKeys: []string{"user:1"},
+		},
+		{
+			name:     "No matching keys",
+			config:   Config{Match: "product:*"},
+			want:     Progress{},
+			wantKeys: []string{"order:1", "order:2", "order:3", "user:1"},
+		},
+		{
+			name:     "Dry run",
+			config:   Config{Match: "order:*", DryRun: true},
+			want:     Progress{Scanned: 3, Processed: 3},
+			wantKeys: []string{"order:1", "order:2", "order:3", "user:1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient(t)
+			ctx := context.Background()
+
+			got, err := Delete(ctx, client, tt.config)
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, errorx.GetCode(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			assert.ElementsMatch(t, tt.wantKeys, keys(t, client))
+		})
+	}
+}
+
+func TestDelete_Progress(t *testing.T) {
+	client := newClient(t)
+	l := &limiter{allowed: 4}
+
+	var progress []Progress
+	got, err := Delete(context.Background(), client, Config{
+		Match:    "*",
+		Count:    1,
+		Limiter:  l,
+		Progress: func(p Progress) { progress = append(progress, p) },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Progress{Scanned: 4, Processed: 4}, got)
+	assert.Equal(t, 4, l.calls)
+
+	if assert.Len(t, progress, 4) {
+		assert.Equal(t, Progress{Scanned: 1, Processed: 1}, progress[0])
+		assert.Equal(t, got, progress[3])
+	}
+}
+
+func TestDelete_RateLimited(t *testing.T) {
+	client := newClient(t)
+
+	// The keys wait for the limiter until the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	l := &limiter{allowed: 0}
+	got, err := Delete(ctx, client, Config{Match: "*", Limiter: l})
+	assert.Equal(t, errorx.CodeGateway, errorx.GetCode(err))
+	assert.Equal(t, Progress{}, got)
+	assert.True(t, l.calls > 1)
+
+	assert.Len(t, keys(t, client), 4)
+}
+
+func TestExpire(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		ttl     time.Duration
+		want    Progress
+		wantErr errorx.Code
+		wantTTL map[string]int64
+	}{
+		{
+			name:    "Invalid ttl",
+			config:  Config{Match: "order:*"},
+			ttl:     time.Millisecond,
+			want:    Progress{},
+			wantErr: errorx.CodeInvalid,
+			wantTTL: map[string]int64{"order:1": -1, "user:1": -1},
+		},
+		{
+			name:    "Matching keys",
+			config:  Config{Match: "order:*"},
+			ttl:     time.Minute,
+			want:    Progress{Scanned: 3, Processed: 3},
+			wantTTL: map[string]int64{"order:1": 60, "order:3": 60, "user:1": -1},
+		},
+		{
+			name:    "Dry run",
+			config:  Config{Match: "order:*", DryRun: true},
+			ttl:     time.Minute,
+			want:    Progress{Scanned: 3, Processed: 3},
+			wantTTL: map[string]int64{"order:1": -1, "user:1": -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient(t)
+			ctx := context.Background()
+
+			got, err := Expire(ctx, client, tt.config, tt.ttl)
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, errorx.GetCode(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			for key, want := range tt.wantTTL {
+				ttl, err := client.TTL(ctx, key)
+				assert.NoError(t, err)
+				assert.Equal(t, want, ttl, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package bulk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// Entry is an exported key, written as a line of json.
+type Entry struct {
+	Key string `json:"key"`
+	// TTL is the remaining ttl of the key in milliseconds, zero means the key has no expiry.
+	TTL int64 `json:"ttl"`
+	// Value is the serialized value of the key, to be restored with cache.DumperItf.
+	Value []byte `json:"value"`
+}
+
+// Export writes the keys matching the pattern of the configuration to w, one json Entry per line.
+// The client must implement cache.DumperItf, so the keys of any type are exported.
+// The failed keys are logged and counted, but a failed write stops the operation.
+func Export(ctx context.Context, client Client, config Config, w io.Writer) (Progress, error) {
+	const op errorx.Op = "bulk.Export"
+
+	dumper, ok := client.(cache.DumperItf)
+	if !ok {
+		return Progress{}, errorx.E("client must implement cache.DumperItf", op, errorx.CodeConfig)
+	}
+
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+
+	progress, err := run(ctx, op, client, config, func(keys []string, progress *Progress) error {
+		for _, key := range keys {
+			value, ttl, err := dumper.Dump(ctx, key)
+			switch {
+			case errorx.GetCode(err) == errorx.CodeNotFound:
+				progress.Skipped++
+				continue
+			case err != nil:
+				progress.Failed++
+				logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" key failed")
+				continue
+			}
+
+			if err := encoder.Encode(Entry{Key: key, TTL: int64(ttl / time.Millisecond), Value: value}); err != nil {
+				return errorx.E(err, errorx.CodeInternal)
+			}
+			progress.Processed++
+		}
+		return nil
+	})
+	if err != nil {
+		return progress, errorx.E(err, op)
+	}
+
+	if err := buf.Flush(); err != nil {
+		return progress, errorx.E(err, op, errorx.CodeInternal)
+	}
+
+	return progress, nil
+}
+
+// ExportFile writes the keys matching the pattern of the configuration to the file at path,
+// truncating the file when it exists.
+func ExportFile(ctx context.Context, client Client, config Config, path string) (Progress, error) {
+	const op errorx.Op = "bulk.ExportFile"
+
+	file, err := os.Create(path)
+	if err != nil {
+		return Progress{}, errorx.E(err, op, errorx.CodeInternal)
+	}
+
+	progress, err := Export(ctx, client, config, file)
+	if cerr := file.Close(); err == nil && cerr != nil {
+		err = errorx.E(cerr, op, errorx.CodeInternal)
+	}
+	if err != nil {
+		return progress, errorx.E(err, op)
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,116 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dumpClient is an in-memory redis dumping the keys with a mock.
+type dumpClient struct {
+	*cache.MemoryRedis
+	*cache.MockDumperItf
+}
+
+// decode returns the entries written by Export.
+func decode(t *testing.T, data []byte) []Entry {
+	res := make([]Entry, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var entry Entry
+		require.NoError(t, decoder.Decode(&entry))
+		res = append(res, entry)
+	}
+
+	return res
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    func(m *cache.MockDumperItf)
+		want    Progress
+		wantErr errorx.Code
+		entries []Entry
+	}{
+		{
+			name: "Matching keys",
+			mock: func(m *cache.MockDumperItf) {
+				m.EXPECT().Dump(gomock.Any(), "order:1").Return([]byte("first"), time.Minute, nil)
+				m.EXPECT().Dump(gomock.Any(), "order:2").Return([]byte("second"), time.Duration(0), nil)
+				m.EXPECT().Dump(gomock.Any(), "order:3").Return([]byte("third"), time.Second, nil)
+			},
+			want: Progress{Scanned: 3, Processed: 3},
+			entries: []Entry{
+				{Key: "order:1", TTL: 60000, Value: []byte("first")},
+				{Key: "order:2", TTL: 0, Value: []byte("second")},
+				{Key: "order:3", TTL: 1000, Value: []byte("third")},
+			},
+		},
+		{
+			name: "Missing and failed keys",
+			mock: func(m *cache.MockDumperItf) {
+				m.EXPECT().Dump(gomock.Any(), "order:1").Return(nil, time.Duration(0), errorx.E("missing", errorx.CodeNotFound))
+				m.EXPECT().Dump(gomock.Any(), "order:2").Return(nil, time.Duration(0), errors.New("failed"))
+				m.EXPECT().Dump(gomock.Any(), "order:3").Return([]byte("third"), time.Duration(0), nil)
+			},
+			want: Progress{Scanned: 3, Processed: 1, Skipped: 1, Failed: 1},
+			entries: []Entry{
+				{Key: "order:3", TTL: 0, Value: []byte("third")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := cache.NewMockDumperItf(ctrl)
+			tt.mock(m)
+
+			var buf bytes.Buffer
+			got, err := Export(context.Background(), dumpClient{newClient(t), m}, Config{Match: "order:*"}, &buf)
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, errorx.GetCode(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.ElementsMatch(t, tt.entries, decode(t, buf.Bytes()))
+		})
+	}
+}
+
+func TestExport_NotDumper(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Export(context.Background(), newClient(t), Config{Match: "*"}, &buf)
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+}
+
+func TestExportFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := cache.NewMockDumperItf(ctrl)
+	m.EXPECT().Dump(gomock.Any(), "user:1").Return([]byte("value"), time.Duration(0), nil)
+
+	path := filepath.Join(t.TempDir(), "export.jsonl")
+	got, err := ExportFile(context.Background(), dumpClient{newClient(t), m}, Config{Match: "user:*"}, path)
+	assert.NoError(t, err)
+	assert.Equal(t, Progress{Scanned: 1, Processed: 1}, got)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{{Key: "user:1", TTL: 0, Value: []byte("value")}}, decode(t, data))
+}
@@ -8,33 +8,198 @@ import (
 //go:generate mockgen -destination=cache_mock.go -package=cache -source=cache.go
 
 // RedisItf is a client to interact with Redis.
+// Every client implements the same commands, so the backend can be switched without breaking the callers.
 type RedisItf interface {
 	// Get gets the value from redis in []byte form.
+	// Missing key is reported as an error with errorx.CodeNotFound.
 	Get(ctx context.Context, key string) ([]byte, error)
+	// SimpleSet sets value to key in redis without any additional options.
+	// Key doesn't have a TTL.
+	SimpleSet(ctx context.Context, key, value string) error
 	// SetEX sets the value to a key with timeout in seconds.
 	SetEX(ctx context.Context, key string, seconds int64, value string) error
+	// SetNX sets a value to a key with specified timeouts.
+	// SetNX returns false if the key exists.
+	SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error)
 	// Exists checks whether the key exists in redis.
 	Exists(ctx context.Context, key string) (bool, error)
 	// Expire sets the TTL of a key to specified value in seconds.
 	Expire(ctx context.Context, key string, seconds int64) (bool, error)
-	// TTL gets the time to live of a key / expiry time.
+	// ExpireAt sets the TTL of a key to a certain unix timestamp in seconds.
+	ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error)
+	// TTL gets the time to live of a key / expiry time in seconds.
+	// It returns -2 if the key does not exist and -1 if the key has no expiry.
 	TTL(ctx context.Context, key string) (int64, error)
+	// Del deletes a key.
+	Del(ctx context.Context, key ...interface{}) (int64, error)
+
+	// Incr increments the integer value of a key by 1.
+	Incr(ctx context.Context, key string) (int64, error)
+	// Decr decrements the integer value of a key by 1.
+	Decr(ctx context.Context, key string) (int64, error)
+	// IncrBy increments the integer value of a key by the given amount.
+	IncrBy(ctx context.Context, key string, by int64) (int64, error)
+	// IncrByEx increments the integer value of a key by the given amount,
+	// and sets the TTL of the key to specified value in seconds.
+	IncrByEx(ctx context.Context, key string, by int64, expires int64) (int64, error)
+
 	// HGet gets the value of a hash field.
+	// Missing key or field is reported as an error with errorx.CodeNotFound.
 	HGet(ctx context.Context, key, field string) ([]byte, error)
+	// HMGet gets the values of multiple hash fields in the order of the fields.
+	// Missing field is returned as a nil value.
+	HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error)
+	// HGetAll gets all the fields and values in a hash.
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	// HKeys gets all the fields in a hash.
+	HKeys(ctx context.Context, key string) ([]string, error)
 	// HExists determines if a hash field exists.
 	HExists(ctx context.Context, key, field string) (bool, error)
 	// HSet sets the string value of a hash field.
 	HSet(ctx context.Context, key, field, value string) (bool, error)
-	// Del deletes a key.
-	Del(ctx context.Context, key ...interface{}) (int64, error)
+	// HDel deletes hash fields and returns the number of deleted fields.
+	HDel(ctx context.Context, key string, fields ...string) (int64, error)
+
+	// LPush prepends the values to a list and returns the length of the list.
+	LPush(ctx context.Context, key string, values ...string) (int64, error)
+	// RPush appends the values to a list and returns the length of the list.
+	RPush(ctx context.Context, key string, values ...string) (int64, error)
+	// LRange gets the elements of a list between index start and stop.
+	LRange(ctx context.Context, key string, start, stop int64) ([][]byte, error)
+	// LTrim trims a list to the elements between index start and stop.
+	LTrim(ctx context.Context, key string, start, stop int64) error
+	// LLen gets the length of a list.
+	LLen(ctx context.Context, key string) (int64, error)
+
+	// SAdd add the specified members to the set stored at key.
+	// It returns false if key and value combination exists.
+	SAdd(ctx context.Context, key string, value ...string) (bool, error)
+	// SRem removes the members from a set and returns the number of removed members.
+	SRem(ctx context.Context, key string, value ...string) (int64, error)
+	// SMembers gets all the members of a set.
+	SMembers(ctx context.Context, key string) ([]string, error)
+	// SIsMember determines if a value is a member of a set.
+	SIsMember(ctx context.Context, key, value string) (bool, error)
+	// SCard gets the number of members of a set.
+	SCard(ctx context.Context, key string) (int64, error)
+
+	// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+	// It returns the number of added members.
+	ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error)
+	// ZIncrBy increments the score of a sorted set member and returns the new score.
+	ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error)
+	// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+	ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error)
+	// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+	ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error)
+	// ZRangeByScore gets the members of a sorted set with a score between min and max,
+	// ordered from the lowest score.
+	// Min and max accept the redis score syntax, e.g. "-inf", "+inf" and "(1" for exclusive bound.
+	// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+	ZRangeByScore(ctx context.Context, key, min, max string, offset, count int64) ([]ZMember, error)
+	// ZRank gets the index of a sorted set member, ordered from the lowest score.
+	// Missing key or member is reported as an error with errorx.CodeNotFound.
+	ZRank(ctx context.Context, key, member string) (int64, error)
+	// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+	// Missing key or member is reported as an error with errorx.CodeNotFound.
+	ZRevRank(ctx context.Context, key, member string) (int64, error)
+	// ZScore gets the score of a sorted set member.
+	// Missing key or member is reported as an error with errorx.CodeNotFound.
+	ZScore(ctx context.Context, key, member string) (float64, error)
+	// ZCard gets the number of members of a sorted set.
+	ZCard(ctx context.Context, key string) (int64, error)
+	// ZRem removes the members from a sorted set and returns the number of removed members.
+	ZRem(ctx context.Context, key string, members ...string) (int64, error)
+	// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+	// and returns the number of removed members.
+	ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error)
+
+	// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+	Publish(ctx context.Context, topic, message string) (int, error)
 	// Close closes the client, releasing any open resources.
 	Close()
 }
 
+// BatcherItf is a client to execute multiple commands in a single round trip.
+// Results are returned in the order of the input, and every key reports its own error.
+type BatcherItf interface {
+	// MGet gets the values of the keys.
+	// Missing key is reported as an error with errorx.CodeNotFound.
+	MGet(ctx context.Context, keys ...string) ([]BatchResult, error)
+	// MSetEX sets the values of the keys with their timeout in seconds.
+	MSetEX(ctx context.Context, items ...BatchItem) ([]BatchResult, error)
+	// MDel deletes the keys.
+	// Missing key is reported as an error with errorx.CodeNotFound.
+	MDel(ctx context.Context, keys ...string) ([]BatchResult, error)
+	// Pipeline returns a pipeline to queue arbitrary commands.
+	Pipeline() *Pipeline
+}
+
+// ScripterItf is a client to run Lua scripts.
+type ScripterItf interface {
+	// RunScript runs the script with EVALSHA, and loads the script when redis replies NOSCRIPT.
+	// All the keys must share the same hash slot, see HashTag.
+	RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error)
+	// LoadScripts loads the scripts into the script cache ahead of their first run.
+	LoadScripts(ctx context.Context, scripts ...*Script) error
+}
+
+// StreamerItf is a client to use redis streams as a durable queue with consumer groups.
+type StreamerItf interface {
+	// XAdd appends a message to a stream and returns the generated id.
+	// The stream is trimmed to approximately maxLen messages, zero value means the stream isn't trimmed.
+	XAdd(ctx context.Context, stream string, maxLen int64, values map[string]string) (string, error)
+	// XGroupCreate creates a consumer group reading the stream from the start id, e.g. "0" or "$".
+	// The stream is created if it doesn't exist, and the existing group is left as is.
+	XGroupCreate(ctx context.Context, stream, group, start string) error
+	// XReadGroup reads up to count new messages for a consumer of a group,
+	// waiting up to block for the messages to arrive, zero value doesn't wait.
+	// The messages are pending until they are acknowledged.
+	XReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]StreamMessage, error)
+	// XAck acknowledges the messages and returns the number of acknowledged messages.
+	XAck(ctx context.Context, stream, group string, ids ...string) (int64, error)
+	// XPending returns up to count pending messages of a group, the oldest first.
+	XPending(ctx context.Context, stream, group string, count int64) ([]PendingMessage, error)
+	// XClaim transfers the pending messages idle for at least minIdle to a consumer,
+	// and returns the claimed messages.
+	// The message deleted from the stream is returned with nil values.
+	XClaim(ctx context.Context, stream, group, consumer string, minIdle time.Duration, ids ...string) ([]StreamMessage, error)
+}
+
+// DumperItf is a client to copy keys between redis instances, whatever the type of the keys.
+// The destination instance must run the same or a newer redis version than the origin.
+type DumperItf interface {
+	// Dump returns the serialized value of a key with its remaining ttl,
+	// zero ttl means the key has no expiry.
+	// Missing key is reported as an error with errorx.CodeNotFound.
+	Dump(ctx context.Context, key string) ([]byte, time.Duration, error)
+	// Restore creates a key from its serialized value with the ttl, zero ttl means no expiry.
+	// Existing key is reported as an error with errorx.CodeConflict, unless it is replaced.
+	Restore(ctx context.Context, key string, ttl time.Duration, value []byte, replace bool) error
+}
+
+// ScannerItf is a client to iterate the keys without blocking redis.
+// Empty match matches everything, and non-positive count uses the default batch size of redis.
+type ScannerItf interface {
+	// ScanKeys iterates the keys matching the pattern with SCAN, count keys at a time,
+	// and calls fn with every batch of keys until fn returns an error.
+	// The cluster clients scan every master.
+	// A key may be seen more than once, and the keys added or deleted during the scan may be missed.
+	ScanKeys(ctx context.Context, match string, count int64, fn func(keys []string) error) error
+	// HScan iterates the fields of a hash matching the pattern with HSCAN, count fields at a time,
+	// and calls fn with every batch of fields and their values until fn returns an error.
+	HScan(ctx context.Context, key, match string, count int64, fn func(fields map[string]string) error) error
+	// SScan iterates the members of a set matching the pattern with SSCAN, count members at a time,
+	// and calls fn with every batch of members until fn returns an error.
+	SScan(ctx context.Context, key, match string, count int64, fn func(members []string) error) error
+}
+
 // RistrettoItf is a in-process or local cache storage client.
 type RistrettoItf interface {
 	// Get returns the value (if any) and a boolean representing whether the value was found or not.
 	Get(ctx context.Context, key string) (res interface{}, exists bool)
+	// Lookup works like Get but reports the missing key as an error with errorx.CodeNotFound.
+	Lookup(ctx context.Context, key string) (interface{}, error)
 	// Set attempts to add the key-value item to the cache.
 	// If it returns false, then the Set was dropped and the key-value item isn't added to the cache.
 	// If it returns true, there's still a chance it could be dropped by the policy
@@ -46,6 +211,17 @@ type RistrettoItf interface {
 	// A zero value means the value never expires, which is identical to calling Set.
 	// A negative value is a no-op and the value is discarded.
 	SetEX(ctx context.Context, key string, value interface{}, TTL time.Duration) bool
+	// SetWithCost works like Set but adds the item with the given cost, e.g. its size in bytes.
+	// The items are evicted once the sum of their costs reaches MaxCost.
+	SetWithCost(ctx context.Context, key string, value interface{}, cost int64) bool
+	// SetEXWithCost works like SetEX but adds the item with the given cost.
+	SetEXWithCost(ctx context.Context, key string, value interface{}, cost int64, TTL time.Duration) bool
+	// GetTTL returns the remaining time to live of a key, zero when the key never expires,
+	// and a boolean representing whether the key was found or not.
+	GetTTL(ctx context.Context, key string) (time.Duration, bool)
+	// Wait blocks until the pending sets are applied, so they are visible to Get.
+	// The sets are buffered and applied asynchronously, it is mostly useful in tests.
+	Wait()
 	// Del deletes the key-value item from the cache if it exists.
 	Del(ctx context.Context, key string)
 	// Clear empties the cache store and zeroes all policy counters.
@@ -53,3 +229,63 @@ type RistrettoItf interface {
 	// Close stops all goroutines and closes all channels.
 	Close()
 }
+
+// PubSubItf is a client to publish and subscribe messages through Redis channels.
+type PubSubItf interface {
+	// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+	Publish(ctx context.Context, topic, message string) (int, error)
+	// Subscribe subscribes to the channels and dispatches the received messages to the handler.
+	// It blocks until the context is done, the broken subscription is reconnected with backoff.
+	// The handler error and panic are logged, and don't stop the subscription.
+	Subscribe(ctx context.Context, handler MessageHandler, channels ...string) error
+	// PSubscribe subscribes to the channels matching the patterns, e.g. "news.*",
+	// and dispatches the received messages to the handler.
+	// It blocks until the context is done, the broken subscription is reconnected with backoff.
+	// The handler error and panic are logged, and don't stop the subscription.
+	PSubscribe(ctx context.Context, handler MessageHandler, patterns ...string) error
+}
+
+// LockerItf is a client to prevent a key from being loaded by multiple processes at the same time.
+type LockerItf interface {
+	// SetNX sets a value to a key with specified timeouts.
+	// SetNX returns false if the key exists.
+	SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error)
+	// RunScript runs the script with EVALSHA, and loads the script when redis replies NOSCRIPT.
+	// The lock is released with ScriptLockRelease, so only the owner of the token deletes it.
+	RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// TaggerItf is a client keeping the tag sets and the namespace versions of Client.
+type TaggerItf interface {
+	// Get gets the value from redis in []byte form.
+	// Missing key is reported as an error with errorx.CodeNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Incr increments the integer value of a key by 1.
+	Incr(ctx context.Context, key string) (int64, error)
+	// SAdd add the specified members to the set stored at key.
+	SAdd(ctx context.Context, key string, value ...string) (bool, error)
+	// SMembers gets all the members of a set.
+	SMembers(ctx context.Context, key string) ([]string, error)
+	// Expire sets the TTL of a key to specified value in seconds.
+	Expire(ctx context.Context, key string, seconds int64) (bool, error)
+	// Del deletes a key.
+	Del(ctx context.Context, key ...interface{}) (int64, error)
+}
+
+// StatsReporterItf is an instrumented client reporting its stats.
+type StatsReporterItf interface {
+	// Stats returns a snapshot of the stats of the client.
+	Stats() CacheStats
+}
+
+// PoolReporterItf is a client reporting the stats of its connection pool.
+type PoolReporterItf interface {
+	// PoolStats returns a snapshot of the stats of the connection pool.
+	PoolStats() PoolStats
+}
+
+// RistrettoMetricsItf is an in-process cache client reporting its metrics.
+type RistrettoMetricsItf interface {
+	// Metrics returns a snapshot of the metrics of the cache, nil when the metrics are disabled.
+	Metrics() *RistrettoMetrics
+}
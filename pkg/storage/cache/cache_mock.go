@@ -49,6 +49,20 @@ func (mr *MockRedisItfMockRecorder) Get(ctx, key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRedisItf)(nil).Get), ctx, key)
 }
 
+// SimpleSet mocks base method
+func (m *MockRedisItf) SimpleSet(ctx context.Context, key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimpleSet", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SimpleSet indicates an expected call of SimpleSet
+func (mr *MockRedisItfMockRecorder) SimpleSet(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimpleSet", reflect.TypeOf((*MockRedisItf)(nil).SimpleSet), ctx, key, value)
+}
+
 // SetEX mocks base method
 func (m *MockRedisItf) SetEX(ctx context.Context, key string, seconds int64, value string) error {
 	m.ctrl.T.Helper()
@@ -63,6 +77,21 @@ func (mr *MockRedisItfMockRecorder) SetEX(ctx, key, seconds, value interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEX", reflect.TypeOf((*MockRedisItf)(nil).SetEX), ctx, key, seconds, value)
 }
 
+// SetNX mocks base method
+func (m *MockRedisItf) SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, seconds, value)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX
+func (mr *MockRedisItfMockRecorder) SetNX(ctx, key, seconds, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockRedisItf)(nil).SetNX), ctx, key, seconds, value)
+}
+
 // Exists mocks base method
 func (m *MockRedisItf) Exists(ctx context.Context, key string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +122,21 @@ func (mr *MockRedisItfMockRecorder) Expire(ctx, key, seconds interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockRedisItf)(nil).Expire), ctx, key, seconds)
 }
 
+// ExpireAt mocks base method
+func (m *MockRedisItf) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireAt", ctx, key, timestamp)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireAt indicates an expected call of ExpireAt
+func (mr *MockRedisItfMockRecorder) ExpireAt(ctx, key, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAt", reflect.TypeOf((*MockRedisItf)(nil).ExpireAt), ctx, key, timestamp)
+}
+
 // TTL mocks base method
 func (m *MockRedisItf) TTL(ctx context.Context, key string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +152,86 @@ func (mr *MockRedisItfMockRecorder) TTL(ctx, key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockRedisItf)(nil).TTL), ctx, key)
 }
 
+// Del mocks base method
+func (m *MockRedisItf) Del(ctx context.Context, key ...interface{}) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range key {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Del", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Del indicates an expected call of Del
+func (mr *MockRedisItfMockRecorder) Del(ctx interface{}, key ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, key...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockRedisItf)(nil).Del), varargs...)
+}
+
+// Incr mocks base method
+func (m *MockRedisItf) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr
+func (mr *MockRedisItfMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockRedisItf)(nil).Incr), ctx, key)
+}
+
+// Decr mocks base method
+func (m *MockRedisItf) Decr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decr indicates an expected call of Decr
+func (mr *MockRedisItfMockRecorder) Decr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decr", reflect.TypeOf((*MockRedisItf)(nil).Decr), ctx, key)
+}
+
+// IncrBy mocks base method
+func (m *MockRedisItf) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", ctx, key, by)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy
+func (mr *MockRedisItfMockRecorder) IncrBy(ctx, key, by interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockRedisItf)(nil).IncrBy), ctx, key, by)
+}
+
+// IncrByEx mocks base method
+func (m *MockRedisItf) IncrByEx(ctx context.Context, key string, by, expires int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrByEx", ctx, key, by, expires)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrByEx indicates an expected call of IncrByEx
+func (mr *MockRedisItfMockRecorder) IncrByEx(ctx, key, by, expires interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrByEx", reflect.TypeOf((*MockRedisItf)(nil).IncrByEx), ctx, key, by, expires)
+}
+
 // HGet mocks base method
 func (m *MockRedisItf) HGet(ctx context.Context, key, field string) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +247,56 @@ func (mr *MockRedisItfMockRecorder) HGet(ctx, key, field interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockRedisItf)(nil).HGet), ctx, key, field)
 }
 
+// HMGet mocks base method
+func (m *MockRedisItf) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HMGet", varargs...)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HMGet indicates an expected call of HMGet
+func (mr *MockRedisItfMockRecorder) HMGet(ctx, key interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HMGet", reflect.TypeOf((*MockRedisItf)(nil).HMGet), varargs...)
+}
+
+// HGetAll mocks base method
+func (m *MockRedisItf) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll
+func (mr *MockRedisItfMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockRedisItf)(nil).HGetAll), ctx, key)
+}
+
+// HKeys mocks base method
+func (m *MockRedisItf) HKeys(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HKeys", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HKeys indicates an expected call of HKeys
+func (mr *MockRedisItfMockRecorder) HKeys(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HKeys", reflect.TypeOf((*MockRedisItf)(nil).HKeys), ctx, key)
+}
+
 // HExists mocks base method
 func (m *MockRedisItf) HExists(ctx context.Context, key, field string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -153,136 +327,1332 @@ func (mr *MockRedisItfMockRecorder) HSet(ctx, key, field, value interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockRedisItf)(nil).HSet), ctx, key, field, value)
 }
 
-// Del mocks base method
-func (m *MockRedisItf) Del(ctx context.Context, key ...interface{}) (int64, error) {
+// HDel mocks base method
+func (m *MockRedisItf) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx}
-	for _, a := range key {
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Del", varargs...)
+	ret := m.ctrl.Call(m, "HDel", varargs...)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Del indicates an expected call of Del
-func (mr *MockRedisItfMockRecorder) Del(ctx interface{}, key ...interface{}) *gomock.Call {
+// HDel indicates an expected call of HDel
+func (mr *MockRedisItfMockRecorder) HDel(ctx, key interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx}, key...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockRedisItf)(nil).Del), varargs...)
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockRedisItf)(nil).HDel), varargs...)
 }
 
-// Close mocks base method
-func (m *MockRedisItf) Close() {
+// LPush mocks base method
+func (m *MockRedisItf) LPush(ctx context.Context, key string, values ...string) (int64, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Close")
+	varargs := []interface{}{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LPush", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Close indicates an expected call of Close
-func (mr *MockRedisItfMockRecorder) Close() *gomock.Call {
+// LPush indicates an expected call of LPush
+func (mr *MockRedisItfMockRecorder) LPush(ctx, key interface{}, values ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRedisItf)(nil).Close))
+	varargs := append([]interface{}{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockRedisItf)(nil).LPush), varargs...)
 }
 
-// MockRistrettoItf is a mock of RistrettoItf interface
-type MockRistrettoItf struct {
-	ctrl     *gomock.Controller
-	recorder *MockRistrettoItfMockRecorder
+// RPush mocks base method
+func (m *MockRedisItf) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RPush", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockRistrettoItfMockRecorder is the mock recorder for MockRistrettoItf
-type MockRistrettoItfMockRecorder struct {
-	mock *MockRistrettoItf
+// RPush indicates an expected call of RPush
+func (mr *MockRedisItfMockRecorder) RPush(ctx, key interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPush", reflect.TypeOf((*MockRedisItf)(nil).RPush), varargs...)
 }
 
-// NewMockRistrettoItf creates a new mock instance
-func NewMockRistrettoItf(ctrl *gomock.Controller) *MockRistrettoItf {
-	mock := &MockRistrettoItf{ctrl: ctrl}
-	mock.recorder = &MockRistrettoItfMockRecorder{mock}
-	return mock
+// LRange mocks base method
+func (m *MockRedisItf) LRange(ctx context.Context, key string, start, stop int64) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LRange", ctx, key, start, stop)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockRistrettoItf) EXPECT() *MockRistrettoItfMockRecorder {
-	return m.recorder
+// LRange indicates an expected call of LRange
+func (mr *MockRedisItfMockRecorder) LRange(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LRange", reflect.TypeOf((*MockRedisItf)(nil).LRange), ctx, key, start, stop)
 }
 
-// Get mocks base method
-func (m *MockRistrettoItf) Get(ctx context.Context, key string) (interface{}, bool) {
+// LTrim mocks base method
+func (m *MockRedisItf) LTrim(ctx context.Context, key string, start, stop int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, key)
-	ret0, _ := ret[0].(interface{})
-	ret1, _ := ret[1].(bool)
+	ret := m.ctrl.Call(m, "LTrim", ctx, key, start, stop)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LTrim indicates an expected call of LTrim
+func (mr *MockRedisItfMockRecorder) LTrim(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LTrim", reflect.TypeOf((*MockRedisItf)(nil).LTrim), ctx, key, start, stop)
+}
+
+// LLen mocks base method
+func (m *MockRedisItf) LLen(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LLen", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get
-func (mr *MockRistrettoItfMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+// LLen indicates an expected call of LLen
+func (mr *MockRedisItfMockRecorder) LLen(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRistrettoItf)(nil).Get), ctx, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LLen", reflect.TypeOf((*MockRedisItf)(nil).LLen), ctx, key)
 }
 
-// Set mocks base method
-func (m *MockRistrettoItf) Set(ctx context.Context, key string, value interface{}) bool {
+// SAdd mocks base method
+func (m *MockRedisItf) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", ctx, key, value)
+	varargs := []interface{}{ctx, key}
+	for _, a := range value {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SAdd", varargs...)
 	ret0, _ := ret[0].(bool)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Set indicates an expected call of Set
-func (mr *MockRistrettoItfMockRecorder) Set(ctx, key, value interface{}) *gomock.Call {
+// SAdd indicates an expected call of SAdd
+func (mr *MockRedisItfMockRecorder) SAdd(ctx, key interface{}, value ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockRistrettoItf)(nil).Set), ctx, key, value)
+	varargs := append([]interface{}{ctx, key}, value...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockRedisItf)(nil).SAdd), varargs...)
 }
 
-// SetEX mocks base method
-func (m *MockRistrettoItf) SetEX(ctx context.Context, key string, value interface{}, TTL time.Duration) bool {
+// SRem mocks base method
+func (m *MockRedisItf) SRem(ctx context.Context, key string, value ...string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetEX", ctx, key, value, TTL)
+	varargs := []interface{}{ctx, key}
+	for _, a := range value {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SRem indicates an expected call of SRem
+func (mr *MockRedisItfMockRecorder) SRem(ctx, key interface{}, value ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, value...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SRem", reflect.TypeOf((*MockRedisItf)(nil).SRem), varargs...)
+}
+
+// SMembers mocks base method
+func (m *MockRedisItf) SMembers(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers
+func (mr *MockRedisItfMockRecorder) SMembers(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockRedisItf)(nil).SMembers), ctx, key)
+}
+
+// SIsMember mocks base method
+func (m *MockRedisItf) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SIsMember", ctx, key, value)
 	ret0, _ := ret[0].(bool)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// SetEX indicates an expected call of SetEX
-func (mr *MockRistrettoItfMockRecorder) SetEX(ctx, key, value, TTL interface{}) *gomock.Call {
+// SIsMember indicates an expected call of SIsMember
+func (mr *MockRedisItfMockRecorder) SIsMember(ctx, key, value interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEX", reflect.TypeOf((*MockRistrettoItf)(nil).SetEX), ctx, key, value, TTL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SIsMember", reflect.TypeOf((*MockRedisItf)(nil).SIsMember), ctx, key, value)
 }
 
-// Del mocks base method
-func (m *MockRistrettoItf) Del(ctx context.Context, key string) {
+// SCard mocks base method
+func (m *MockRedisItf) SCard(ctx context.Context, key string) (int64, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Del", ctx, key)
+	ret := m.ctrl.Call(m, "SCard", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Del indicates an expected call of Del
-func (mr *MockRistrettoItfMockRecorder) Del(ctx, key interface{}) *gomock.Call {
+// SCard indicates an expected call of SCard
+func (mr *MockRedisItfMockRecorder) SCard(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockRistrettoItf)(nil).Del), ctx, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SCard", reflect.TypeOf((*MockRedisItf)(nil).SCard), ctx, key)
 }
 
-// Clear mocks base method
-func (m *MockRistrettoItf) Clear(ctx context.Context) {
+// ZAdd mocks base method
+func (m *MockRedisItf) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Clear", ctx)
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Clear indicates an expected call of Clear
-func (mr *MockRistrettoItfMockRecorder) Clear(ctx interface{}) *gomock.Call {
+// ZAdd indicates an expected call of ZAdd
+func (mr *MockRedisItfMockRecorder) ZAdd(ctx, key interface{}, members ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockRistrettoItf)(nil).Clear), ctx)
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockRedisItf)(nil).ZAdd), varargs...)
 }
 
-// Close mocks base method
-func (m *MockRistrettoItf) Close() {
+// ZIncrBy mocks base method
+func (m *MockRedisItf) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Close")
+	ret := m.ctrl.Call(m, "ZIncrBy", ctx, key, increment, member)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Close indicates an expected call of Close
-func (mr *MockRistrettoItfMockRecorder) Close() *gomock.Call {
+// ZIncrBy indicates an expected call of ZIncrBy
+func (mr *MockRedisItfMockRecorder) ZIncrBy(ctx, key, increment, member interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRistrettoItf)(nil).Close))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZIncrBy", reflect.TypeOf((*MockRedisItf)(nil).ZIncrBy), ctx, key, increment, member)
+}
+
+// ZRange mocks base method
+func (m *MockRedisItf) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRange", ctx, key, start, stop)
+	ret0, _ := ret[0].([]ZMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRange indicates an expected call of ZRange
+func (mr *MockRedisItfMockRecorder) ZRange(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRange", reflect.TypeOf((*MockRedisItf)(nil).ZRange), ctx, key, start, stop)
+}
+
+// ZRevRange mocks base method
+func (m *MockRedisItf) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRevRange", ctx, key, start, stop)
+	ret0, _ := ret[0].([]ZMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRevRange indicates an expected call of ZRevRange
+func (mr *MockRedisItfMockRecorder) ZRevRange(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRevRange", reflect.TypeOf((*MockRedisItf)(nil).ZRevRange), ctx, key, start, stop)
+}
+
+// ZRangeByScore mocks base method
+func (m *MockRedisItf) ZRangeByScore(ctx context.Context, key, min, max string, offset, count int64) ([]ZMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, min, max, offset, count)
+	ret0, _ := ret[0].([]ZMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore
+func (mr *MockRedisItfMockRecorder) ZRangeByScore(ctx, key, min, max, offset, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockRedisItf)(nil).ZRangeByScore), ctx, key, min, max, offset, count)
+}
+
+// ZRank mocks base method
+func (m *MockRedisItf) ZRank(ctx context.Context, key, member string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRank", ctx, key, member)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRank indicates an expected call of ZRank
+func (mr *MockRedisItfMockRecorder) ZRank(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRank", reflect.TypeOf((*MockRedisItf)(nil).ZRank), ctx, key, member)
+}
+
+// ZRevRank mocks base method
+func (m *MockRedisItf) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRevRank", ctx, key, member)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRevRank indicates an expected call of ZRevRank
+func (mr *MockRedisItfMockRecorder) ZRevRank(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRevRank", reflect.TypeOf((*MockRedisItf)(nil).ZRevRank), ctx, key, member)
+}
+
+// ZScore mocks base method
+func (m *MockRedisItf) ZScore(ctx context.Context, key, member string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZScore", ctx, key, member)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZScore indicates an expected call of ZScore
+func (mr *MockRedisItfMockRecorder) ZScore(ctx, key, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZScore", reflect.TypeOf((*MockRedisItf)(nil).ZScore), ctx, key, member)
+}
+
+// ZCard mocks base method
+func (m *MockRedisItf) ZCard(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZCard", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZCard indicates an expected call of ZCard
+func (mr *MockRedisItfMockRecorder) ZCard(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZCard", reflect.TypeOf((*MockRedisItf)(nil).ZCard), ctx, key)
+}
+
+// ZRem mocks base method
+func (m *MockRedisItf) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRem indicates an expected call of ZRem
+func (mr *MockRedisItfMockRecorder) ZRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockRedisItf)(nil).ZRem), varargs...)
+}
+
+// ZRemRangeByScore mocks base method
+func (m *MockRedisItf) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRemRangeByScore", ctx, key, min, max)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRemRangeByScore indicates an expected call of ZRemRangeByScore
+func (mr *MockRedisItfMockRecorder) ZRemRangeByScore(ctx, key, min, max interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRemRangeByScore", reflect.TypeOf((*MockRedisItf)(nil).ZRemRangeByScore), ctx, key, min, max)
+}
+
+// Publish mocks base method
+func (m *MockRedisItf) Publish(ctx context.Context, topic, message string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, topic, message)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish
+func (mr *MockRedisItfMockRecorder) Publish(ctx, topic, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRedisItf)(nil).Publish), ctx, topic, message)
+}
+
+// Close mocks base method
+func (m *MockRedisItf) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockRedisItfMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRedisItf)(nil).Close))
+}
+
+// MockBatcherItf is a mock of BatcherItf interface
+type MockBatcherItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatcherItfMockRecorder
+}
+
+// MockBatcherItfMockRecorder is the mock recorder for MockBatcherItf
+type MockBatcherItfMockRecorder struct {
+	mock *MockBatcherItf
+}
+
+// NewMockBatcherItf creates a new mock instance
+func NewMockBatcherItf(ctrl *gomock.Controller) *MockBatcherItf {
+	mock := &MockBatcherItf{ctrl: ctrl}
+	mock.recorder = &MockBatcherItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBatcherItf) EXPECT() *MockBatcherItfMockRecorder {
+	return m.recorder
+}
+
+// MGet mocks base method
+func (m *MockBatcherItf) MGet(ctx context.Context, keys ...string) ([]BatchResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MGet", varargs...)
+	ret0, _ := ret[0].([]BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet
+func (mr *MockBatcherItfMockRecorder) MGet(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockBatcherItf)(nil).MGet), varargs...)
+}
+
+// MSetEX mocks base method
+func (m *MockBatcherItf) MSetEX(ctx context.Context, items ...BatchItem) ([]BatchResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range items {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MSetEX", varargs...)
+	ret0, _ := ret[0].([]BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MSetEX indicates an expected call of MSetEX
+func (mr *MockBatcherItfMockRecorder) MSetEX(ctx interface{}, items ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, items...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSetEX", reflect.TypeOf((*MockBatcherItf)(nil).MSetEX), varargs...)
+}
+
+// MDel mocks base method
+func (m *MockBatcherItf) MDel(ctx context.Context, keys ...string) ([]BatchResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MDel", varargs...)
+	ret0, _ := ret[0].([]BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MDel indicates an expected call of MDel
+func (mr *MockBatcherItfMockRecorder) MDel(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MDel", reflect.TypeOf((*MockBatcherItf)(nil).MDel), varargs...)
+}
+
+// Pipeline mocks base method
+func (m *MockBatcherItf) Pipeline() *Pipeline {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pipeline")
+	ret0, _ := ret[0].(*Pipeline)
+	return ret0
+}
+
+// Pipeline indicates an expected call of Pipeline
+func (mr *MockBatcherItfMockRecorder) Pipeline() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pipeline", reflect.TypeOf((*MockBatcherItf)(nil).Pipeline))
+}
+
+// MockScripterItf is a mock of ScripterItf interface
+type MockScripterItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockScripterItfMockRecorder
+}
+
+// MockScripterItfMockRecorder is the mock recorder for MockScripterItf
+type MockScripterItfMockRecorder struct {
+	mock *MockScripterItf
+}
+
+// NewMockScripterItf creates a new mock instance
+func NewMockScripterItf(ctrl *gomock.Controller) *MockScripterItf {
+	mock := &MockScripterItf{ctrl: ctrl}
+	mock.recorder = &MockScripterItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockScripterItf) EXPECT() *MockScripterItfMockRecorder {
+	return m.recorder
+}
+
+// RunScript mocks base method
+func (m *MockScripterItf) RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, script, keys}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunScript", varargs...)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunScript indicates an expected call of RunScript
+func (mr *MockScripterItfMockRecorder) RunScript(ctx, script, keys interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, script, keys}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunScript", reflect.TypeOf((*MockScripterItf)(nil).RunScript), varargs...)
+}
+
+// LoadScripts mocks base method
+func (m *MockScripterItf) LoadScripts(ctx context.Context, scripts ...*Script) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range scripts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LoadScripts", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadScripts indicates an expected call of LoadScripts
+func (mr *MockScripterItfMockRecorder) LoadScripts(ctx interface{}, scripts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, scripts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadScripts", reflect.TypeOf((*MockScripterItf)(nil).LoadScripts), varargs...)
+}
+
+// MockStreamerItf is a mock of StreamerItf interface
+type MockStreamerItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamerItfMockRecorder
+}
+
+// MockStreamerItfMockRecorder is the mock recorder for MockStreamerItf
+type MockStreamerItfMockRecorder struct {
+	mock *MockStreamerItf
+}
+
+// NewMockStreamerItf creates a new mock instance
+func NewMockStreamerItf(ctrl *gomock.Controller) *MockStreamerItf {
+	mock := &MockStreamerItf{ctrl: ctrl}
+	mock.recorder = &MockStreamerItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStreamerItf) EXPECT() *MockStreamerItfMockRecorder {
+	return m.recorder
+}
+
+// XAdd mocks base method
+func (m *MockStreamerItf) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XAdd", ctx, stream, maxLen, values)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XAdd indicates an expected call of XAdd
+func (mr *MockStreamerItfMockRecorder) XAdd(ctx, stream, maxLen, values interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAdd", reflect.TypeOf((*MockStreamerItf)(nil).XAdd), ctx, stream, maxLen, values)
+}
+
+// XGroupCreate mocks base method
+func (m *MockStreamerItf) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XGroupCreate", ctx, stream, group, start)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// XGroupCreate indicates an expected call of XGroupCreate
+func (mr *MockStreamerItfMockRecorder) XGroupCreate(ctx, stream, group, start interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XGroupCreate", reflect.TypeOf((*MockStreamerItf)(nil).XGroupCreate), ctx, stream, group, start)
+}
+
+// XReadGroup mocks base method
+func (m *MockStreamerItf) XReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]StreamMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XReadGroup", ctx, stream, group, consumer, count, block)
+	ret0, _ := ret[0].([]StreamMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XReadGroup indicates an expected call of XReadGroup
+func (mr *MockStreamerItfMockRecorder) XReadGroup(ctx, stream, group, consumer, count, block interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XReadGroup", reflect.TypeOf((*MockStreamerItf)(nil).XReadGroup), ctx, stream, group, consumer, count, block)
+}
+
+// XAck mocks base method
+func (m *MockStreamerItf) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, stream, group}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "XAck", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XAck indicates an expected call of XAck
+func (mr *MockStreamerItfMockRecorder) XAck(ctx, stream, group interface{}, ids ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, stream, group}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAck", reflect.TypeOf((*MockStreamerItf)(nil).XAck), varargs...)
+}
+
+// XPending mocks base method
+func (m *MockStreamerItf) XPending(ctx context.Context, stream, group string, count int64) ([]PendingMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XPending", ctx, stream, group, count)
+	ret0, _ := ret[0].([]PendingMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XPending indicates an expected call of XPending
+func (mr *MockStreamerItfMockRecorder) XPending(ctx, stream, group, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XPending", reflect.TypeOf((*MockStreamerItf)(nil).XPending), ctx, stream, group, count)
+}
+
+// XClaim mocks base method
+func (m *MockStreamerItf) XClaim(ctx context.Context, stream, group, consumer string, minIdle time.Duration, ids ...string) ([]StreamMessage, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, stream, group, consumer, minIdle}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "XClaim", varargs...)
+	ret0, _ := ret[0].([]StreamMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XClaim indicates an expected call of XClaim
+func (mr *MockStreamerItfMockRecorder) XClaim(ctx, stream, group, consumer, minIdle interface{}, ids ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, stream, group, consumer, minIdle}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XClaim", reflect.TypeOf((*MockStreamerItf)(nil).XClaim), varargs...)
+}
+
+// MockDumperItf is a mock of DumperItf interface
+type MockDumperItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDumperItfMockRecorder
+}
+
+// MockDumperItfMockRecorder is the mock recorder for MockDumperItf
+type MockDumperItfMockRecorder struct {
+	mock *MockDumperItf
+}
+
+// NewMockDumperItf creates a new mock instance
+func NewMockDumperItf(ctrl *gomock.Controller) *MockDumperItf {
+	mock := &MockDumperItf{ctrl: ctrl}
+	mock.recorder = &MockDumperItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDumperItf) EXPECT() *MockDumperItfMockRecorder {
+	return m.recorder
+}
+
+// Dump mocks base method
+func (m *MockDumperItf) Dump(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dump", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Dump indicates an expected call of Dump
+func (mr *MockDumperItfMockRecorder) Dump(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dump", reflect.TypeOf((*MockDumperItf)(nil).Dump), ctx, key)
+}
+
+// Restore mocks base method
+func (m *MockDumperItf) Restore(ctx context.Context, key string, ttl time.Duration, value []byte, replace bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, key, ttl, value, replace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockDumperItfMockRecorder) Restore(ctx, key, ttl, value, replace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockDumperItf)(nil).Restore), ctx, key, ttl, value, replace)
+}
+
+// MockScannerItf is a mock of ScannerItf interface
+type MockScannerItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockScannerItfMockRecorder
+}
+
+// MockScannerItfMockRecorder is the mock recorder for MockScannerItf
+type MockScannerItfMockRecorder struct {
+	mock *MockScannerItf
+}
+
+// NewMockScannerItf creates a new mock instance
+func NewMockScannerItf(ctrl *gomock.Controller) *MockScannerItf {
+	mock := &MockScannerItf{ctrl: ctrl}
+	mock.recorder = &MockScannerItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockScannerItf) EXPECT() *MockScannerItfMockRecorder {
+	return m.recorder
+}
+
+// ScanKeys mocks base method
+func (m *MockScannerItf) ScanKeys(ctx context.Context, match string, count int64, fn func([]string) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanKeys", ctx, match, count, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScanKeys indicates an expected call of ScanKeys
+func (mr *MockScannerItfMockRecorder) ScanKeys(ctx, match, count, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanKeys", reflect.TypeOf((*MockScannerItf)(nil).ScanKeys), ctx, match, count, fn)
+}
+
+// HScan mocks base method
+func (m *MockScannerItf) HScan(ctx context.Context, key, match string, count int64, fn func(map[string]string) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HScan", ctx, key, match, count, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HScan indicates an expected call of HScan
+func (mr *MockScannerItfMockRecorder) HScan(ctx, key, match, count, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HScan", reflect.TypeOf((*MockScannerItf)(nil).HScan), ctx, key, match, count, fn)
+}
+
+// SScan mocks base method
+func (m *MockScannerItf) SScan(ctx context.Context, key, match string, count int64, fn func([]string) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SScan", ctx, key, match, count, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SScan indicates an expected call of SScan
+func (mr *MockScannerItfMockRecorder) SScan(ctx, key, match, count, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SScan", reflect.TypeOf((*MockScannerItf)(nil).SScan), ctx, key, match, count, fn)
+}
+
+// MockRistrettoItf is a mock of RistrettoItf interface
+type MockRistrettoItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockRistrettoItfMockRecorder
+}
+
+// MockRistrettoItfMockRecorder is the mock recorder for MockRistrettoItf
+type MockRistrettoItfMockRecorder struct {
+	mock *MockRistrettoItf
+}
+
+// NewMockRistrettoItf creates a new mock instance
+func NewMockRistrettoItf(ctrl *gomock.Controller) *MockRistrettoItf {
+	mock := &MockRistrettoItf{ctrl: ctrl}
+	mock.recorder = &MockRistrettoItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRistrettoItf) EXPECT() *MockRistrettoItfMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method
+func (m *MockRistrettoItf) Get(ctx context.Context, key string) (interface{}, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockRistrettoItfMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRistrettoItf)(nil).Get), ctx, key)
+}
+
+// Lookup mocks base method
+func (m *MockRistrettoItf) Lookup(ctx context.Context, key string) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, key)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup
+func (mr *MockRistrettoItfMockRecorder) Lookup(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockRistrettoItf)(nil).Lookup), ctx, key)
+}
+
+// Set mocks base method
+func (m *MockRistrettoItf) Set(ctx context.Context, key string, value interface{}) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Set indicates an expected call of Set
+func (mr *MockRistrettoItfMockRecorder) Set(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockRistrettoItf)(nil).Set), ctx, key, value)
+}
+
+// SetEX mocks base method
+func (m *MockRistrettoItf) SetEX(ctx context.Context, key string, value interface{}, TTL time.Duration) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEX", ctx, key, value, TTL)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SetEX indicates an expected call of SetEX
+func (mr *MockRistrettoItfMockRecorder) SetEX(ctx, key, value, TTL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEX", reflect.TypeOf((*MockRistrettoItf)(nil).SetEX), ctx, key, value, TTL)
+}
+
+// SetWithCost mocks base method
+func (m *MockRistrettoItf) SetWithCost(ctx context.Context, key string, value interface{}, cost int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithCost", ctx, key, value, cost)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SetWithCost indicates an expected call of SetWithCost
+func (mr *MockRistrettoItfMockRecorder) SetWithCost(ctx, key, value, cost interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithCost", reflect.TypeOf((*MockRistrettoItf)(nil).SetWithCost), ctx, key, value, cost)
+}
+
+// SetEXWithCost mocks base method
+func (m *MockRistrettoItf) SetEXWithCost(ctx context.Context, key string, value interface{}, cost int64, TTL time.Duration) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEXWithCost", ctx, key, value, cost, TTL)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SetEXWithCost indicates an expected call of SetEXWithCost
+func (mr *MockRistrettoItfMockRecorder) SetEXWithCost(ctx, key, value, cost, TTL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEXWithCost", reflect.TypeOf((*MockRistrettoItf)(nil).SetEXWithCost), ctx, key, value, cost, TTL)
+}
+
+// GetTTL mocks base method
+func (m *MockRistrettoItf) GetTTL(ctx context.Context, key string) (time.Duration, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTTL", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetTTL indicates an expected call of GetTTL
+func (mr *MockRistrettoItfMockRecorder) GetTTL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTTL", reflect.TypeOf((*MockRistrettoItf)(nil).GetTTL), ctx, key)
+}
+
+// Wait mocks base method
+func (m *MockRistrettoItf) Wait() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Wait")
+}
+
+// Wait indicates an expected call of Wait
+func (mr *MockRistrettoItfMockRecorder) Wait() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wait", reflect.TypeOf((*MockRistrettoItf)(nil).Wait))
+}
+
+// Del mocks base method
+func (m *MockRistrettoItf) Del(ctx context.Context, key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Del", ctx, key)
+}
+
+// Del indicates an expected call of Del
+func (mr *MockRistrettoItfMockRecorder) Del(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockRistrettoItf)(nil).Del), ctx, key)
+}
+
+// Clear mocks base method
+func (m *MockRistrettoItf) Clear(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Clear", ctx)
+}
+
+// Clear indicates an expected call of Clear
+func (mr *MockRistrettoItfMockRecorder) Clear(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockRistrettoItf)(nil).Clear), ctx)
+}
+
+// Close mocks base method
+func (m *MockRistrettoItf) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockRistrettoItfMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRistrettoItf)(nil).Close))
+}
+
+// MockPubSubItf is a mock of PubSubItf interface
+type MockPubSubItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockPubSubItfMockRecorder
+}
+
+// MockPubSubItfMockRecorder is the mock recorder for MockPubSubItf
+type MockPubSubItfMockRecorder struct {
+	mock *MockPubSubItf
+}
+
+// NewMockPubSubItf creates a new mock instance
+func NewMockPubSubItf(ctrl *gomock.Controller) *MockPubSubItf {
+	mock := &MockPubSubItf{ctrl: ctrl}
+	mock.recorder = &MockPubSubItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPubSubItf) EXPECT() *MockPubSubItfMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method
+func (m *MockPubSubItf) Publish(ctx context.Context, topic, message string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, topic, message)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish
+func (mr *MockPubSubItfMockRecorder) Publish(ctx, topic, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPubSubItf)(nil).Publish), ctx, topic, message)
+}
+
+// Subscribe mocks base method
+func (m *MockPubSubItf) Subscribe(ctx context.Context, handler MessageHandler, channels ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, handler}
+	for _, a := range channels {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Subscribe", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe
+func (mr *MockPubSubItfMockRecorder) Subscribe(ctx, handler interface{}, channels ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, handler}, channels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockPubSubItf)(nil).Subscribe), varargs...)
+}
+
+// PSubscribe mocks base method
+func (m *MockPubSubItf) PSubscribe(ctx context.Context, handler MessageHandler, patterns ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, handler}
+	for _, a := range patterns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PSubscribe", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PSubscribe indicates an expected call of PSubscribe
+func (mr *MockPubSubItfMockRecorder) PSubscribe(ctx, handler interface{}, patterns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, handler}, patterns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PSubscribe", reflect.TypeOf((*MockPubSubItf)(nil).PSubscribe), varargs...)
+}
+
+// MockLockerItf is a mock of LockerItf interface
+type MockLockerItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockerItfMockRecorder
+}
+
+// MockLockerItfMockRecorder is the mock recorder for MockLockerItf
+type MockLockerItfMockRecorder struct {
+	mock *MockLockerItf
+}
+
+// NewMockLockerItf creates a new mock instance
+func NewMockLockerItf(ctrl *gomock.Controller) *MockLockerItf {
+	mock := &MockLockerItf{ctrl: ctrl}
+	mock.recorder = &MockLockerItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLockerItf) EXPECT() *MockLockerItfMockRecorder {
+	return m.recorder
+}
+
+// SetNX mocks base method
+func (m *MockLockerItf) SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, seconds, value)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX
+func (mr *MockLockerItfMockRecorder) SetNX(ctx, key, seconds, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockLockerItf)(nil).SetNX), ctx, key, seconds, value)
+}
+
+// RunScript mocks base method
+func (m *MockLockerItf) RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, script, keys}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunScript", varargs...)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunScript indicates an expected call of RunScript
+func (mr *MockLockerItfMockRecorder) RunScript(ctx, script, keys interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, script, keys}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunScript", reflect.TypeOf((*MockLockerItf)(nil).RunScript), varargs...)
+}
+
+// MockTaggerItf is a mock of TaggerItf interface
+type MockTaggerItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaggerItfMockRecorder
+}
+
+// MockTaggerItfMockRecorder is the mock recorder for MockTaggerItf
+type MockTaggerItfMockRecorder struct {
+	mock *MockTaggerItf
+}
+
+// NewMockTaggerItf creates a new mock instance
+func NewMockTaggerItf(ctrl *gomock.Controller) *MockTaggerItf {
+	mock := &MockTaggerItf{ctrl: ctrl}
+	mock.recorder = &MockTaggerItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTaggerItf) EXPECT() *MockTaggerItfMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method
+func (m *MockTaggerItf) Get(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockTaggerItfMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTaggerItf)(nil).Get), ctx, key)
+}
+
+// Incr mocks base method
+func (m *MockTaggerItf) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr
+func (mr *MockTaggerItfMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockTaggerItf)(nil).Incr), ctx, key)
+}
+
+// SAdd mocks base method
+func (m *MockTaggerItf) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range value {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SAdd", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SAdd indicates an expected call of SAdd
+func (mr *MockTaggerItfMockRecorder) SAdd(ctx, key interface{}, value ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, value...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SAdd", reflect.TypeOf((*MockTaggerItf)(nil).SAdd), varargs...)
+}
+
+// SMembers mocks base method
+func (m *MockTaggerItf) SMembers(ctx context.Context, key string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMembers", ctx, key)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMembers indicates an expected call of SMembers
+func (mr *MockTaggerItfMockRecorder) SMembers(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMembers", reflect.TypeOf((*MockTaggerItf)(nil).SMembers), ctx, key)
+}
+
+// Expire mocks base method
+func (m *MockTaggerItf) Expire(ctx context.Context, key string, seconds int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, seconds)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire
+func (mr *MockTaggerItfMockRecorder) Expire(ctx, key, seconds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockTaggerItf)(nil).Expire), ctx, key, seconds)
+}
+
+// Del mocks base method
+func (m *MockTaggerItf) Del(ctx context.Context, key ...interface{}) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range key {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Del", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Del indicates an expected call of Del
+func (mr *MockTaggerItfMockRecorder) Del(ctx interface{}, key ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, key...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockTaggerItf)(nil).Del), varargs...)
+}
+
+// MockStatsReporterItf is a mock of StatsReporterItf interface
+type MockStatsReporterItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsReporterItfMockRecorder
+}
+
+// MockStatsReporterItfMockRecorder is the mock recorder for MockStatsReporterItf
+type MockStatsReporterItfMockRecorder struct {
+	mock *MockStatsReporterItf
+}
+
+// NewMockStatsReporterItf creates a new mock instance
+func NewMockStatsReporterItf(ctrl *gomock.Controller) *MockStatsReporterItf {
+	mock := &MockStatsReporterItf{ctrl: ctrl}
+	mock.recorder = &MockStatsReporterItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStatsReporterItf) EXPECT() *MockStatsReporterItfMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method
+func (m *MockStatsReporterItf) Stats() CacheStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(CacheStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats
+func (mr *MockStatsReporterItfMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockStatsReporterItf)(nil).Stats))
+}
+
+// MockPoolReporterItf is a mock of PoolReporterItf interface
+type MockPoolReporterItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockPoolReporterItfMockRecorder
+}
+
+// MockPoolReporterItfMockRecorder is the mock recorder for MockPoolReporterItf
+type MockPoolReporterItfMockRecorder struct {
+	mock *MockPoolReporterItf
+}
+
+// NewMockPoolReporterItf creates a new mock instance
+func NewMockPoolReporterItf(ctrl *gomock.Controller) *MockPoolReporterItf {
+	mock := &MockPoolReporterItf{ctrl: ctrl}
+	mock.recorder = &MockPoolReporterItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPoolReporterItf) EXPECT() *MockPoolReporterItfMockRecorder {
+	return m.recorder
+}
+
+// PoolStats mocks base method
+func (m *MockPoolReporterItf) PoolStats() PoolStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PoolStats")
+	ret0, _ := ret[0].(PoolStats)
+	return ret0
+}
+
+// PoolStats indicates an expected call of PoolStats
+func (mr *MockPoolReporterItfMockRecorder) PoolStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PoolStats", reflect.TypeOf((*MockPoolReporterItf)(nil).PoolStats))
+}
+
+// MockRistrettoMetricsItf is a mock of RistrettoMetricsItf interface
+type MockRistrettoMetricsItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockRistrettoMetricsItfMockRecorder
+}
+
+// MockRistrettoMetricsItfMockRecorder is the mock recorder for MockRistrettoMetricsItf
+type MockRistrettoMetricsItfMockRecorder struct {
+	mock *MockRistrettoMetricsItf
+}
+
+// NewMockRistrettoMetricsItf creates a new mock instance
+func NewMockRistrettoMetricsItf(ctrl *gomock.Controller) *MockRistrettoMetricsItf {
+	mock := &MockRistrettoMetricsItf{ctrl: ctrl}
+	mock.recorder = &MockRistrettoMetricsItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRistrettoMetricsItf) EXPECT() *MockRistrettoMetricsItfMockRecorder {
+	return m.recorder
+}
+
+// Metrics mocks base method
+func (m *MockRistrettoMetricsItf) Metrics() *RistrettoMetrics {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metrics")
+	ret0, _ := ret[0].(*RistrettoMetrics)
+	return ret0
+}
+
+// Metrics indicates an expected call of Metrics
+func (mr *MockRistrettoMetricsItfMockRecorder) Metrics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metrics", reflect.TypeOf((*MockRistrettoMetricsItf)(nil).Metrics))
 }
@@ -0,0 +1,35 @@
+package cachetest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock moved by the tests, e.g. to expire the keys of cache.MemoryRedis.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a clock stopped at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now: now,
+	}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by the duration.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,772 @@
+// Package cachetest provides the conformance suites of the redis clients, and a fake clock,
+// so every backend of the cache package, including cache.MemoryRedis, is checked against the same behavior.
+package cachetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ScriptClient is a redis client able to run scripts.
+type ScriptClient interface {
+	cache.RedisItf
+	cache.ScripterItf
+}
+
+// ScanClient is a redis client able to iterate the keys.
+type ScanClient interface {
+	cache.RedisItf
+	cache.ScannerItf
+}
+
+// prefix returns a unique prefix of the keys of a suite, so the suites can share a redis server.
+// The prefix is a hash tag, so the keys of a suite share the cluster hash slot and can be used by the scripts.
+func prefix() string {
+	return "{cachetest:" + ksuid.New().String() + "}:"
+}
+
+// RunRedisConformance runs the conformance suite of RedisItf against the client,
+// checking the strings, hashes, lists, sets, sorted sets and ttl commands.
+// The keys are prefixed with a unique hash tag and deleted at the end of the suite.
+func RunRedisConformance(t *testing.T, client cache.RedisItf) {
+	p := prefix()
+	ctx := context.Background()
+
+	var (
+		mu   sync.Mutex
+		keys []interface{}
+	)
+	key := func(name string) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		keys = append(keys, p+name)
+		return p + name
+	}
+	defer func() {
+		_, _ = client.Del(ctx, keys...)
+	}()
+
+	t.Run("Strings", func(t *testing.T) {
+		k := key("string")
+
+		_, err := client.Get(ctx, k)
+		assert.Equal(t, errorx.CodeNotFound, errorx.GetCode(err))
+
+		require.NoError(t, client.SimpleSet(ctx, k, "value"))
+		got, err := client.Get(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), got)
+
+		ttl, err := client.TTL(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-1), ttl)
+
+		require.NoError(t, client.SetEX(ctx, k, 60, "other"))
+		got, err = client.Get(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("other"), got)
+		assertTTL(t, client, k, 60)
+
+		assert.Error(t, client.SetEX(ctx, k, 0, "value"))
+	})
+
+	t.Run("SetNX", func(t *testing.T) {
+		k := key("setnx")
+
+		ok, err := client.SetNX(ctx, k, 60, "first")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = client.SetNX(ctx, k, 60, "second")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		got, err := client.Get(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("first"), got)
+		assertTTL(t, client, k, 60)
+	})
+
+	t.Run("Counters", func(t *testing.T) {
+		k := key("counter")
+
+		n, err := client.Incr(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		n, err = client.IncrBy(ctx, k, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), n)
+
+		n, err = client.Decr(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), n)
+
+		n, err = client.IncrByEx(ctx, k, 5, 60)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(15), n)
+		assertTTL(t, client, k, 60)
+
+		// The counter keeps its ttl.
+		_, err = client.Incr(ctx, k)
+		assert.NoError(t, err)
+		assertTTL(t, client, k, 60)
+
+		text := key("counter:text")
+		require.NoError(t, client.SimpleSet(ctx, text, "text"))
+		_, err = client.Incr(ctx, text)
+		assert.Error(t, err)
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		k := key("expiry")
+
+		ttl, err := client.TTL(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-2), ttl)
+
+		ok, err := client.Expire(ctx, k, 60)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, client.SimpleSet(ctx, k, "value"))
+		ok, err = client.Expire(ctx, k, 60)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assertTTL(t, client, k, 60)
+
+		ok, err = client.ExpireAt(ctx, k, time.Now().Add(time.Hour).Unix())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assertTTL(t, client, k, 3600)
+
+		// Expiring now or in the past deletes the key.
+		ok, err = client.Expire(ctx, k, 0)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assertExists(t, client, k, false)
+
+		require.NoError(t, client.SimpleSet(ctx, k, "value"))
+		ok, err = client.ExpireAt(ctx, k, time.Now().Add(-time.Hour).Unix())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assertExists(t, client, k, false)
+	})
+
+	t.Run("Del", func(t *testing.T) {
+		first, second, missing := key("del:1"), key("del:2"), key("del:3")
+		require.NoError(t, client.SimpleSet(ctx, first, "value"))
+		require.NoError(t, client.SimpleSet(ctx, second, "value"))
+		assertExists(t, client, first, true)
+
+		n, err := client.Del(ctx, first, second, missing)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assertExists(t, client, first, false)
+	})
+
+	t.Run("Wrong type", func(t *testing.T) {
+		k := key("wrongtype")
+		require.NoError(t, client.SimpleSet(ctx, k, "value"))
+
+		_, err := client.HGet(ctx, k, "field")
+		assert.Equal(t, errorx.CodeGateway, errorx.GetCode(err))
+		_, err = client.LPush(ctx, k, "value")
+		assert.Equal(t, errorx.CodeGateway, errorx.GetCode(err))
+		_, err = client.SAdd(ctx, k, "value")
+		assert.Equal(t, errorx.CodeGateway, errorx.GetCode(err))
+		_, err = client.ZAdd(ctx, k, cache.ZMember{Member: "value", Score: 1})
+		assert.Equal(t, errorx.CodeGateway, errorx.GetCode(err))
+	})
+
+	t.Run("Hashes", func(t *testing.T) {
+		k := key("hash")
+
+		_, err := client.HGet(ctx, k, "name")
+		assert.Equal(t, errorx.CodeNotFound, errorx.GetCode(err))
+
+		for _, field := range []string{"name", "email"} {
+			ok, err := client.HSet(ctx, k, field, field+":value")
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}
+
+		got, err := client.HGet(ctx, k, "name")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("name:value"), got)
+
+		_, err = client.HGet(ctx, k, "missing")
+		assert.Equal(t, errorx.CodeNotFound, errorx.GetCode(err))
+
+		values, err := client.HMGet(ctx, k, "name", "missing")
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("name:value"), nil}, values)
+
+		all, err := client.HGetAll(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"name": "name:value", "email": "email:value"}, all)
+
+		fields, err := client.HKeys(ctx, k)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"name", "email"}, fields)
+
+		ok, err := client.HExists(ctx, k, "email")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		n, err := client.HDel(ctx, k, "name", "email", "missing")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		// The empty hash is deleted.
+		assertExists(t, client, k, false)
+		all, err = client.HGetAll(ctx, k)
+		assert.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("Lists", func(t *testing.T) {
+		k := key("list")
+
+		n, err := client.RPush(ctx, k, "c", "d")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		n, err = client.LPush(ctx, k, "b", "a")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), n)
+
+		got, err := client.LRange(ctx, k, 0, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}, got)
+
+		got, err = client.LRange(ctx, k, -2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("c"), []byte("d")}, got)
+
+		got, err = client.LRange(ctx, k, 5, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+
+		require.NoError(t, client.LTrim(ctx, k, 1, 2))
+		n, err = client.LLen(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		got, err = client.LRange(ctx, k, 0, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("b"), []byte("c")}, got)
+
+		// The empty list is deleted.
+		require.NoError(t, client.LTrim(ctx, k, 5, 10))
+		assertExists(t, client, k, false)
+	})
+
+	t.Run("Sets", func(t *testing.T) {
+		k := key("set")
+
+		ok, err := client.SAdd(ctx, k, "a", "b")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = client.SAdd(ctx, k, "a")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = client.SIsMember(ctx, k, "b")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		n, err := client.SCard(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		members, err := client.SMembers(ctx, k)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, members)
+
+		n, err = client.SRem(ctx, k, "a", "b", "c")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assertExists(t, client, k, false)
+
+		members, err = client.SMembers(ctx, k)
+		assert.NoError(t, err)
+		assert.Empty(t, members)
+	})
+
+	t.Run("Sorted sets", func(t *testing.T) {
+		k := key("zset")
+
+		n, err := client.ZAdd(ctx, k,
+			cache.ZMember{Member: "a", Score: 1},
+			cache.ZMember{Member: "b", Score: 2},
+			cache.ZMember{Member: "c", Score: 3},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+
+		n, err = client.ZAdd(ctx, k, cache.ZMember{Member: "a", Score: 4})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+
+		score, err := client.ZIncrBy(ctx, k, 0.5, "b")
+		assert.NoError(t, err)
+		assert.Equal(t, 2.5, score)
+
+		got, err := client.ZRange(ctx, k, 0, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, []cache.ZMember{{Member: "b", Score: 2.5}, {Member: "c", Score: 3}, {Member: "a", Score: 4}}, got)
+
+		got, err = client.ZRevRange(ctx, k, 0, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []cache.ZMember{{Member: "a", Score: 4}, {Member: "c", Score: 3}}, got)
+
+		got, err = client.ZRangeByScore(ctx, k, "(2.5", "+inf", 0, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []cache.ZMember{{Member: "c", Score: 3}, {Member: "a", Score: 4}}, got)
+
+		got, err = client.ZRangeByScore(ctx, k, "-inf", "+inf", 1, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []cache.ZMember{{Member: "c", Score: 3}}, got)
+
+		// Zero count means no limit after the offset.
+		got, err = client.ZRangeByScore(ctx, k, "-inf", "+inf", 1, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []cache.ZMember{{Member: "c", Score: 3}, {Member: "a", Score: 4}}, got)
+
+		rank, err := client.ZRank(ctx, k, "c")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), rank)
+
+		rank, err = client.ZRevRank(ctx, k, "b")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), rank)
+
+		score, err = client.ZScore(ctx, k, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, float64(4), score)
+
+		_, err = client.ZRank(ctx, k, "missing")
+		assert.Equal(t, errorx.CodeNotFound, errorx.GetCode(err))
+		_, err = client.ZScore(ctx, k, "missing")
+		assert.Equal(t, errorx.CodeNotFound, errorx.GetCode(err))
+
+		n, err = client.ZRem(ctx, k, "a", "missing")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		n, err = client.ZRemRangeByScore(ctx, k, "-inf", "3")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		n, err = client.ZCard(ctx, k)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+		assertExists(t, client, k, false)
+	})
+
+	t.Run("Publish without subscribers", func(t *testing.T) {
+		n, err := client.Publish(ctx, key("channel"), "message")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+// RunScripterConformance runs the conformance suite of the scripts shipped with the cache package against the client.
+// The keys are prefixed with a unique hash tag and deleted at the end of the suite.
+func RunScripterConformance(t *testing.T, client ScriptClient) {
+	p := prefix()
+	ctx := context.Background()
+
+	var (
+		mu   sync.Mutex
+		keys []interface{}
+	)
+	key := func(name string) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		keys = append(keys, p+name)
+		return p + name
+	}
+	defer func() {
+		_, _ = client.Del(ctx, keys...)
+	}()
+
+	run := func(t *testing.T, script *cache.Script, keys []string, args ...interface{}) interface{} {
+		res, err := client.RunScript(ctx, script, keys, args...)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("Load", func(t *testing.T) {
+		assert.NoError(t, client.LoadScripts(ctx, cache.ScriptLockAcquire, cache.ScriptLockRelease))
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		lock := []string{key("lock"), key("fence")}
+
+		assert.Equal(t, int64(1), run(t, cache.ScriptLockAcquire, lock, "a", 60000))
+		assert.Equal(t, int64(0), run(t, cache.ScriptLockAcquire, lock, "b", 60000))
+		assert.Equal(t, int64(0), run(t, cache.ScriptLockRelease, lock[:1], "b"))
+		assert.Equal(t, int64(0), run(t, cache.ScriptLockRenew, lock[:1], "b", 60000))
+		assert.Equal(t, int64(1), run(t, cache.ScriptLockRenew, lock[:1], "a", 120000))
+		assertTTL(t, client, lock[0], 120)
+		assert.Equal(t, int64(1), run(t, cache.ScriptLockRelease, lock[:1], "a"))
+
+		// The fencing token grows with every acquisition.
+		assert.Equal(t, int64(2), run(t, cache.ScriptLockAcquire, lock, "b", 60000))
+	})
+
+	t.Run("Incr by with expiry", func(t *testing.T) {
+		counter := []string{key("counter")}
+
+		assert.Equal(t, int64(2), run(t, cache.ScriptIncrByEx, counter, 2, 60))
+		assert.Equal(t, int64(5), run(t, cache.ScriptIncrByEx, counter, 3, 120))
+		assertTTL(t, client, counter[0], 120)
+	})
+
+	t.Run("Fixed window", func(t *testing.T) {
+		window := []string{key("fixed")}
+
+		for i, want := range [][2]int64{{1, 1}, {1, 0}, {0, 0}} {
+			res := toInt64s(t, run(t, cache.ScriptRateLimitFixedWindow, window, 2, 60000))
+			assert.Equal(t, want[0], res[0], "request %d", i)
+			assert.Equal(t, want[1], res[1], "request %d", i)
+			assert.True(t, res[2] > 0 && res[2] <= 60000, "request %d reset after %d", i, res[2])
+		}
+	})
+
+	t.Run("Sliding log", func(t *testing.T) {
+		log := []string{key("sliding")}
+
+		steps := []struct {
+			now  int64
+			want []int64
+		}{
+			{now: 1000, want: []int64{1, 1, 1000}},
+			{now: 1100, want: []int64{1, 0, 1000}},
+			{now: 1200, want: []int64{0, 0, 800}},
+			{now: 2001, want: []int64{1, 0, 1000}},
+		}
+		for i, step := range steps {
+			res := run(t, cache.ScriptRateLimitSlidingLog, log, 2, 1000, step.now, ksuid.New().String())
+			assert.Equal(t, step.want, toInt64s(t, res), "step %d", i)
+		}
+	})
+
+	t.Run("Token bucket", func(t *testing.T) {
+		bucket := []string{key("bucket")}
+
+		steps := []struct {
+			now  int64
+			want []int64
+		}{
+			{now: 1000, want: []int64{1, 1, 1000}},
+			{now: 1000, want: []int64{1, 0, 2000}},
+			{now: 1000, want: []int64{0, 0, 1000}},
+			{now: 2500, want: []int64{1, 0, 1500}},
+		}
+		for i, step := range steps {
+			res := run(t, cache.ScriptRateLimitTokenBucket, bucket, 2, "1", step.now, 1)
+			assert.Equal(t, step.want, toInt64s(t, res), "step %d", i)
+		}
+	})
+
+	t.Run("Tag key", func(t *testing.T) {
+		tag, tagged := key("tag:key"), key("tagged")
+
+		assert.Equal(t, int64(1), run(t, cache.ScriptTagKey, []string{tag}, tagged, 60))
+		assert.Equal(t, int64(0), run(t, cache.ScriptTagKey, []string{tag}, tagged, 120))
+		assertTTL(t, client, tag, 120)
+	})
+
+	t.Run("Invalidate tag", func(t *testing.T) {
+		tag, first, second := key("tag"), key("tagged:1"), key("tagged:2")
+		require.NoError(t, client.SimpleSet(ctx, first, "value"))
+		require.NoError(t, client.SimpleSet(ctx, second, "value"))
+		_, err := client.SAdd(ctx, tag, first, second)
+		require.NoError(t, err)
+
+		res := run(t, cache.ScriptInvalidateTag, []string{tag})
+		assert.ElementsMatch(t, []string{first, second}, toStrings(t, res))
+		assertExists(t, client, first, false)
+		assertExists(t, client, tag, false)
+	})
+}
+
+// RunScannerConformance runs the conformance suite of ScannerItf against the client.
+// The keys are prefixed with a unique hash tag and deleted at the end of the suite.
+func RunScannerConformance(t *testing.T, client ScanClient) {
+	p := prefix()
+	ctx := context.Background()
+
+	keys := []interface{}{p + "hash", p + "set"}
+	for i := 0; i < 25; i++ {
+		keys = append(keys, fmt.Sprintf("%skey:%d", p, i))
+	}
+	defer func() {
+		_, _ = client.Del(ctx, keys...)
+	}()
+
+	for _, key := range keys[2:] {
+		require.NoError(t, client.SimpleSet(ctx, key.(string), "value"))
+	}
+	for i := 0; i < 25; i++ {
+		_, err := client.HSet(ctx, p+"hash", fmt.Sprintf("field:%d", i), strconv.Itoa(i))
+		require.NoError(t, err)
+		_, err = client.SAdd(ctx, p+"set", fmt.Sprintf("member:%d", i))
+		require.NoError(t, err)
+	}
+
+	t.Run("ScanKeys", func(t *testing.T) {
+		got := make(map[string]struct{})
+		err := client.ScanKeys(ctx, p+"key:1*", 5, func(keys []string) error {
+			for _, key := range keys {
+				got[key] = struct{}{}
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+
+		want := make(map[string]struct{})
+		for _, i := range []int{1, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19} {
+			want[fmt.Sprintf("%skey:%d", p, i)] = struct{}{}
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("HScan", func(t *testing.T) {
+		got := make(map[string]string)
+		err := client.HScan(ctx, p+"hash", "field:2*", 5, func(fields map[string]string) error {
+			for field, value := range fields {
+				got[field] = value
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"field:2": "2", "field:20": "20", "field:21": "21", "field:22": "22", "field:23": "23", "field:24": "24",
+		}, got)
+	})
+
+	t.Run("SScan", func(t *testing.T) {
+		got := make([]string, 0)
+		err := client.SScan(ctx, p+"set", "*", 5, func(members []string) error {
+			got = append(got, members...)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, got, 25)
+		assert.Contains(t, got, "member:0")
+	})
+
+	t.Run("Default options", func(t *testing.T) {
+		// Empty match matches everything, and zero count uses the default batch size.
+		keys := make(map[string]struct{})
+		err := client.ScanKeys(ctx, "", 0, func(batch []string) error {
+			for _, key := range batch {
+				keys[key] = struct{}{}
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		for i := 0; i < 25; i++ {
+			assert.Contains(t, keys, fmt.Sprintf("%skey:%d", p, i))
+		}
+
+		fields := make(map[string]string)
+		err = client.HScan(ctx, p+"hash", "", 0, func(batch map[string]string) error {
+			for field, value := range batch {
+				fields[field] = value
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, fields, 25)
+
+		members := make([]string, 0)
+		err = client.SScan(ctx, p+"set", "", 0, func(batch []string) error {
+			members = append(members, batch...)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, members, 25)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		calls := 0
+		err := client.HScan(ctx, p+"missing", "*", 5, func(map[string]string) error {
+			calls++
+			return nil
+		})
+		assert.NoError(t, err)
+		err = client.SScan(ctx, p+"missing", "*", 5, func([]string) error {
+			calls++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := client.ScanKeys(ctx, p+"key:*", 5, func([]string) error {
+			calls++
+			return stop
+		})
+		assert.True(t, errors.Is(err, stop))
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// RunPubSubConformance runs the conformance suite of PubSubItf against the client.
+func RunPubSubConformance(t *testing.T, client cache.PubSubItf) {
+	p := prefix()
+
+	t.Run("Subscribe", func(t *testing.T) {
+		channel := p + "channel"
+		got := receive(t, client, channel, func(ctx context.Context, handler cache.MessageHandler) error {
+			return client.Subscribe(ctx, handler, channel)
+		})
+		assert.Equal(t, channel, got.Channel)
+		assert.Equal(t, "", got.Pattern)
+		assert.Equal(t, []byte("message"), got.Data)
+	})
+
+	t.Run("PSubscribe", func(t *testing.T) {
+		pattern := p + "news.*"
+		got := receive(t, client, p+"news.sport", func(ctx context.Context, handler cache.MessageHandler) error {
+			return client.PSubscribe(ctx, handler, pattern)
+		})
+		assert.Equal(t, p+"news.sport", got.Channel)
+		assert.Equal(t, pattern, got.Pattern)
+		assert.Equal(t, []byte("message"), got.Data)
+	})
+
+	t.Run("Missing channels", func(t *testing.T) {
+		err := client.Subscribe(context.Background(), func(context.Context, *cache.Message) error { return nil })
+		assert.Equal(t, errorx.CodeInvalid, errorx.GetCode(err))
+	})
+}
+
+// receive subscribes, publishes a message to the channel once the subscription is ready,
+// and returns the received message.
+func receive(
+	t *testing.T,
+	client cache.PubSubItf,
+	channel string,
+	subscribe func(ctx context.Context, handler cache.MessageHandler) error,
+) *cache.Message {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan *cache.Message, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- subscribe(ctx, func(_ context.Context, msg *cache.Message) error {
+			select {
+			case received <- msg:
+			default:
+			}
+			return nil
+		})
+	}()
+
+	// The subscription is ready once the message has a subscriber.
+	for {
+		n, err := client.Publish(ctx, channel, "message")
+		require.NoError(t, err)
+		if n > 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			require.FailNow(t, "subscription not ready")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	var msg *cache.Message
+	select {
+	case msg = <-received:
+	case <-ctx.Done():
+		require.FailNow(t, "message not received")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+	return msg
+}
+
+// assertTTL asserts the ttl of the key is positive and at most max seconds.
+func assertTTL(t *testing.T, client cache.RedisItf, key string, max int64) {
+	t.Helper()
+
+	ttl, err := client.TTL(context.Background(), key)
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= max, "ttl %d not in (0, %d]", ttl, max)
+}
+
+// assertExists asserts whether the key exists.
+func assertExists(t *testing.T, client cache.RedisItf, key string, want bool) {
+	t.Helper()
+
+	ok, err := client.Exists(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Equal(t, want, ok, "key %s", key)
+}
+
+// toInt64s converts an array reply of integers.
+func toInt64s(t *testing.T, reply interface{}) []int64 {
+	t.Helper()
+
+	values, ok := reply.([]interface{})
+	require.True(t, ok, "unexpected reply %v", reply)
+
+	res := make([]int64, len(values))
+	for i, v := range values {
+		n, ok := v.(int64)
+		require.True(t, ok, "unexpected reply %v", reply)
+		res[i] = n
+	}
+
+	return res
+}
+
+// toStrings converts an array reply of strings, of both redigo and go-redis.
+func toStrings(t *testing.T, reply interface{}) []string {
+	t.Helper()
+
+	values, ok := reply.([]interface{})
+	require.True(t, ok, "unexpected reply %v", reply)
+
+	res := make([]string, len(values))
+	for i, v := range values {
+		switch s := v.(type) {
+		case []byte:
+			res[i] = string(s)
+		case string:
+			res[i] = s
+		default:
+			require.Fail(t, "unexpected reply", "%v", reply)
+		}
+	}
+
+	return res
+}
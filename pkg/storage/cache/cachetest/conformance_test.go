@@ -0,0 +1,86 @@
+package cachetest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/stretchr/testify/require"
+)
+
+// redisAddress returns the address of the redis server running the suites against the real clients,
+// or skips the test when it isn't set.
+func redisAddress(t *testing.T) string {
+	address := os.Getenv("GDK_REDIS_ADDRESS")
+	if address == "" {
+		t.Skip("GDK_REDIS_ADDRESS not set")
+	}
+
+	return address
+}
+
+func TestMemoryRedis(t *testing.T) {
+	client := cache.NewMemoryRedis()
+	defer client.Close()
+
+	RunRedisConformance(t, client)
+	RunScripterConformance(t, client)
+	RunScannerConformance(t, client)
+	RunPubSubConformance(t, client)
+}
+
+func TestRedigo(t *testing.T) {
+	client, err := cache.NewRedigoInstance(&cache.RedisConfiguration{
+		Addresses: []string{redisAddress(t)},
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	RunRedisConformance(t, client)
+	RunScripterConformance(t, client)
+	RunScannerConformance(t, client)
+	RunPubSubConformance(t, client)
+}
+
+func TestGoRedisV8(t *testing.T) {
+	client, err := cache.NewGoRedisV8Instance(&cache.RedisConfiguration{
+		Addresses: []string{redisAddress(t)},
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	RunRedisConformance(t, client)
+	RunScripterConformance(t, client)
+	RunScannerConformance(t, client)
+	RunPubSubConformance(t, client)
+}
+
+// TestScripts runs the Lua scripts against the real clients connected to an embedded redis,
+// so the scripts and their Go implementations in MemoryRedis can't drift apart
+// even when GDK_REDIS_ADDRESS isn't set.
+func TestScripts(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	defer server.Close()
+
+	t.Run("Redigo", func(t *testing.T) {
+		client, err := cache.NewRedigoInstance(&cache.RedisConfiguration{
+			Addresses: []string{server.Addr()},
+		})
+		require.NoError(t, err)
+		defer client.Close()
+
+		RunScripterConformance(t, client)
+	})
+
+	t.Run("GoRedisV8", func(t *testing.T) {
+		client, err := cache.NewGoRedisV8Instance(&cache.RedisConfiguration{
+			Addresses: []string{server.Addr()},
+		})
+		require.NoError(t, err)
+		defer client.Close()
+
+		RunScripterConformance(t, client)
+	})
+}
@@ -0,0 +1,18 @@
+package cachetest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/peractio/gdk/pkg/logx"
+)
+
+func TestMain(m *testing.M) {
+	_, _ = logx.New(&logx.Config{
+		Debug:    true,
+		AppName:  "gdk",
+		Filename: "",
+	})
+
+	os.Exit(m.Run())
+}
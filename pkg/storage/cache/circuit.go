@@ -0,0 +1,283 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int32
+
+// Circuit states.
+const (
+	// CircuitClosed lets the commands through, and counts their failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects the commands until the open timeout has passed.
+	CircuitOpen
+	// CircuitHalfOpen lets a few trial commands through,
+	// the circuit is closed when all of them succeed, and opened again on the first failure,
+	// or when they don't complete within the open timeout.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configuration.
+type CircuitBreakerConfig struct {
+	// ConsecutiveFailures opens the circuit after this number of consecutive failures,
+	// zero value disables the threshold.
+	ConsecutiveFailures int
+	// FailureRate opens the circuit when the ratio of the failures to the commands of the window reaches it,
+	// zero value disables the threshold.
+	FailureRate float64
+	// MinRequests is the minimum number of commands of the window for the failure rate to apply.
+	MinRequests int
+	// Window is the period the commands are counted over while the circuit is closed,
+	// zero value means the counts are only reset when the state changes.
+	Window time.Duration
+	// OpenTimeout is the duration the circuit stays open before letting trial commands through,
+	// and the duration the trial commands have to succeed before the circuit opens again.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of trial commands to succeed in the half-open state to close the circuit.
+	HalfOpenRequests int
+	// IsFailure reports whether an error is a failure of redis,
+	// by default all the errors except the missing keys, the conflicts, the invalid commands
+	// and the cancelled contexts.
+	IsFailure func(err error) bool
+	// Fallback serves the commands rejected by the circuit,
+	// nil value fails them fast with errorx.CodeCircuitBreaker.
+	Fallback RedisItf
+}
+
+// DefaultCircuitBreakerConfig is the default configuration of the circuit breakers.
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	ConsecutiveFailures: 5,
+	FailureRate:         0.5,
+	MinRequests:         20,
+	Window:              10 * time.Second,
+	OpenTimeout:         5 * time.Second,
+	HalfOpenRequests:    1,
+	IsFailure:           isCircuitFailure,
+	Fallback:            nil,
+}
+
+// isCircuitFailure reports the errors of a degraded redis, the other errors are the results of the commands.
+func isCircuitFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	switch errorx.GetCode(err) {
+	case errorx.CodeNotFound, errorx.CodeConflict, errorx.CodeInvalid, errorx.CodeCircuitBreaker:
+		return false
+	default:
+		return true
+	}
+}
+
+// circuit is the state machine of a circuit breaker.
+type circuit struct {
+	name   string
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	state CircuitState
+	// generation changes with the state, the results of the commands let through by a former state are ignored.
+	generation uint64
+	// expiry is the end of the window in the closed state,
+	// and the end of the open timeout in the open and half-open states.
+	expiry      time.Time
+	requests    int
+	failures    int
+	consecutive int
+	// trials are the commands let through in the half-open state, but the ones released by a done context,
+	// and successes the succeeded ones.
+	trials    int
+	successes int
+}
+
+// newCircuit returns a closed circuit.
+func newCircuit(name string, config CircuitBreakerConfig) *circuit {
+	// Defaults
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = DefaultCircuitBreakerConfig.OpenTimeout
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = DefaultCircuitBreakerConfig.HalfOpenRequests
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = isCircuitFailure
+	}
+
+	c := &circuit{
+		name:   name,
+		config: config,
+		now:    time.Now,
+	}
+	c.expiry = c.windowEnd(c.now())
+
+	return c
+}
+
+// State returns the current state of the circuit.
+func (c *circuit) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refresh(context.Background(), c.now())
+	return c.state
+}
+
+// allow returns the generation of the command let through, or the error of the rejected command.
+func (c *circuit) allow(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refresh(ctx, c.now())
+	switch c.state {
+	case CircuitOpen:
+		return 0, errorx.E("circuit breaker is open", errorx.Fields{tags.Client: c.name}, errorx.CodeCircuitBreaker)
+	case CircuitHalfOpen:
+		if c.trials >= c.config.HalfOpenRequests {
+			return 0, errorx.E("circuit breaker is half-open", errorx.Fields{tags.Client: c.name}, errorx.CodeCircuitBreaker)
+		}
+		c.trials++
+	}
+
+	return c.generation, nil
+}
+
+// done counts the result of a command let through by the generation.
+func (c *circuit) done(ctx context.Context, generation uint64, err error) {
+	c.count(ctx, generation, err, c.config.IsFailure(err))
+}
+
+// fail counts a failure of a command let through by the generation, whatever the error is.
+func (c *circuit) fail(ctx context.Context, generation uint64, err error) {
+	c.count(ctx, generation, err, true)
+}
+
+// count counts the result of a command let through by the generation.
+func (c *circuit) count(ctx context.Context, generation uint64, err error, failure bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.refresh(ctx, now)
+	if generation != c.generation {
+		return
+	}
+
+	switch c.state {
+	case CircuitClosed:
+		c.requests++
+		if failure {
+			c.failures++
+			c.consecutive++
+		} else {
+			c.consecutive = 0
+		}
+		if c.tripped() {
+			c.setState(ctx, CircuitOpen, now, err)
+		}
+
+	case CircuitHalfOpen:
+		if failure {
+			c.setState(ctx, CircuitOpen, now, err)
+			return
+		}
+		// The command ended by its context didn't get a reply of redis,
+		// so it releases its trial for another command instead of counting as a success.
+		if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled)) {
+			c.trials--
+			return
+		}
+		c.successes++
+		if c.successes >= c.config.HalfOpenRequests {
+			c.setState(ctx, CircuitClosed, now, nil)
+		}
+	}
+}
+
+// tripped reports whether the counts of the closed state reach a threshold.
+func (c *circuit) tripped() bool {
+	if c.config.ConsecutiveFailures > 0 && c.consecutive >= c.config.ConsecutiveFailures {
+		return true
+	}
+
+	return c.config.FailureRate > 0 &&
+		c.requests >= c.config.MinRequests &&
+		float64(c.failures)/float64(c.requests) >= c.config.FailureRate
+}
+
+// refresh resets the counts of the ended window, lets the trial commands through after the open timeout,
+// and opens the circuit again when the trial commands don't complete in time.
+func (c *circuit) refresh(ctx context.Context, now time.Time) {
+	if c.expiry.IsZero() || now.Before(c.expiry) {
+		return
+	}
+
+	switch c.state {
+	case CircuitClosed:
+		c.requests, c.failures, c.consecutive = 0, 0, 0
+		c.expiry = c.windowEnd(now)
+	case CircuitOpen:
+		c.setState(ctx, CircuitHalfOpen, now, nil)
+	case CircuitHalfOpen:
+		c.setState(ctx, CircuitOpen, now, errorx.E("circuit breaker trials timed out"))
+	}
+}
+
+// setState changes the state of the circuit, and starts a new generation.
+func (c *circuit) setState(ctx context.Context, state CircuitState, now time.Time, err error) {
+	const op errorx.Op = "cache/CircuitBreaker.setState"
+
+	c.state = state
+	c.generation++
+	c.requests, c.failures, c.consecutive = 0, 0, 0
+	c.trials, c.successes = 0, 0
+
+	switch state {
+	case CircuitClosed:
+		c.expiry = c.windowEnd(now)
+	case CircuitOpen, CircuitHalfOpen:
+		c.expiry = now.Add(c.config.OpenTimeout)
+	}
+
+	if state == CircuitOpen {
+		if err == nil {
+			err = errorx.E("circuit breaker tripped")
+		}
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Client: c.name, tags.State: state.String()}), string(op)+" circuit opened")
+		return
+	}
+	logx.INF(ctx, logx.KV{tags.Client: c.name, tags.State: state.String()}, string(op)+" circuit "+state.String())
+}
+
+// windowEnd returns the end of the window starting now, zero value when the counts aren't reset.
+func (c *circuit) windowEnd(now time.Time) time.Time {
+	if c.config.Window <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(c.config.Window)
+}
@@ -0,0 +1,616 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// CircuitBreaker is a redis client failing fast while redis is degraded, instead of waiting for the timeouts.
+// The circuit opens when the failures reach a threshold of the configuration, see CircuitState.
+// The commands rejected by the open circuit are served by the fallback client,
+// or fail with errorx.CodeCircuitBreaker without a fallback.
+// The results of the commands let through are returned unchanged.
+type CircuitBreaker struct {
+	client  RedisItf
+	circuit *circuit
+}
+
+// NewCircuitBreaker returns the circuit breaker of the redis client, with the default configuration.
+// The name identifies the client in the logs.
+func NewCircuitBreaker(name string, client RedisItf) *CircuitBreaker {
+	return NewCircuitBreakerWithConfig(name, client, DefaultCircuitBreakerConfig)
+}
+
+// NewCircuitBreakerWithConfig returns the circuit breaker of the redis client.
+// The name identifies the client in the logs.
+func NewCircuitBreakerWithConfig(name string, client RedisItf, config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		client:  client,
+		circuit: newCircuit(name, config),
+	}
+}
+
+// State returns the current state of the circuit.
+func (c *CircuitBreaker) State() CircuitState {
+	return c.circuit.State()
+}
+
+// do runs fn with the client when the circuit lets the command through, or with the fallback client otherwise.
+// A panic of fn is counted as a failure before it is propagated, so a trial command never holds its slot.
+func (c *CircuitBreaker) do(ctx context.Context, op errorx.Op, fn func(client RedisItf) error) (err error) {
+	generation, err := c.circuit.allow(ctx)
+	if err != nil {
+		if c.circuit.config.Fallback != nil {
+			return fn(c.circuit.config.Fallback)
+		}
+		return errorx.E(err, op)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.circuit.fail(ctx, generation, errorx.E(fmt.Errorf("%v", r), op, errorx.CodeInternal))
+			panic(r)
+		}
+		c.circuit.done(ctx, generation, err)
+	}()
+
+	return fn(c.client)
+}
+
+// Get gets the value from redis in []byte form.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (c *CircuitBreaker) Get(ctx context.Context, key string) ([]byte, error) {
+	const op errorx.Op = "cache/CircuitBreaker.Get"
+
+	var res []byte
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.Get(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// SimpleSet sets value to key in redis without any additional options.
+// Key doesn't have a TTL.
+func (c *CircuitBreaker) SimpleSet(ctx context.Context, key, value string) error {
+	const op errorx.Op = "cache/CircuitBreaker.SimpleSet"
+
+	return c.do(ctx, op, func(client RedisItf) error {
+		return client.SimpleSet(ctx, key, value)
+	})
+}
+
+// SetEX sets the value to a key with timeout in seconds.
+func (c *CircuitBreaker) SetEX(ctx context.Context, key string, seconds int64, value string) error {
+	const op errorx.Op = "cache/CircuitBreaker.SetEX"
+
+	return c.do(ctx, op, func(client RedisItf) error {
+		return client.SetEX(ctx, key, seconds, value)
+	})
+}
+
+// SetNX sets a value to a key with specified timeouts.
+// SetNX returns false if the key exists.
+func (c *CircuitBreaker) SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.SetNX"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.SetNX(ctx, key, seconds, value)
+		return err
+	})
+
+	return res, err
+}
+
+// Exists checks whether the key exists in redis.
+func (c *CircuitBreaker) Exists(ctx context.Context, key string) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.Exists"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.Exists(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// Expire sets the TTL of a key to specified value in seconds.
+func (c *CircuitBreaker) Expire(ctx context.Context, key string, seconds int64) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.Expire"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.Expire(ctx, key, seconds)
+		return err
+	})
+
+	return res, err
+}
+
+// ExpireAt sets the TTL of a key to a certain unix timestamp in seconds.
+func (c *CircuitBreaker) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ExpireAt"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ExpireAt(ctx, key, timestamp)
+		return err
+	})
+
+	return res, err
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+// It returns -2 if the key does not exist and -1 if the key has no expiry.
+func (c *CircuitBreaker) TTL(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.TTL"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.TTL(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// Del deletes a key.
+func (c *CircuitBreaker) Del(ctx context.Context, key ...interface{}) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.Del"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.Del(ctx, key...)
+		return err
+	})
+
+	return res, err
+}
+
+// Incr increments the integer value of a key by 1.
+func (c *CircuitBreaker) Incr(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.Incr"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.Incr(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// Decr decrements the integer value of a key by 1.
+func (c *CircuitBreaker) Decr(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.Decr"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.Decr(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// IncrBy increments the integer value of a key by the given amount.
+func (c *CircuitBreaker) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.IncrBy"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.IncrBy(ctx, key, by)
+		return err
+	})
+
+	return res, err
+}
+
+// IncrByEx increments the integer value of a key by the given amount,
+// and sets the TTL of the key to specified value in seconds.
+func (c *CircuitBreaker) IncrByEx(ctx context.Context, key string, by int64, expires int64) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.IncrByEx"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.IncrByEx(ctx, key, by, expires)
+		return err
+	})
+
+	return res, err
+}
+
+// HGet gets the value of a hash field.
+// Missing key or field is reported as an error with errorx.CodeNotFound.
+func (c *CircuitBreaker) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	const op errorx.Op = "cache/CircuitBreaker.HGet"
+
+	var res []byte
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.HGet(ctx, key, field)
+		return err
+	})
+
+	return res, err
+}
+
+// HMGet gets the values of multiple hash fields in the order of the fields.
+// Missing field is returned as a nil value.
+func (c *CircuitBreaker) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
+	const op errorx.Op = "cache/CircuitBreaker.HMGet"
+
+	var res [][]byte
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.HMGet(ctx, key, fields...)
+		return err
+	})
+
+	return res, err
+}
+
+// HGetAll gets all the fields and values in a hash.
+func (c *CircuitBreaker) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	const op errorx.Op = "cache/CircuitBreaker.HGetAll"
+
+	var res map[string]string
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.HGetAll(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// HKeys gets all the fields in a hash.
+func (c *CircuitBreaker) HKeys(ctx context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/CircuitBreaker.HKeys"
+
+	var res []string
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.HKeys(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// HExists determines if a hash field exists.
+func (c *CircuitBreaker) HExists(ctx context.Context, key, field string) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.HExists"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.HExists(ctx, key, field)
+		return err
+	})
+
+	return res, err
+}
+
+// HSet sets the string value of a hash field.
+func (c *CircuitBreaker) HSet(ctx context.Context, key, field, value string) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.HSet"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.HSet(ctx, key, field, value)
+		return err
+	})
+
+	return res, err
+}
+
+// HDel deletes hash fields and returns the number of deleted fields.
+func (c *CircuitBreaker) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.HDel"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.HDel(ctx, key, fields...)
+		return err
+	})
+
+	return res, err
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (c *CircuitBreaker) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.LPush"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.LPush(ctx, key, values...)
+		return err
+	})
+
+	return res, err
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (c *CircuitBreaker) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.RPush"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.RPush(ctx, key, values...)
+		return err
+	})
+
+	return res, err
+}
+
+// LRange gets the elements of a list between index start and stop.
+func (c *CircuitBreaker) LRange(ctx context.Context, key string, start, stop int64) ([][]byte, error) {
+	const op errorx.Op = "cache/CircuitBreaker.LRange"
+
+	var res [][]byte
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.LRange(ctx, key, start, stop)
+		return err
+	})
+
+	return res, err
+}
+
+// LTrim trims a list to the elements between index start and stop.
+func (c *CircuitBreaker) LTrim(ctx context.Context, key string, start, stop int64) error {
+	const op errorx.Op = "cache/CircuitBreaker.LTrim"
+
+	return c.do(ctx, op, func(client RedisItf) error {
+		return client.LTrim(ctx, key, start, stop)
+	})
+}
+
+// LLen gets the length of a list.
+func (c *CircuitBreaker) LLen(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.LLen"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.LLen(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// SAdd add the specified members to the set stored at key.
+// It returns false if key and value combination exists.
+func (c *CircuitBreaker) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.SAdd"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.SAdd(ctx, key, value...)
+		return err
+	})
+
+	return res, err
+}
+
+// SRem removes the members from a set and returns the number of removed members.
+func (c *CircuitBreaker) SRem(ctx context.Context, key string, value ...string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.SRem"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.SRem(ctx, key, value...)
+		return err
+	})
+
+	return res, err
+}
+
+// SMembers gets all the members of a set.
+func (c *CircuitBreaker) SMembers(ctx context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/CircuitBreaker.SMembers"
+
+	var res []string
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.SMembers(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// SIsMember determines if a value is a member of a set.
+func (c *CircuitBreaker) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	const op errorx.Op = "cache/CircuitBreaker.SIsMember"
+
+	var res bool
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.SIsMember(ctx, key, value)
+		return err
+	})
+
+	return res, err
+}
+
+// SCard gets the number of members of a set.
+func (c *CircuitBreaker) SCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.SCard"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.SCard(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (c *CircuitBreaker) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZAdd"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZAdd(ctx, key, members...)
+		return err
+	})
+
+	return res, err
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (c *CircuitBreaker) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZIncrBy"
+
+	var res float64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZIncrBy(ctx, key, increment, member)
+		return err
+	})
+
+	return res, err
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (c *CircuitBreaker) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZRange"
+
+	var res []ZMember
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZRange(ctx, key, start, stop)
+		return err
+	})
+
+	return res, err
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (c *CircuitBreaker) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZRevRange"
+
+	var res []ZMember
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZRevRange(ctx, key, start, stop)
+		return err
+	})
+
+	return res, err
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Min and max accept the redis score syntax, e.g. "-inf", "+inf" and "(1" for exclusive bound.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (c *CircuitBreaker) ZRangeByScore(ctx context.Context, key, min, max string, offset, count int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZRangeByScore"
+
+	var res []ZMember
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZRangeByScore(ctx, key, min, max, offset, count)
+		return err
+	})
+
+	return res, err
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (c *CircuitBreaker) ZRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZRank"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZRank(ctx, key, member)
+		return err
+	})
+
+	return res, err
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (c *CircuitBreaker) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZRevRank"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZRevRank(ctx, key, member)
+		return err
+	})
+
+	return res, err
+}
+
+// ZScore gets the score of a sorted set member.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (c *CircuitBreaker) ZScore(ctx context.Context, key, member string) (float64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZScore"
+
+	var res float64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZScore(ctx, key, member)
+		return err
+	})
+
+	return res, err
+}
+
+// ZCard gets the number of members of a sorted set.
+func (c *CircuitBreaker) ZCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZCard"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZCard(ctx, key)
+		return err
+	})
+
+	return res, err
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (c *CircuitBreaker) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZRem"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZRem(ctx, key, members...)
+		return err
+	})
+
+	return res, err
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (c *CircuitBreaker) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	const op errorx.Op = "cache/CircuitBreaker.ZRemRangeByScore"
+
+	var res int64
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.ZRemRangeByScore(ctx, key, min, max)
+		return err
+	})
+
+	return res, err
+}
+
+// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+func (c *CircuitBreaker) Publish(ctx context.Context, topic, message string) (int, error) {
+	const op errorx.Op = "cache/CircuitBreaker.Publish"
+
+	var res int
+	err := c.do(ctx, op, func(client RedisItf) (err error) {
+		res, err = client.Publish(ctx, topic, message)
+		return err
+	})
+
+	return res, err
+}
+
+// Close closes the client, releasing any open resources.
+// The fallback client is left open.
+func (c *CircuitBreaker) Close() {
+	c.client.Close()
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_Get(t *testing.T) {
+	failure := errorx.E("connection refused", errorx.CodeGateway)
+
+	tests := []struct {
+		name     string
+		fallback bool
+		// calls is the number of calls, the first one trips the circuit when it fails.
+		calls    int
+		mock     func(client, fallback *MockRedisItf)
+		want     []byte
+		wantCode errorx.Code
+	}{
+		{
+			name:  "Closed",
+			calls: 1,
+			mock: func(client, fallback *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "key").Return([]byte("value"), nil)
+			},
+			want:     []byte("value"),
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:  "Tripped",
+			calls: 1,
+			mock: func(client, fallback *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "key").Return(nil, failure)
+			},
+			want:     nil,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name:  "Open without fallback",
+			calls: 2,
+			mock: func(client, fallback *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "key").Return(nil, failure)
+			},
+			want:     nil,
+			wantCode: errorx.CodeCircuitBreaker,
+		},
+		{
+			name:     "Open with fallback",
+			fallback: true,
+			calls:    2,
+			mock: func(client, fallback *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "key").Return(nil, failure)
+				fallback.EXPECT().Get(gomock.Any(), "key").Return([]byte("stale"), nil)
+			},
+			want:     []byte("stale"),
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client, fallback := NewMockRedisItf(ctrl), NewMockRedisItf(ctrl)
+			tt.mock(client, fallback)
+
+			config := CircuitBreakerConfig{ConsecutiveFailures: 1}
+			if tt.fallback {
+				config.Fallback = fallback
+			}
+			breaker := NewCircuitBreakerWithConfig("session", client, config)
+
+			var (
+				got []byte
+				err error
+			)
+			for i := 0; i < tt.calls; i++ {
+				got, err = breaker.Get(context.Background(), "key")
+			}
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCircuitBreaker_Panic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := NewMockRedisItf(ctrl)
+	client.EXPECT().Get(gomock.Any(), "key").DoAndReturn(func(ctx context.Context, key string) ([]byte, error) {
+		panic("client failed")
+	})
+	breaker := NewCircuitBreakerWithConfig("session", client, CircuitBreakerConfig{ConsecutiveFailures: 1})
+
+	// The panic is propagated, and counted as a failure.
+	assert.Panics(t, func() {
+		_, _ = breaker.Get(context.Background(), "key")
+	})
+	assert.Equal(t, CircuitOpen, breaker.State())
+}
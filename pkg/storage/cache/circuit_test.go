@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a clock moved by the tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestCircuit(t *testing.T) {
+	failure := errorx.E("connection refused", errorx.CodeGateway)
+	notFound := errorx.E("key not found", errorx.CodeNotFound)
+
+	type step struct {
+		// wait moves the clock before the command.
+		wait        time.Duration
+		err         error
+		wantAllowed bool
+		wantState   CircuitState
+	}
+	tests := []struct {
+		name   string
+		config CircuitBreakerConfig
+		steps  []step
+	}{
+		{
+			name: "Consecutive failures",
+			config: CircuitBreakerConfig{
+				ConsecutiveFailures: 2,
+				OpenTimeout:         time.Second,
+			},
+			steps: []step{
+				{err: failure, wantAllowed: true, wantState: CircuitClosed},
+				{err: nil, wantAllowed: true, wantState: CircuitClosed},
+				{err: failure, wantAllowed: true, wantState: CircuitClosed},
+				{err: failure, wantAllowed: true, wantState: CircuitOpen},
+				{err: nil, wantAllowed: false, wantState: CircuitOpen},
+			},
+		},
+		{
+			name: "Results of the commands aren't failures",
+			config: CircuitBreakerConfig{
+				ConsecutiveFailures: 1,
+			},
+			steps: []step{
+				{err: notFound, wantAllowed: true, wantState: CircuitClosed},
+				{err: errorx.E(context.Canceled, errorx.CodeGateway), wantAllowed: true, wantState: CircuitClosed},
+				{err: errorx.E(context.DeadlineExceeded, errorx.CodeGateway), wantAllowed: true, wantState: CircuitOpen},
+			},
+		},
+		{
+			name: "Failure rate",
+			config: CircuitBreakerConfig{
+				FailureRate: 0.5,
+				MinRequests: 4,
+				Window:      time.Minute,
+			},
+			steps: []step{
+				{err: failure, wantAllowed: true, wantState: CircuitClosed},
+				{err: nil, wantAllowed: true, wantState: CircuitClosed},
+				{err: failure, wantAllowed: true, wantState: CircuitClosed},
+				{err: nil, wantAllowed: true, wantState: CircuitOpen},
+			},
+		},
+		{
+			name: "Failure rate reset by the window",
+			config: CircuitBreakerConfig{
+				FailureRate: 0.5,
+				MinRequests: 2,
+				Window:      time.Minute,
+			},
+			steps: []step{
+				{err: failure, wantAllowed: true, wantState: CircuitClosed},
+				{wait: time.Minute, err: nil, wantAllowed: true, wantState: CircuitClosed},
+				{err: nil, wantAllowed: true, wantState: CircuitClosed},
+				{err: failure, wantAllowed: true, wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "Closed after the trials",
+			config: CircuitBreakerConfig{
+				ConsecutiveFailures: 1,
+				OpenTimeout:         time.Second,
+				HalfOpenRequests:    2,
+			},
+			steps: []step{
+				{err: failure, wantAllowed: true, wantState: CircuitOpen},
+				{wait: 500 * time.Millisecond, err: nil, wantAllowed: false, wantState: CircuitOpen},
+				{wait: 500 * time.Millisecond, err: nil, wantAllowed: true, wantState: CircuitHalfOpen},
+				{err: nil, wantAllowed: true, wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "Closed by a missing key",
+			config: CircuitBreakerConfig{
+				ConsecutiveFailures: 1,
+				OpenTimeout:         time.Second,
+			},
+			steps: []step{
+				{err: failure, wantAllowed: true, wantState: CircuitOpen},
+				{wait: time.Second, err: notFound, wantAllowed: true, wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "Trial released by a cancelled context",
+			config: CircuitBreakerConfig{
+				ConsecutiveFailures: 1,
+				OpenTimeout:         time.Second,
+			},
+			steps: []step{
+				{err: failure, wantAllowed: true, wantState: CircuitOpen},
+				{wait: time.Second, err: context.Canceled, wantAllowed: true, wantState: CircuitHalfOpen},
+				{err: nil, wantAllowed: true, wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "Opened by a failed trial",
+			config: CircuitBreakerConfig{
+				ConsecutiveFailures: 1,
+				OpenTimeout:         time.Second,
+			},
+			steps: []step{
+				{err: failure, wantAllowed: true, wantState: CircuitOpen},
+				{wait: time.Second, err: failure, wantAllowed: true, wantState: CircuitOpen},
+				{err: nil, wantAllowed: false, wantState: CircuitOpen},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Now()}
+			c := newCircuit("session", tt.config)
+			c.now = clock.Now
+			c.expiry = c.windowEnd(clock.now)
+
+			ctx := context.Background()
+			for i, v := range tt.steps {
+				clock.now = clock.now.Add(v.wait)
+
+				generation, err := c.allow(ctx)
+				assert.Equal(t, v.wantAllowed, err == nil, "step %d", i)
+				if err == nil {
+					c.done(ctx, generation, v.err)
+				} else {
+					assert.True(t, errorx.Is(errorx.CodeCircuitBreaker, err), "step %d", i)
+				}
+				assert.Equal(t, v.wantState, c.State(), "step %d", i)
+			}
+		})
+	}
+}
+
+func TestCircuit_HalfOpenTrials(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	c := newCircuit("session", CircuitBreakerConfig{ConsecutiveFailures: 1, OpenTimeout: time.Second})
+	c.now = clock.Now
+
+	ctx := context.Background()
+	generation, err := c.allow(ctx)
+	assert.NoError(t, err)
+	c.done(ctx, generation, errorx.E("connection refused", errorx.CodeGateway))
+
+	// A single trial is let through at a time.
+	clock.now = clock.now.Add(time.Second)
+	trial, err := c.allow(ctx)
+	assert.NoError(t, err)
+	_, err = c.allow(ctx)
+	assert.True(t, errorx.Is(errorx.CodeCircuitBreaker, err))
+
+	// The results of the commands let through by the former states are ignored.
+	c.done(ctx, generation, nil)
+	assert.Equal(t, CircuitHalfOpen, c.State())
+
+	c.done(ctx, trial, nil)
+	assert.Equal(t, CircuitClosed, c.State())
+}
+
+func TestCircuit_HalfOpenTimeout(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	c := newCircuit("session", CircuitBreakerConfig{ConsecutiveFailures: 1, OpenTimeout: time.Second})
+	c.now = clock.Now
+
+	ctx := context.Background()
+	generation, err := c.allow(ctx)
+	assert.NoError(t, err)
+	c.done(ctx, generation, errorx.E("connection refused", errorx.CodeGateway))
+
+	// The trial never completes.
+	clock.now = clock.now.Add(time.Second)
+	_, err = c.allow(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, CircuitHalfOpen, c.State())
+
+	// The circuit opens again once the trial timed out, then lets a new trial through.
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, CircuitOpen, c.State())
+
+	clock.now = clock.now.Add(time.Second)
+	trial, err := c.allow(ctx)
+	assert.NoError(t, err)
+	c.done(ctx, trial, nil)
+	assert.Equal(t, CircuitClosed, c.State())
+}
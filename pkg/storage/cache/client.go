@@ -0,0 +1,440 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime/debug"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/stack"
+	"github.com/peractio/gdk/pkg/tags"
+	"github.com/segmentio/ksuid"
+)
+
+// ClientConfig configuration.
+type ClientConfig struct {
+	// StaleTTL is the duration a value is kept after it expires.
+	// GetOrLoad returns the stale value and refreshes it in the background.
+	// Zero value means the stale value is never returned.
+	StaleTTL time.Duration
+	// EarlyRefreshBeta scales the probability to refresh a value before it expires.
+	// The probability grows as the value gets closer to its expiry,
+	// and values that take longer to load are refreshed earlier.
+	// One is a good default, bigger value favors earlier refresh.
+	// Zero value disables the early refresh.
+	EarlyRefreshBeta float64
+	// Locker is used to prevent a key from being loaded by multiple processes at the same time.
+	// Nil value means concurrent loads are only collapsed within the current process.
+	Locker LockerItf
+	// LockTTL is the maximum duration a process can hold the load lock.
+	LockTTL time.Duration
+	// LockWait is the maximum duration to wait for the value loaded by other process.
+	// After that, the current process loads the value by itself.
+	LockWait time.Duration
+	// RefreshTimeout is the maximum duration of a background refresh.
+	RefreshTimeout time.Duration
+	// LoadTimeout is the maximum duration of a load shared by the concurrent callers of GetOrLoad.
+	// The load is detached from the caller context, so one cancelled caller doesn't fail the others.
+	LoadTimeout time.Duration
+	// NegativeTTL is the duration to remember a key that is missing from the source of truth.
+	// GetOrLoad stores a negative entry when the loader returns an error with errorx.CodeNotFound,
+	// and reports the key as not found without calling the loader until the entry expires.
+	// It should be shorter than the ttl of the value.
+	// Zero value disables the negative caching.
+	NegativeTTL time.Duration
+	// Tagger keeps the tag sets of the tagged keys and the versions of the namespaces,
+	// usually the redis client of the store.
+	// Nil value means the keys can't be tagged nor namespaced.
+	Tagger TaggerItf
+	// TagTTL is the ttl of a tag set, refreshed whenever a key is tagged.
+	// It must not be shorter than the ttl of the tagged keys, or the keys would outlive their tag,
+	// so SetObject rejects the tagged keys with a longer ttl.
+	TagTTL time.Duration
+}
+
+var (
+	// DefaultClientConfig is the default client config.
+	DefaultClientConfig = ClientConfig{
+		StaleTTL:         0,
+		EarlyRefreshBeta: 1,
+		Locker:           nil,
+		LockTTL:          5 * time.Second,
+		LockWait:         time.Second,
+		RefreshTimeout:   5 * time.Second,
+		LoadTimeout:      5 * time.Second,
+		NegativeTTL:      0,
+		Tagger:           nil,
+		TagTTL:           24 * time.Hour,
+	}
+
+	// lockPollInterval is the interval to check the value loaded by other process.
+	lockPollInterval = 50 * time.Millisecond
+)
+
+// ObjectLoadFunc loads the value from the source of truth, usually the database.
+type ObjectLoadFunc func(ctx context.Context) (interface{}, error)
+
+// Client is a typed cache client.
+// It encodes the value with a codec before storing it,
+// so callers don't need to marshal and unmarshal the value by themselves.
+//
+// Example:
+//
+//	client := cache.NewClient(cache.NewRedisStore(redigo), cache.NewJSONCodec(nil))
+//	err := client.SetObject(ctx, "user:1", time.Minute, user)
+//	err = client.GetObject(ctx, "user:1", &user)
+type Client struct {
+	store  Store
+	codec  Codec
+	config ClientConfig
+	group  group
+}
+
+// NewClient returns a typed cache client with default config.
+// Nil codec means the client will use json codec.
+func NewClient(store Store, codec Codec) *Client {
+	return NewClientWithConfig(store, codec, DefaultClientConfig)
+}
+
+// NewClientWithConfig returns a typed cache client with custom config.
+// Nil codec means the client will use json codec.
+func NewClientWithConfig(store Store, codec Codec, config ClientConfig) *Client {
+	if codec == nil {
+		codec = NewJSONCodec(nil)
+	}
+	if config.LockTTL <= 0 {
+		config.LockTTL = DefaultClientConfig.LockTTL
+	}
+	if config.LockWait <= 0 {
+		config.LockWait = DefaultClientConfig.LockWait
+	}
+	if config.RefreshTimeout <= 0 {
+		config.RefreshTimeout = DefaultClientConfig.RefreshTimeout
+	}
+	if config.LoadTimeout <= 0 {
+		config.LoadTimeout = DefaultClientConfig.LoadTimeout
+	}
+	if config.TagTTL <= 0 {
+		config.TagTTL = DefaultClientConfig.TagTTL
+	}
+
+	return &Client{
+		store:  store,
+		codec:  codec,
+		config: config,
+	}
+}
+
+// GetObject gets the value of a key and decodes it into dst.
+// Dst must be a pointer.
+// Missing key and negative entry are reported as an error with errorx.CodeNotFound.
+func (c *Client) GetObject(ctx context.Context, key string, dst interface{}) error {
+	const op errorx.Op = "cache/Client.GetObject"
+
+	res, err := c.store.Get(ctx, key)
+	if err != nil {
+		return errorx.E(err, op)
+	}
+
+	return c.decode(op, decodeEntry(res), dst)
+}
+
+// SetObject encodes the value and sets it to a key that will expire after the ttl has passed.
+// The key is added to the tags, so it is deleted when any of them is invalidated, see InvalidateTag.
+// Tagging the key requires the Tagger to be configured, and a ttl not longer than the TagTTL.
+func (c *Client) SetObject(ctx context.Context, key string, ttl time.Duration, v interface{}, keyTags ...string) error {
+	const op errorx.Op = "cache/Client.SetObject"
+
+	res, err := c.codec.Marshal(v)
+	if err != nil {
+		return errorx.E(err, op, errorx.CodeInternal)
+	}
+
+	// The key is tagged first, so it is never stored without its tags.
+	if err := c.tag(ctx, key, ttl, keyTags); err != nil {
+		return errorx.E(err, op)
+	}
+
+	if err := c.store.Set(ctx, key, res, ttl); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// SetNotFound stores a negative entry that will expire after the ttl has passed.
+// The key is reported as not found until the entry expires or is replaced.
+func (c *Client) SetNotFound(ctx context.Context, key string, ttl time.Duration) error {
+	const op errorx.Op = "cache/Client.SetNotFound"
+
+	e := &entry{
+		kind:   entryKindNotFound,
+		expiry: time.Now().Add(ttl),
+		value:  nil,
+	}
+
+	if err := c.store.Set(ctx, key, encodeEntry(e), ttl); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// GetOrLoad gets the value of a key and decodes it into dst.
+// On a miss, the value is loaded by the loader, stored for the ttl, and decoded into dst.
+// Concurrent loads of the same key are collapsed into a single load within the process,
+// and across processes when the Locker is configured.
+// Loader error is returned as is and never stored,
+// except errorx.CodeNotFound which is stored as a negative entry when NegativeTTL is configured.
+func (c *Client) GetOrLoad(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	dst interface{},
+	loader ObjectLoadFunc,
+) error {
+	const op errorx.Op = "cache/Client.GetOrLoad"
+
+	res, err := c.store.Get(ctx, key)
+	if err != nil && !IsNotFound(err) {
+		// The store is degraded, serve the request from the loader.
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" store failed")
+	}
+
+	if err == nil {
+		e := decodeEntry(res)
+		now := time.Now()
+
+		switch {
+		case e.isStale(now) && e.kind == entryKindValue && c.config.StaleTTL > 0:
+			// Serve the stale value while it is being revalidated.
+			c.refresh(ctx, key, ttl, loader)
+			return c.decode(op, e, dst)
+		case e.isStale(now):
+			// The value is expired, treat it as a miss.
+		case e.kind == entryKindValue && c.shouldRefreshEarly(e, now):
+			c.refresh(ctx, key, ttl, loader)
+			return c.decode(op, e, dst)
+		default:
+			return c.decode(op, e, dst)
+		}
+	}
+
+	// The load is shared with the other callers, so it runs on a detached context,
+	// and the caller stops waiting for it when its own context is done.
+	done := make(chan loadResult, 1)
+	loadCtx := logx.SetRequestID(context.Background(), logx.GetRequestID(ctx))
+	go func() {
+		loadCtx, cancel := context.WithTimeout(loadCtx, c.config.LoadTimeout)
+		defer cancel()
+
+		var r loadResult
+		r.res, r.shared, r.err = c.group.do(key, func() ([]byte, error) {
+			return c.safeLoad(loadCtx, key, ttl, loader)
+		})
+		done <- r
+	}()
+
+	var r loadResult
+	select {
+	case <-ctx.Done():
+		return errorx.E(ctx.Err(), op, errorx.CodeGateway)
+	case r = <-done:
+	}
+	if r.err != nil {
+		return errorx.E(r.err, op)
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: key, tags.Shared: r.shared}, string(op)+" loaded")
+	return c.decode(op, decodeEntry(r.res), dst)
+}
+
+// loadResult is the result of a load shared by the concurrent callers.
+type loadResult struct {
+	res    []byte
+	shared bool
+	err    error
+}
+
+// Del deletes a key.
+func (c *Client) Del(ctx context.Context, key string) error {
+	const op errorx.Op = "cache/Client.Del"
+
+	if err := c.store.Del(ctx, key); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// safeLoad calls load, and reports a panic of the loader as an error with errorx.CodeInternal,
+// since the load runs on its own goroutine where nothing else recovers it.
+// The error is given to all the callers sharing the load.
+func (c *Client) safeLoad(ctx context.Context, key string, ttl time.Duration, loader ObjectLoadFunc) (res []byte, err error) {
+	const op errorx.Op = "cache/Client.safeLoad"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errorx.E(fmt.Errorf("%v", r), op, errorx.CodeInternal, errorx.Fields{
+				tags.Key:        key,
+				tags.Panic:      r,
+				tags.StackTrace: stack.ToArr(stack.Trim(debug.Stack())),
+			})
+			logx.ERR(ctx, err, string(op)+" loader panicked")
+		}
+	}()
+
+	return c.load(ctx, key, ttl, loader)
+}
+
+// load loads the value and stores it, then returns the stored entry.
+// Negative entry is returned as an error with errorx.CodeNotFound.
+func (c *Client) load(ctx context.Context, key string, ttl time.Duration, loader ObjectLoadFunc) ([]byte, error) {
+	const op errorx.Op = "cache/Client.load"
+
+	if c.config.Locker != nil {
+		unlock, res := c.lock(ctx, key)
+		if res != nil {
+			if res.kind == entryKindNotFound {
+				return nil, errorx.E("key is cached as not found", op, errorx.CodeNotFound)
+			}
+			return encodeEntry(res), nil
+		}
+		defer unlock()
+	}
+
+	start := time.Now()
+	v, err := loader(ctx)
+	if IsNotFound(err) && c.config.NegativeTTL > 0 {
+		if err := c.SetNotFound(ctx, key, c.config.NegativeTTL); err != nil {
+			logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" store failed")
+		}
+	}
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	value, err := c.codec.Marshal(v)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeInternal)
+	}
+
+	e := &entry{
+		kind:   entryKindValue,
+		expiry: time.Now().Add(ttl),
+		delta:  time.Since(start),
+		value:  value,
+	}
+
+	// Failing to store the value should not fail the load.
+	res := encodeEntry(e)
+	if err := c.store.Set(ctx, key, res, ttl+c.config.StaleTTL); err != nil {
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" store failed")
+	}
+
+	return res, nil
+}
+
+// lock acquires the load lock of a key.
+// When the lock is held by other process, it waits for the loaded entry and returns it.
+// Unlock is a no-op when the lock is not acquired.
+func (c *Client) lock(ctx context.Context, key string) (unlock func(), res *entry) {
+	const op errorx.Op = "cache/Client.lock"
+
+	lockKey := key + ":lock"
+	token := ksuid.New().String()
+	acquired, err := c.config.Locker.SetNX(ctx, lockKey, toSeconds(c.config.LockTTL), token)
+	if err != nil {
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" failed")
+	}
+	if acquired {
+		return func() {
+			// The lock may have expired and been acquired by other process,
+			// so it is only deleted while it still holds our token.
+			if _, err := c.config.Locker.RunScript(ctx, ScriptLockRelease, []string{lockKey}, token); err != nil {
+				logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" release failed")
+			}
+		}, nil
+	}
+
+	// Wait for the value loaded by other process.
+	deadline := time.Now().Add(c.config.LockWait)
+	for err == nil && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return func() {}, nil
+		case <-time.After(lockPollInterval):
+		}
+
+		data, getErr := c.store.Get(ctx, key)
+		if getErr == nil {
+			if e := decodeEntry(data); !e.isStale(time.Now()) {
+				return func() {}, e
+			}
+		}
+	}
+
+	return func() {}, nil
+}
+
+// refresh reloads the value in the background.
+// Refreshes of the same key are collapsed with the in-flight loads.
+func (c *Client) refresh(ctx context.Context, key string, ttl time.Duration, loader ObjectLoadFunc) {
+	const op errorx.Op = "cache/Client.refresh"
+
+	// Detach from the request context, since the request may end before the refresh.
+	refreshCtx := logx.SetRequestID(context.Background(), logx.GetRequestID(ctx))
+
+	go func() {
+		refreshCtx, cancel := context.WithTimeout(refreshCtx, c.config.RefreshTimeout)
+		defer cancel()
+
+		_, _, err := c.group.do(key, func() ([]byte, error) {
+			return c.safeLoad(refreshCtx, key, ttl, loader)
+		})
+		if err != nil {
+			logx.WRN(refreshCtx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" failed")
+		}
+	}()
+}
+
+// shouldRefreshEarly decides whether a fresh value should be refreshed before it expires.
+// It uses the probabilistic early expiration, refresh when:
+//
+//	now - delta * beta * ln(random) >= expiry
+//
+// See https://cseweb.ucsd.edu/~avattani/papers/cache_stampede.pdf
+func (c *Client) shouldRefreshEarly(e *entry, now time.Time) bool {
+	if c.config.EarlyRefreshBeta <= 0 || e.expiry.IsZero() || e.delta <= 0 {
+		return false
+	}
+
+	// Random value in (0, 1], so the logarithm is finite.
+	random := 1 - rand.Float64() // nolint:gosec
+	gap := -float64(e.delta) * c.config.EarlyRefreshBeta * math.Log(random)
+
+	return !now.Add(time.Duration(gap)).Before(e.expiry)
+}
+
+// decode decodes the entry value into dst.
+// Negative entry is reported as an error with errorx.CodeNotFound.
+func (c *Client) decode(op errorx.Op, e *entry, dst interface{}) error {
+	if e.kind == entryKindNotFound {
+		return errorx.E("key is cached as not found", op, errorx.CodeNotFound)
+	}
+
+	if err := c.codec.Unmarshal(e.value, dst); err != nil {
+		return errorx.E(err, op, errorx.CodeInternal)
+	}
+
+	return nil
+}
+
+// IsNotFound reports whether the error is caused by a missing key.
+func IsNotFound(err error) bool {
+	return errorx.Is(errorx.CodeNotFound, err)
+}
@@ -0,0 +1,586 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(local *MockRistrettoItf)
+		want     codecTestValue
+		wantCode errorx.Code
+	}{
+		{
+			name: "Not found",
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+			},
+			want:     codecTestValue{},
+			wantCode: errorx.CodeNotFound,
+		},
+		{
+			name: "Invalid stored value",
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Lookup(gomock.Any(), "key").Return("not bytes", nil)
+			},
+			want:     codecTestValue{},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name: "Broken stored value",
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Lookup(gomock.Any(), "key").Return([]byte("broken"), nil)
+			},
+			want:     codecTestValue{},
+			wantCode: errorx.CodeInternal,
+		},
+		{
+			name: "Success",
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Lookup(gomock.Any(), "key").Return([]byte(`{"ID":1,"Name":"name"}`), nil)
+			},
+			want:     codecTestValue{ID: 1, Name: "name"},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			local := NewMockRistrettoItf(ctrl)
+			tt.mock(local)
+
+			var got codecTestValue
+			err := NewClient(NewRistrettoStore(local), nil).GetObject(context.Background(), "key", &got)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_SetObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		value    interface{}
+		mock     func(redis *MockRedisItf)
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Unsupported value",
+			ttl:      time.Second,
+			value:    make(chan int),
+			mock:     func(redis *MockRedisItf) {},
+			wantCode: errorx.CodeInternal,
+		},
+		{
+			name:     "Invalid ttl",
+			ttl:      0,
+			value:    codecTestValue{ID: 1},
+			mock:     func(redis *MockRedisItf) {},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:  "Redis error",
+			ttl:   time.Second,
+			value: codecTestValue{ID: 1},
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().SetEX(gomock.Any(), "key", int64(1), gomock.Any()).
+					Return(errorx.E(errors.New("redis error"), errorx.CodeGateway))
+			},
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name:  "Success with rounded up ttl",
+			ttl:   1500 * time.Millisecond,
+			value: codecTestValue{ID: 1},
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().SetEX(gomock.Any(), "key", int64(2), `{"ID":1,"Name":"","Tags":null}`).Return(nil)
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			redis := NewMockRedisItf(ctrl)
+			tt.mock(redis)
+
+			err := NewClient(NewRedisStore(redis), nil).SetObject(context.Background(), "key", tt.ttl, tt.value)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+		})
+	}
+}
+
+func TestClient_Del(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redis := NewMockRedisItf(ctrl)
+	redis.EXPECT().Del(gomock.Any(), "key").Return(int64(1), nil)
+	redis.EXPECT().Del(gomock.Any(), "key").Return(int64(0), errorx.E("redis error", errorx.CodeGateway))
+
+	client := NewClient(NewRedisStore(redis), nil)
+	assert.NoError(t, client.Del(context.Background(), "key"))
+	assert.True(t, errorx.Is(errorx.CodeGateway, client.Del(context.Background(), "key")))
+}
+
+func TestRedisStore_Get(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(redis *MockRedisItf)
+		want     []byte
+		wantCode errorx.Code
+	}{
+		{
+			name: "Not found",
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+			},
+			want:     nil,
+			wantCode: errorx.CodeNotFound,
+		},
+		{
+			name: "Redis error",
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:     nil,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Success with empty value",
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().Get(gomock.Any(), "key").Return([]byte{}, nil)
+			},
+			want:     []byte{},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			redis := NewMockRedisItf(ctrl)
+			tt.mock(redis)
+
+			got, err := NewRedisStore(redis).Get(context.Background(), "key")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCode == errorx.CodeNotFound, IsNotFound(err))
+		})
+	}
+}
+
+// mapStore is a thread safe store for testing.
+type mapStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	sets int
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: map[string][]byte{}}
+}
+
+func (s *mapStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, ok := s.data[key]
+	if !ok {
+		return nil, errorx.E("key not found", errorx.CodeNotFound)
+	}
+	return res, nil
+}
+
+func (s *mapStore) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sets++
+	s.data[key] = value
+	return nil
+}
+
+func (s *mapStore) Del(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, key)
+	return nil
+}
+
+func (s *mapStore) setCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sets
+}
+
+func TestClient_GetOrLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    ClientConfig
+		stored    *entry
+		loader    ObjectLoadFunc
+		want      codecTestValue
+		wantCode  errorx.Code
+		wantLoads int
+	}{
+		{
+			name:   "Loader error",
+			config: DefaultClientConfig,
+			stored: nil,
+			loader: func(ctx context.Context) (interface{}, error) {
+				return nil, errorx.E("db error", errorx.CodeDB)
+			},
+			want:      codecTestValue{},
+			wantCode:  errorx.CodeDB,
+			wantLoads: 0,
+		},
+		{
+			name:   "Miss",
+			config: DefaultClientConfig,
+			stored: nil,
+			loader: func(ctx context.Context) (interface{}, error) {
+				return codecTestValue{ID: 2}, nil
+			},
+			want:      codecTestValue{ID: 2},
+			wantCode:  errorx.CodeUnknown,
+			wantLoads: 1,
+		},
+		{
+			name:   "Hit",
+			config: ClientConfig{},
+			stored: &entry{kind: entryKindValue, expiry: time.Now().Add(time.Minute), value: []byte(`{"ID":1}`)},
+			loader: func(ctx context.Context) (interface{}, error) {
+				return codecTestValue{ID: 2}, nil
+			},
+			want:      codecTestValue{ID: 1},
+			wantCode:  errorx.CodeUnknown,
+			wantLoads: 0,
+		},
+		{
+			name:   "Expired without stale ttl",
+			config: ClientConfig{},
+			stored: &entry{kind: entryKindValue, expiry: time.Now().Add(-time.Second), value: []byte(`{"ID":1}`)},
+			loader: func(ctx context.Context) (interface{}, error) {
+				return codecTestValue{ID: 2}, nil
+			},
+			want:      codecTestValue{ID: 2},
+			wantCode:  errorx.CodeUnknown,
+			wantLoads: 1,
+		},
+		{
+			name:   "Stale while revalidate",
+			config: ClientConfig{StaleTTL: time.Minute},
+			stored: &entry{kind: entryKindValue, expiry: time.Now().Add(-time.Second), value: []byte(`{"ID":1}`)},
+			loader: func(ctx context.Context) (interface{}, error) {
+				return codecTestValue{ID: 2}, nil
+			},
+			want:      codecTestValue{ID: 1},
+			wantCode:  errorx.CodeUnknown,
+			wantLoads: 1,
+		},
+		{
+			name:   "Early refresh",
+			config: ClientConfig{EarlyRefreshBeta: 1e9},
+			stored: &entry{
+				kind:   entryKindValue,
+				expiry: time.Now().Add(time.Minute),
+				delta:  time.Second,
+				value:  []byte(`{"ID":1}`),
+			},
+			loader: func(ctx context.Context) (interface{}, error) {
+				return codecTestValue{ID: 2}, nil
+			},
+			want:      codecTestValue{ID: 1},
+			wantCode:  errorx.CodeUnknown,
+			wantLoads: 1,
+		},
+		{
+			name:   "Not found without negative ttl",
+			config: DefaultClientConfig,
+			stored: nil,
+			loader: func(ctx context.Context) (interface{}, error) {
+				return nil, errorx.E("row not found", errorx.CodeNotFound)
+			},
+			want:      codecTestValue{},
+			wantCode:  errorx.CodeNotFound,
+			wantLoads: 0,
+		},
+		{
+			name:   "Not found with negative ttl",
+			config: ClientConfig{NegativeTTL: time.Second},
+			stored: nil,
+			loader: func(ctx context.Context) (interface{}, error) {
+				return nil, errorx.E("row not found", errorx.CodeNotFound)
+			},
+			want:      codecTestValue{},
+			wantCode:  errorx.CodeNotFound,
+			wantLoads: 1,
+		},
+		{
+			name:   "Negative hit",
+			config: ClientConfig{NegativeTTL: time.Second},
+			stored: &entry{kind: entryKindNotFound, expiry: time.Now().Add(time.Minute)},
+			loader: func(ctx context.Context) (interface{}, error) {
+				return codecTestValue{ID: 2}, nil
+			},
+			want:      codecTestValue{},
+			wantCode:  errorx.CodeNotFound,
+			wantLoads: 0,
+		},
+		{
+			name:   "Negative expired",
+			config: ClientConfig{NegativeTTL: time.Second, StaleTTL: time.Minute},
+			stored: &entry{kind: entryKindNotFound, expiry: time.Now().Add(-time.Second)},
+			loader: func(ctx context.Context) (interface{}, error) {
+				return codecTestValue{ID: 2}, nil
+			},
+			want:      codecTestValue{ID: 2},
+			wantCode:  errorx.CodeUnknown,
+			wantLoads: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMapStore()
+			if tt.stored != nil {
+				store.data["key"] = encodeEntry(tt.stored)
+			}
+
+			var got codecTestValue
+			client := NewClientWithConfig(store, nil, tt.config)
+			err := client.GetOrLoad(context.Background(), "key", time.Minute, &got, tt.loader)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+
+			// Wait for the background refresh.
+			assert.Eventually(t, func() bool {
+				return store.setCount() == tt.wantLoads
+			}, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestClient_GetOrLoad_Concurrent(t *testing.T) {
+	store := newMapStore()
+	client := NewClient(store, nil)
+
+	var loads int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return codecTestValue{ID: 1}, nil
+	}
+
+	const total = 10
+	var wg sync.WaitGroup
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var got codecTestValue
+			assert.NoError(t, client.GetOrLoad(context.Background(), "key", time.Minute, &got, loader))
+			assert.Equal(t, codecTestValue{ID: 1}, got)
+		}()
+	}
+
+	// Give the callers some time to join the in-flight load.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+}
+
+func TestClient_GetOrLoad_CancelledCaller(t *testing.T) {
+	store := newMapStore()
+	client := NewClient(store, nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-release
+		// The shared load isn't cancelled with the caller that started it.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return codecTestValue{ID: 1}, nil
+	}
+
+	// The first caller starts the load and gives up before it ends.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		var got codecTestValue
+		first <- client.GetOrLoad(ctx, "key", time.Minute, &got, loader)
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	var got codecTestValue
+	go func() {
+		second <- client.GetOrLoad(context.Background(), "key", time.Minute, &got, loader)
+	}()
+
+	// Give the second caller some time to join the in-flight load.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.Equal(t, errorx.CodeGateway, errorx.GetCode(<-first))
+
+	close(release)
+	assert.NoError(t, <-second)
+	assert.Equal(t, codecTestValue{ID: 1}, got)
+}
+
+func TestClient_GetOrLoad_PanickingLoader(t *testing.T) {
+	store := newMapStore()
+	client := NewClientWithConfig(store, nil, ClientConfig{StaleTTL: time.Minute})
+
+	release := make(chan struct{})
+	loads := make(chan struct{}, 2)
+	loader := func(ctx context.Context) (interface{}, error) {
+		loads <- struct{}{}
+		<-release
+		panic("loader failed")
+	}
+
+	// The panic of the shared load is given to all of its callers.
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			var got codecTestValue
+			errs <- client.GetOrLoad(context.Background(), "key", time.Minute, &got, loader)
+		}()
+	}
+	<-loads
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	assert.Equal(t, errorx.CodeInternal, errorx.GetCode(<-errs))
+	assert.Equal(t, errorx.CodeInternal, errorx.GetCode(<-errs))
+
+	// The panic of a background refresh doesn't crash the process.
+	stale := &entry{kind: entryKindValue, expiry: time.Now().Add(-time.Second), value: []byte(`{"ID":1}`)}
+	assert.NoError(t, store.Set(context.Background(), "stale", encodeEntry(stale), 0))
+
+	var got codecTestValue
+	assert.NoError(t, client.GetOrLoad(context.Background(), "stale", time.Minute, &got, loader))
+	assert.Equal(t, codecTestValue{ID: 1}, got)
+	<-loads
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestClient_GetOrLoad_Locker(t *testing.T) {
+	tests := []struct {
+		name      string
+		mock      func(locker *MockLockerItf, store *mapStore)
+		want      codecTestValue
+		wantLoads int32
+	}{
+		{
+			name: "Lock acquired",
+			mock: func(locker *MockLockerItf, store *mapStore) {
+				var token string
+				locker.EXPECT().SetNX(gomock.Any(), "key:lock", int64(1), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ int64, value string) (bool, error) {
+						token = value
+						return true, nil
+					})
+				locker.EXPECT().RunScript(gomock.Any(), ScriptLockRelease, []string{"key:lock"}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *Script, _ []string, args ...interface{}) (interface{}, error) {
+						// The lock is released with the token it was acquired with.
+						assert.Equal(t, []interface{}{token}, args)
+						return int64(1), nil
+					})
+			},
+			want:      codecTestValue{ID: 2},
+			wantLoads: 1,
+		},
+		{
+			name: "Lock held by other process",
+			mock: func(locker *MockLockerItf, store *mapStore) {
+				locker.EXPECT().SetNX(gomock.Any(), "key:lock", int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ string, _ int64, _ string) (bool, error) {
+						// Other process stores the value while holding the lock.
+						value := &entry{kind: entryKindValue, expiry: time.Now().Add(time.Minute), value: []byte(`{"ID":1}`)}
+						_ = store.Set(ctx, "key", encodeEntry(value), time.Minute)
+						return false, nil
+					})
+			},
+			want:      codecTestValue{ID: 1},
+			wantLoads: 0,
+		},
+		{
+			name: "Lock error",
+			mock: func(locker *MockLockerItf, store *mapStore) {
+				locker.EXPECT().SetNX(gomock.Any(), "key:lock", int64(1), gomock.Any()).
+					Return(false, errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:      codecTestValue{ID: 2},
+			wantLoads: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMapStore()
+			locker := NewMockLockerItf(ctrl)
+			tt.mock(locker, store)
+
+			var loads int32
+			client := NewClientWithConfig(store, nil, ClientConfig{
+				Locker:   locker,
+				LockTTL:  time.Second,
+				LockWait: time.Second,
+			})
+
+			var got codecTestValue
+			err := client.GetOrLoad(context.Background(), "key", time.Minute, &got, func(ctx context.Context) (interface{}, error) {
+				atomic.AddInt32(&loads, 1)
+				return codecTestValue{ID: 2}, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantLoads, atomic.LoadInt32(&loads))
+		})
+	}
+}
+
+func TestClient_SetNotFound(t *testing.T) {
+	store := newMapStore()
+	client := NewClient(store, nil)
+
+	assert.NoError(t, client.SetNotFound(context.Background(), "key", time.Minute))
+
+	var got codecTestValue
+	err := client.GetObject(context.Background(), "key", &got)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, codecTestValue{}, got)
+
+	// The negative entry is replaced by a value.
+	assert.NoError(t, client.SetObject(context.Background(), "key", time.Minute, codecTestValue{ID: 1}))
+	assert.NoError(t, client.GetObject(context.Background(), "key", &got))
+	assert.Equal(t, codecTestValue{ID: 1}, got)
+}
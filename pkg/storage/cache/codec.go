@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/peractio/gdk/pkg/jsonx"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec encodes a value into bytes before it is stored in the cache,
+// and decodes the stored bytes back into a value.
+type Codec interface {
+	// Marshal returns the encoded bytes of v.
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal decodes data into v, v must be a pointer.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is a codec using json format.
+type JSONCodec struct {
+	json jsonx.OperatorItf
+}
+
+// NewJSONCodec returns a json codec using the given json operator.
+// Nil operator means the codec will use the default operator from jsonx.New.
+func NewJSONCodec(json jsonx.OperatorItf) *JSONCodec {
+	if json == nil {
+		json = jsonx.New()
+	}
+
+	return &JSONCodec{
+		json: json,
+	}
+}
+
+// Marshal returns the json encoding of v.
+func (c *JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return c.json.Marshal(v)
+}
+
+// Unmarshal parses the json encoded data and stores the result in v.
+func (c *JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return c.json.Unmarshal(data, v)
+}
+
+// GobCodec is a codec using gob format.
+// Interface values must be registered with gob.Register before being encoded.
+type GobCodec struct{}
+
+// NewGobCodec returns a gob codec.
+func NewGobCodec() *GobCodec {
+	return &GobCodec{}
+}
+
+// Marshal returns the gob encoding of v.
+func (c *GobCodec) Marshal(v interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	if err := gob.NewEncoder(buffer).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Unmarshal parses the gob encoded data and stores the result in v.
+func (c *GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// MsgpackCodec is a codec using msgpack format.
+type MsgpackCodec struct{}
+
+// NewMsgpackCodec returns a msgpack codec.
+func NewMsgpackCodec() *MsgpackCodec {
+	return &MsgpackCodec{}
+}
+
+// Marshal returns the msgpack encoding of v.
+func (c *MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal parses the msgpack encoded data and stores the result in v.
+func (c *MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
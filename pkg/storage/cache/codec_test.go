@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type codecTestValue struct {
+	ID   int64
+	Name string
+	Tags []string
+}
+
+func TestCodec(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+	}{
+		{
+			name:  "JSON",
+			codec: NewJSONCodec(nil),
+		},
+		{
+			name:  "Gob",
+			codec: NewGobCodec(),
+		},
+		{
+			name:  "Msgpack",
+			codec: NewMsgpackCodec(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := codecTestValue{ID: 1, Name: "name", Tags: []string{"a", "b"}}
+
+			data, err := tt.codec.Marshal(want)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var got codecTestValue
+			if assert.NoError(t, tt.codec.Unmarshal(data, &got)) {
+				assert.Equal(t, want, got)
+			}
+
+			assert.Error(t, tt.codec.Unmarshal([]byte("broken"), &got))
+		})
+	}
+}
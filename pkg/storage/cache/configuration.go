@@ -1,5 +1,10 @@
 package cache
 
+import (
+	"crypto/tls"
+	"time"
+)
+
 // RedisConfiguration configuration.
 type RedisConfiguration struct {
 	// IdleConnectionLimit describes maximum idle connection before it closed.
@@ -10,8 +15,24 @@ type RedisConfiguration struct {
 	// for open connection to do operation.
 	WaitOpenConnection bool
 	// Addresses list of available addresses for cluster connection.
+	// The single instance clients connect to the first address.
 	Addresses []string
-	// Enables read-only commands on slave nodes.
+	// MasterName is the name of the master monitored by Redis Sentinel.
+	// When set, Addresses are the sentinel addresses, and the client follows the master on failover.
+	// It isn't supported by the cluster clients.
+	MasterName string
+	// SentinelPassword is the password of the sentinels, empty string means no authentication.
+	SentinelPassword string
+	// Username is the ACL username, empty string means the default user.
+	Username string
+	// Password is the password of the user, empty string means no authentication.
+	Password string
+	// DB is the database selected on connection.
+	// The cluster clients only support the database 0.
+	DB int64
+	// TLSConfig enables TLS with the given configuration, nil means plain TCP.
+	TLSConfig *tls.Config
+	// Enables read-only commands on slave nodes, only supported by the go-redis cluster client.
 	ReadOnly bool
 	// Allows routing read-only commands to the closest master or slave node.
 	// It automatically enables ReadOnly.
@@ -24,12 +45,24 @@ type RedisConfiguration struct {
 	MaxRetries int64
 	// DialTimeout is in seconds.
 	DialTimeout int64
+	// ReadTimeout is the timeout to read a command reply in seconds.
+	// Zero value keeps the default of the library, no timeout for redigo and 3 seconds for go-redis.
+	ReadTimeout int64
+	// WriteTimeout is the timeout to write a command in seconds.
+	// Zero value keeps the default of the library, no timeout for redigo and the read timeout for go-redis.
+	WriteTimeout int64
 	// MaxConnAge is in seconds.
 	MaxConnAge int64
 	// IdleTimeout is in seconds.
 	IdleTimeout int64
 	// MinIdleConns describes the minimum open idle connection to be keep at all time.
 	MinIdleConns int64
+	// SubscribeMinBackoff is the delay before reconnecting a broken subscription,
+	// doubled on every failed attempt. Zero value means 100 milliseconds.
+	SubscribeMinBackoff time.Duration
+	// SubscribeMaxBackoff is the maximum delay before reconnecting a broken subscription.
+	// Zero value means 30 seconds.
+	SubscribeMaxBackoff time.Duration
 }
 
 // RistrettoConfiguration configuration.
@@ -62,4 +95,10 @@ type RistrettoConfiguration struct {
 	// only set this flag to true when testing or throughput performance isn't a
 	// major factor.
 	Metrics bool
+	// Cost computes the cost of the values stored by Set and SetEX, e.g. EncodedSizeCost.
+	// Nil value means the cost of every value is 1, so MaxCost is the maximum number of items.
+	Cost func(value interface{}) int64
+	// InternalCost adds the memory used to store an item, about 50 bytes, to its cost.
+	// It should only be enabled when the costs are sizes in bytes.
+	InternalCost bool
 }
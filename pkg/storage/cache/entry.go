@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+// entryMagic prefixes every stored entry, so entries can be distinguished from raw values.
+// Encoded values never start with a zero byte for json, gob, and msgpack non empty values.
+var entryMagic = []byte{0, 'g', 'd', 'k'}
+
+// Entry kinds.
+const (
+	// entryKindValue describes an entry holding an encoded value.
+	entryKindValue byte = 1
+	// entryKindNotFound describes a negative entry,
+	// the key is known to be missing from the source of truth.
+	entryKindNotFound byte = 2
+)
+
+// entryHeaderLength is the length of magic, kind, expiry, and delta.
+const entryHeaderLength = 4 + 1 + 8 + 8
+
+// entry is the stored form of a value loaded by GetOrLoad, or a negative entry.
+type entry struct {
+	// kind describes the entry kind.
+	kind byte
+	// expiry is the time after which the value is stale and should be refreshed.
+	// Zero value means the value never becomes stale.
+	expiry time.Time
+	// delta is the duration needed to load the value.
+	// It is used to decide the probabilistic early refresh.
+	delta time.Duration
+	// value is the encoded value.
+	value []byte
+}
+
+// encodeEntry returns the stored form of the entry.
+func encodeEntry(e *entry) []byte {
+	res := make([]byte, entryHeaderLength, entryHeaderLength+len(e.value))
+	copy(res, entryMagic)
+	res[4] = e.kind
+
+	var expiry int64
+	if !e.expiry.IsZero() {
+		expiry = e.expiry.UnixNano()
+	}
+	binary.BigEndian.PutUint64(res[5:13], uint64(expiry))
+	binary.BigEndian.PutUint64(res[13:21], uint64(e.delta))
+
+	return append(res, e.value...)
+}
+
+// decodeEntry returns the entry of the stored data.
+// Data without an entry header is treated as a value that never becomes stale.
+func decodeEntry(data []byte) *entry {
+	if len(data) < entryHeaderLength || !bytes.Equal(data[:4], entryMagic) {
+		return &entry{
+			kind:  entryKindValue,
+			value: data,
+		}
+	}
+
+	e := &entry{
+		kind:  data[4],
+		delta: time.Duration(binary.BigEndian.Uint64(data[13:21])),
+		value: data[entryHeaderLength:],
+	}
+	if expiry := int64(binary.BigEndian.Uint64(data[5:13])); expiry != 0 {
+		e.expiry = time.Unix(0, expiry)
+	}
+
+	return e
+}
+
+// isStale reports whether the entry should be refreshed.
+func (e *entry) isStale(now time.Time) bool {
+	return !e.expiry.IsZero() && !now.Before(e.expiry)
+}
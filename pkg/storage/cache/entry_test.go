@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name      string
+		data      []byte
+		want      *entry
+		wantStale bool
+	}{
+		{
+			name:      "Raw value",
+			data:      []byte(`{"ID":1}`),
+			want:      &entry{kind: entryKindValue, value: []byte(`{"ID":1}`)},
+			wantStale: false,
+		},
+		{
+			name: "Fresh entry",
+			data: encodeEntry(&entry{
+				kind:   entryKindValue,
+				expiry: now.Add(time.Second),
+				delta:  time.Millisecond,
+				value:  []byte(`{"ID":1}`),
+			}),
+			want: &entry{
+				kind:   entryKindValue,
+				expiry: now.Add(time.Second),
+				delta:  time.Millisecond,
+				value:  []byte(`{"ID":1}`),
+			},
+			wantStale: false,
+		},
+		{
+			name: "Stale entry",
+			data: encodeEntry(&entry{
+				kind:   entryKindValue,
+				expiry: now,
+				value:  []byte{},
+			}),
+			want: &entry{
+				kind:   entryKindValue,
+				expiry: now,
+				value:  []byte{},
+			},
+			wantStale: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeEntry(tt.data)
+			assert.Equal(t, tt.want.kind, got.kind)
+			assert.True(t, tt.want.expiry.Equal(got.expiry))
+			assert.Equal(t, tt.want.delta, got.delta)
+			assert.Equal(t, tt.want.value, got.value)
+			assert.Equal(t, tt.wantStale, got.isStale(now))
+		})
+	}
+}
@@ -0,0 +1,1534 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/resync"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+var (
+	onceNewGoRedisV8           resync.Once
+	onceNewGoRedisV8Res        *GoRedisV8
+	onceNewGoRedisV8Err        error
+	onceNewGoRedisClusterV8    resync.Once
+	onceNewGoRedisClusterV8Res *GoRedisClusterV8
+	onceNewGoRedisClusterV8Err error
+)
+
+// GoRedisV8 returns a redis client using go-redis library,
+// connected to a single instance, a sentinel monitored master, or a cluster.
+type GoRedisV8 struct {
+	client  redis.UniversalClient
+	backoff subscribeBackoff
+}
+
+// GoRedisClusterV8 returns a redis cluster client using go-redis library.
+type GoRedisClusterV8 = GoRedisV8
+
+// NewGoRedisV8 returns the redis client shared by the package.
+// The client is created on the first call, the later calls return it whatever their configuration.
+// Use NewGoRedisV8Instance or a Registry to hold several clients.
+func NewGoRedisV8(config *RedisConfiguration) (*GoRedisV8, error) {
+	onceNewGoRedisV8.Do(func() {
+		onceNewGoRedisV8Res, onceNewGoRedisV8Err = NewGoRedisV8Instance(config)
+	})
+
+	return onceNewGoRedisV8Res, onceNewGoRedisV8Err
+}
+
+// NewGoRedisV8Instance returns a new redis client connected to a single instance,
+// or to the master monitored by the sentinels when the master name is set.
+func NewGoRedisV8Instance(config *RedisConfiguration) (*GoRedisV8, error) {
+	const op errorx.Op = "cache.NewGoRedisV8"
+
+	if len(config.Addresses) == 0 {
+		return nil, errorx.E("missing address", op, errorx.CodeConfig)
+	}
+
+	if config.MasterName != "" {
+		rdb := redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:            config.MasterName,
+			SentinelAddrs:         config.Addresses,
+			SentinelPassword:      config.SentinelPassword,
+			RouteByLatency:        false,
+			RouteRandomly:         false,
+			SlaveOnly:             false,
+			UseDisconnectedSlaves: false,
+			QuerySentinelRandomly: false,
+			Dialer:                nil,
+			OnConnect:             nil,
+			Username:              config.Username,
+			Password:              config.Password,
+			DB:                    int(config.DB),
+			MaxRetries:            int(config.MaxRetries),
+			MinRetryBackoff:       0,
+			MaxRetryBackoff:       0,
+			DialTimeout:           time.Duration(config.DialTimeout) * time.Second,
+			ReadTimeout:           time.Duration(config.ReadTimeout) * time.Second,
+			WriteTimeout:          time.Duration(config.WriteTimeout) * time.Second,
+			PoolSize:              int(config.OpenConnectionLimit),
+			MinIdleConns:          int(config.MinIdleConns),
+			MaxConnAge:            time.Duration(config.MaxConnAge) * time.Second,
+			PoolTimeout:           0,
+			IdleTimeout:           time.Duration(config.IdleTimeout) * time.Second,
+			IdleCheckFrequency:    0,
+			TLSConfig:             config.TLSConfig,
+		})
+
+		return &GoRedisV8{
+			client:  rdb,
+			backoff: newSubscribeBackoff(config),
+		}, nil
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Network:            "",
+		Addr:               config.Addresses[0],
+		Dialer:             nil,
+		OnConnect:          nil,
+		Username:           config.Username,
+		Password:           config.Password,
+		DB:                 int(config.DB),
+		MaxRetries:         int(config.MaxRetries),
+		MinRetryBackoff:    0,
+		MaxRetryBackoff:    0,
+		DialTimeout:        time.Duration(config.DialTimeout) * time.Second,
+		ReadTimeout:        time.Duration(config.ReadTimeout) * time.Second,
+		WriteTimeout:       time.Duration(config.WriteTimeout) * time.Second,
+		PoolSize:           int(config.OpenConnectionLimit),
+		MinIdleConns:       int(config.MinIdleConns),
+		MaxConnAge:         time.Duration(config.MaxConnAge) * time.Second,
+		PoolTimeout:        0,
+		IdleTimeout:        time.Duration(config.IdleTimeout) * time.Second,
+		IdleCheckFrequency: 0,
+		TLSConfig:          config.TLSConfig,
+		Limiter:            nil,
+	})
+
+	return &GoRedisV8{
+		client:  rdb,
+		backoff: newSubscribeBackoff(config),
+	}, nil
+}
+
+// NewGoRedisClusterV8 returns the redis cluster client shared by the package.
+// The client is created on the first call, the later calls return it whatever their configuration.
+// Use NewGoRedisClusterV8Instance or a Registry to hold several clients.
+func NewGoRedisClusterV8(config *RedisConfiguration) (*GoRedisClusterV8, error) {
+	onceNewGoRedisClusterV8.Do(func() {
+		onceNewGoRedisClusterV8Res, onceNewGoRedisClusterV8Err = NewGoRedisClusterV8Instance(config)
+	})
+
+	return onceNewGoRedisClusterV8Res, onceNewGoRedisClusterV8Err
+}
+
+// NewGoRedisClusterV8Instance returns a new redis cluster client.
+func NewGoRedisClusterV8Instance(config *RedisConfiguration) (*GoRedisClusterV8, error) {
+	const op errorx.Op = "cache.NewGoRedisClusterV8"
+
+	if config.MasterName != "" {
+		return nil, errorx.E("sentinel isn't supported by the cluster client", op, errorx.CodeConfig)
+	}
+	if config.DB != 0 {
+		return nil, errorx.E("cluster only supports the database 0", op, errorx.CodeConfig)
+	}
+
+	// Create connection to the cluster.
+	// The unfilled configuration means, it will use the default configuration.
+	// Tweaking configuration may increase or decrease the performance.
+	rdb := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:              config.Addresses,
+		NewClient:          nil,
+		MaxRedirects:       0,
+		ReadOnly:           config.ReadOnly,
+		RouteByLatency:     config.RouteByLatency,
+		RouteRandomly:      config.RouteRandomly,
+		ClusterSlots:       nil,
+		Dialer:             nil,
+		OnConnect:          nil,
+		Username:           config.Username,
+		Password:           config.Password,
+		MaxRetries:         int(config.MaxRetries),
+		MinRetryBackoff:    0,
+		MaxRetryBackoff:    0,
+		DialTimeout:        time.Duration(config.DialTimeout) * time.Second,
+		ReadTimeout:        time.Duration(config.ReadTimeout) * time.Second,
+		WriteTimeout:       time.Duration(config.WriteTimeout) * time.Second,
+		PoolSize:           int(config.OpenConnectionLimit),
+		MinIdleConns:       int(config.MinIdleConns),
+		MaxConnAge:         time.Duration(config.MaxConnAge) * time.Second,
+		PoolTimeout:        0,
+		IdleTimeout:        time.Duration(config.IdleTimeout) * time.Second,
+		IdleCheckFrequency: 0,
+		TLSConfig:          config.TLSConfig,
+	})
+
+	return &GoRedisClusterV8{
+		client:  rdb,
+		backoff: newSubscribeBackoff(config),
+	}, nil
+}
+
+// Get gets the value from redis in []byte form.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) Get(ctx context.Context, key string) ([]byte, error) {
+	const op errorx.Op = "cache/GoRedisV8.Get"
+
+	res, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return []byte(res), nil
+}
+
+// SimpleSet sets value to key in redis without any additional options.
+// Key doesn't have a TTL.
+func (r *GoRedisV8) SimpleSet(ctx context.Context, key, value string) error {
+	const op errorx.Op = "cache/GoRedisV8.SimpleSet"
+
+	if _, err := r.client.Set(ctx, key, value, 0).Result(); err != nil {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: key}, string(op)+" success")
+	return nil
+}
+
+// SetEX sets the value to a key with timeout in seconds.
+func (r *GoRedisV8) SetEX(
+	ctx context.Context,
+	key string,
+	seconds int64,
+	value string,
+) error {
+	const op errorx.Op = "cache/GoRedisV8.SetEX"
+
+	_, err := r.client.SetEX(ctx, key, value, time.Duration(seconds)*time.Second).Result()
+	if err != nil {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: key}, string(op)+" success")
+	return nil
+}
+
+// SetNX sets a value to a key with specified timeouts.
+// SetNX returns false if the key exists.
+func (r *GoRedisV8) SetNX(
+	ctx context.Context,
+	key string,
+	seconds int64,
+	value string,
+) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.SetNX"
+
+	res, err := r.client.SetNX(ctx, key, value, time.Duration(seconds)*time.Second).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// Exists checks whether the key exists in redis.
+func (r *GoRedisV8) Exists(ctx context.Context, key string) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.Exists"
+
+	res, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res > 0, nil
+}
+
+// Expire sets the ttl of a key to specified value in seconds.
+func (r *GoRedisV8) Expire(
+	ctx context.Context,
+	key string,
+	seconds int64,
+) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.Expire"
+
+	res, err := r.client.Expire(ctx, key, time.Duration(seconds)*time.Second).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ExpireAt sets the ttl of a key to a certain timestamp.
+func (r *GoRedisV8) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.ExpireAt"
+
+	res, err := r.client.ExpireAt(ctx, key, time.Unix(timestamp, 0)).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// Incr increments the integer value of a key by 1.
+func (r *GoRedisV8) Incr(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.Incr"
+
+	res, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// Decr decrements the integer value of a key by 1.
+func (r *GoRedisV8) Decr(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.Decr"
+
+	res, err := r.client.Decr(ctx, key).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// IncrBy increments the integer value of a key by the given amount.
+func (r *GoRedisV8) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.IncrBy"
+
+	res, err := r.client.IncrBy(ctx, key, by).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// IncrByEx increments redis key by adding expired.
+func (r *GoRedisV8) IncrByEx(
+	ctx context.Context,
+	key string,
+	by int64,
+	expires int64,
+) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.IncrByEx"
+
+	reply, err := r.RunScript(ctx, ScriptIncrByEx, []string{key}, by, expires)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	res, err := replyInt64(reply)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+// It returns -2 if the key does not exist and -1 if the key has no expiry.
+func (r *GoRedisV8) TTL(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.TTL"
+
+	res, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+
+	// The negative replies are not converted into a duration.
+	if res < 0 {
+		return int64(res), nil
+	}
+	return int64(res / time.Second), nil
+}
+
+// HGet gets the value of a hash field.
+// Missing key or field is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	const op errorx.Op = "cache/GoRedisV8.HGet"
+
+	res, err := r.client.HGet(ctx, key, field).Result()
+	if err == redis.Nil {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return []byte(res), nil
+}
+
+// HMGet gets a value of multiple fields from hash key.
+// Missing field is returned as a nil value.
+func (r *GoRedisV8) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
+	const op errorx.Op = "cache/GoRedisV8.HMGet"
+
+	res, err := r.client.HMGet(ctx, key, fields...).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	data := make([][]byte, len(res))
+	for i, v := range res {
+		if str, ok := v.(string); ok {
+			data[i] = []byte(str)
+		}
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return data, nil
+}
+
+// HGetAll gets all the fields and values in a hash.
+func (r *GoRedisV8) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	const op errorx.Op = "cache/GoRedisV8.HGetAll"
+
+	res, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// HKeys gets all the fields in a hash.
+func (r *GoRedisV8) HKeys(ctx context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/GoRedisV8.HKeys"
+
+	res, err := r.client.HKeys(ctx, key).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// HExists determines if a hash field exists.
+func (r *GoRedisV8) HExists(ctx context.Context, key, field string) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.HExists"
+
+	res, err := r.client.HExists(ctx, key, field).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// HSet sets the string value of a hash field.
+func (r *GoRedisV8) HSet(ctx context.Context, key, field, value string) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.HSet"
+
+	res, err := r.client.HSet(ctx, key, field, value).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res > 0, nil
+}
+
+// HDel deletes a hash field.
+func (r *GoRedisV8) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.HDel"
+
+	res, err := r.client.HDel(ctx, key, fields...).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// Del deletes the keys.
+// Keys are grouped by the hash slot, since DEL can't span multiple slots in cluster mode.
+func (r *GoRedisV8) Del(ctx context.Context, key ...interface{}) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.Del"
+
+	slots := make(map[int][]interface{})
+	for _, v := range key {
+		stdKey, _ := v.(string)
+		slots[Slot(stdKey)] = append(slots[Slot(stdKey)], stdKey)
+	}
+
+	p := r.Pipeline()
+	for _, keys := range slots {
+		p.Do("DEL", keys...)
+	}
+
+	cmds, err := p.Exec(ctx)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	var res int64
+	for _, cmd := range cmds {
+		data, err := cmd.Int64()
+		if err != nil {
+			return res, errorx.E(err, op)
+		}
+		res += data
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (r *GoRedisV8) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.LPush"
+
+	res, err := r.client.LPush(ctx, key, toInterfaces(values)...).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (r *GoRedisV8) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.RPush"
+
+	res, err := r.client.RPush(ctx, key, toInterfaces(values)...).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// LRange gets array range that we set using LPush between index Start and Stop.
+func (r *GoRedisV8) LRange(
+	ctx context.Context,
+	key string,
+	start, stop int64,
+) ([][]byte, error) {
+	const op errorx.Op = "cache/GoRedisV8.LRange"
+
+	res, err := r.client.LRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	data := make([][]byte, len(res))
+	for i, v := range res {
+		data[i] = []byte(v)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return data, nil
+}
+
+// LTrim trims array value that we set using LPush between index start and stop.
+func (r *GoRedisV8) LTrim(ctx context.Context, key string, start, stop int64) error {
+	const op errorx.Op = "cache/GoRedisV8.LTrim"
+
+	if _, err := r.client.LTrim(ctx, key, start, stop).Result(); err != nil {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: key}, string(op)+" success")
+	return nil
+}
+
+// LLen gets the length of a list.
+func (r *GoRedisV8) LLen(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.LLen"
+
+	res, err := r.client.LLen(ctx, key).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// SAdd add the specified members to the set stored at key.
+// It returns false if key and value combination exists.
+func (r *GoRedisV8) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.SAdd"
+
+	res, err := r.client.SAdd(ctx, key, toInterfaces(value)...).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res > 0, nil
+}
+
+// SRem removes the members from a set and returns the number of removed members.
+func (r *GoRedisV8) SRem(ctx context.Context, key string, value ...string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.SRem"
+
+	res, err := r.client.SRem(ctx, key, toInterfaces(value)...).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// SMembers gets all the members of a set.
+func (r *GoRedisV8) SMembers(ctx context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/GoRedisV8.SMembers"
+
+	res, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// SIsMember determines if a value is a member of a set.
+func (r *GoRedisV8) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	const op errorx.Op = "cache/GoRedisV8.SIsMember"
+
+	res, err := r.client.SIsMember(ctx, key, value).Result()
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// SCard gets the number of members of a set.
+func (r *GoRedisV8) SCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.SCard"
+
+	res, err := r.client.SCard(ctx, key).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (r *GoRedisV8) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZAdd"
+
+	args := make([]*redis.Z, len(members))
+	for i, v := range members {
+		args[i] = &redis.Z{Score: v.Score, Member: v.Member}
+	}
+
+	res, err := r.client.ZAdd(ctx, key, args...).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (r *GoRedisV8) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZIncrBy"
+
+	res, err := r.client.ZIncrBy(ctx, key, increment, member).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (r *GoRedisV8) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZRange"
+
+	res, err := r.client.ZRangeWithScores(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	data := fromRedisZ(res)
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return data, nil
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (r *GoRedisV8) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZRevRange"
+
+	res, err := r.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	data := fromRedisZ(res)
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return data, nil
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (r *GoRedisV8) ZRangeByScore(
+	ctx context.Context,
+	key, min, max string,
+	offset, count int64,
+) ([]ZMember, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZRangeByScore"
+
+	// go-redis only sends the limit when the offset or the count isn't zero.
+	count, _ = zrangeLimit(offset, count)
+	res, err := r.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min:    min,
+		Max:    max,
+		Offset: offset,
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	data := fromRedisZ(res)
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return data, nil
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) ZRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZRank"
+
+	res, err := r.client.ZRank(ctx, key, member).Result()
+	if err == redis.Nil {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZRevRank"
+
+	res, err := r.client.ZRevRank(ctx, key, member).Result()
+	if err == redis.Nil {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZScore gets the score of a sorted set member.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) ZScore(ctx context.Context, key, member string) (float64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZScore"
+
+	res, err := r.client.ZScore(ctx, key, member).Result()
+	if err == redis.Nil {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZCard gets the number of members of a sorted set.
+func (r *GoRedisV8) ZCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZCard"
+
+	res, err := r.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (r *GoRedisV8) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZRem"
+
+	res, err := r.client.ZRem(ctx, key, toInterfaces(members)...).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (r *GoRedisV8) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.ZRemRangeByScore"
+
+	res, err := r.client.ZRemRangeByScore(ctx, key, min, max).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    key,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// MGet gets the values of the keys.
+// Keys are grouped by the hash slot, and every group is fetched with a single MGET command.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) MGet(ctx context.Context, keys ...string) ([]BatchResult, error) {
+	const op errorx.Op = "cache/GoRedisV8.MGet"
+
+	res, err := batchMGet(ctx, r.Pipeline(), keys, Slot)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// MSetEX sets the values of the keys with their timeout in seconds in a single round trip per node.
+func (r *GoRedisV8) MSetEX(ctx context.Context, items ...BatchItem) ([]BatchResult, error) {
+	const op errorx.Op = "cache/GoRedisV8.MSetEX"
+
+	res, err := batchSetEX(ctx, r.Pipeline(), items)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// MDel deletes the keys in a single round trip per node.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) MDel(ctx context.Context, keys ...string) ([]BatchResult, error) {
+	const op errorx.Op = "cache/GoRedisV8.MDel"
+
+	res, err := batchDel(ctx, r.Pipeline(), keys)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// Pipeline returns a pipeline to queue arbitrary commands.
+// In cluster mode, the commands are routed to the node owning the slot of their first key,
+// and sent in a single round trip per node.
+// Multi-key commands must only use keys of the same slot.
+func (r *GoRedisV8) Pipeline() *Pipeline {
+	return newPipeline(r.execPipeline)
+}
+
+// execPipeline sends the commands through the client pipeline and fills their replies.
+func (r *GoRedisV8) execPipeline(ctx context.Context, cmds []*PipelineCmd) error {
+	const op errorx.Op = "cache/GoRedisV8.execPipeline"
+
+	pipe := r.client.Pipeline()
+	res := make([]*redis.Cmd, len(cmds))
+	for i, cmd := range cmds {
+		res[i] = pipe.Do(ctx, cmd.args...)
+	}
+
+	// Every command reports its own error.
+	_, _ = pipe.Exec(ctx)
+
+	for i, cmd := range cmds {
+		cmd.val, cmd.err = res[i].Result()
+		if cmd.err == redis.Nil {
+			cmd.val, cmd.err = nil, nil
+		}
+		if cmd.err != nil {
+			cmd.err = errorx.E(cmd.err, op, errorx.CodeGateway)
+		}
+	}
+
+	return nil
+}
+
+// RunScript runs the script with EVALSHA, and loads the script when redis replies NOSCRIPT.
+// All the keys must share the same hash slot, see HashTag.
+func (r *GoRedisV8) RunScript(
+	ctx context.Context,
+	script *Script,
+	keys []string,
+	args ...interface{},
+) (interface{}, error) {
+	const op errorx.Op = "cache/GoRedisV8.RunScript"
+
+	if err := script.validate(keys); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res, err := r.client.EvalSha(ctx, script.Hash(), keys, args...).Result()
+	if isNoScript(err) {
+		if err := r.LoadScripts(ctx, script); err != nil {
+			return nil, errorx.E(err, op)
+		}
+		res, err = r.client.EvalSha(ctx, script.Hash(), keys, args...).Result()
+	}
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    keys,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// LoadScripts loads the scripts into the script cache, of every master node in cluster mode, ahead of their first run.
+func (r *GoRedisV8) LoadScripts(ctx context.Context, scripts ...*Script) error {
+	const op errorx.Op = "cache/GoRedisV8.LoadScripts"
+
+	err := r.forEachMaster(ctx, func(ctx context.Context, client redis.Cmdable) error {
+		for _, v := range scripts {
+			if err := client.ScriptLoad(ctx, v.Source()).Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// XAdd appends a message to a stream and returns the generated id.
+// The stream is trimmed to approximately maxLen messages, zero value means the stream isn't trimmed.
+func (r *GoRedisV8) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]string) (string, error) {
+	const op errorx.Op = "cache/GoRedisV8.XAdd"
+
+	res, err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream:       stream,
+		MaxLenApprox: maxLen,
+		Values:       streamValues(values),
+	}).Result()
+	if err != nil {
+		return "", errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Key:    stream,
+		tags.Detail: res,
+	}, string(op)+" success")
+	return res, nil
+}
+
+// XGroupCreate creates a consumer group reading the stream from the start id, e.g. "0" or "$".
+// The stream is created if it doesn't exist, and the existing group is left as is.
+func (r *GoRedisV8) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	const op errorx.Op = "cache/GoRedisV8.XGroupCreate"
+
+	err := r.client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && !isBusyGroup(err) {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// XReadGroup reads up to count new messages for a consumer of a group,
+// waiting up to block for the messages to arrive, zero value doesn't wait.
+// The messages are pending until they are acknowledged.
+func (r *GoRedisV8) XReadGroup(
+	ctx context.Context,
+	stream, group, consumer string,
+	count int64,
+	block time.Duration,
+) ([]StreamMessage, error) {
+	const op errorx.Op = "cache/GoRedisV8.XReadGroup"
+
+	// Negative block omits the BLOCK option, while zero blocks forever.
+	if block <= 0 {
+		block = -1
+	}
+
+	streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return []StreamMessage{}, nil
+	}
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res := []StreamMessage{}
+	for _, v := range streams {
+		res = append(res, fromRedisXMessages(v.Messages)...)
+	}
+
+	return res, nil
+}
+
+// XAck acknowledges the messages and returns the number of acknowledged messages.
+func (r *GoRedisV8) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	const op errorx.Op = "cache/GoRedisV8.XAck"
+
+	res, err := r.client.XAck(ctx, stream, group, ids...).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// XPending returns up to count pending messages of a group, the oldest first.
+func (r *GoRedisV8) XPending(ctx context.Context, stream, group string, count int64) ([]PendingMessage, error) {
+	const op errorx.Op = "cache/GoRedisV8.XPending"
+
+	pending, err := r.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res := make([]PendingMessage, len(pending))
+	for i, v := range pending {
+		res[i] = PendingMessage{
+			ID:         v.ID,
+			Consumer:   v.Consumer,
+			Idle:       v.Idle,
+			Deliveries: v.RetryCount,
+		}
+	}
+
+	return res, nil
+}
+
+// XClaim transfers the pending messages idle for at least minIdle to a consumer,
+// and returns the claimed messages.
+// The message deleted from the stream is returned with nil values.
+func (r *GoRedisV8) XClaim(
+	ctx context.Context,
+	stream, group, consumer string,
+	minIdle time.Duration,
+	ids ...string,
+) ([]StreamMessage, error) {
+	const op errorx.Op = "cache/GoRedisV8.XClaim"
+
+	messages, err := r.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return fromRedisXMessages(messages), nil
+}
+
+// Dump returns the serialized value of a key with its remaining ttl,
+// zero ttl means the key has no expiry.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *GoRedisV8) Dump(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	const op errorx.Op = "cache/GoRedisV8.Dump"
+
+	// Both commands are sent to the node of the key.
+	pipe := r.client.Pipeline()
+	dump := pipe.Dump(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	_, _ = pipe.Exec(ctx)
+
+	value, err := dump.Result()
+	if err == redis.Nil {
+		return nil, 0, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return nil, 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	// The negative replies of PTTL mean the key has no expiry or has just expired.
+	d, err := ttl.Result()
+	if err != nil {
+		return nil, 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	return []byte(value), d, nil
+}
+
+// Restore creates a key from its serialized value with the ttl, zero ttl means no expiry.
+// Existing key is reported as an error with errorx.CodeConflict, unless it is replaced.
+func (r *GoRedisV8) Restore(ctx context.Context, key string, ttl time.Duration, value []byte, replace bool) error {
+	const op errorx.Op = "cache/GoRedisV8.Restore"
+
+	var err error
+	if replace {
+		err = r.client.RestoreReplace(ctx, key, ttl, string(value)).Err()
+	} else {
+		err = r.client.Restore(ctx, key, ttl, string(value)).Err()
+	}
+	if err != nil {
+		if isBusyKey(err) {
+			return errorx.E(err, op, errorx.CodeConflict)
+		}
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// ScanKeys iterates the keys matching the pattern with SCAN, count keys at a time,
+// and calls fn with every batch of keys until fn returns an error.
+// Every master is scanned concurrently in cluster mode, while fn is never called concurrently.
+func (r *GoRedisV8) ScanKeys(ctx context.Context, match string, count int64, fn func(keys []string) error) error {
+	const op errorx.Op = "cache/GoRedisV8.ScanKeys"
+
+	var (
+		mu     sync.Mutex
+		failed error
+	)
+	err := r.forEachMaster(ctx, func(ctx context.Context, client redis.Cmdable) error {
+		cursor := uint64(0)
+		for {
+			keys, next, err := client.Scan(ctx, cursor, match, count).Result()
+			if err != nil {
+				return errorx.E(err, errorx.CodeGateway)
+			}
+
+			if len(keys) > 0 {
+				mu.Lock()
+				if failed == nil {
+					failed = fn(keys)
+				}
+				err = failed
+				mu.Unlock()
+				if err != nil {
+					return err
+				}
+			}
+
+			cursor = next
+			if cursor == 0 {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// HScan iterates the fields of a hash matching the pattern with HSCAN, count fields at a time,
+// and calls fn with every batch of fields and their values until fn returns an error.
+func (r *GoRedisV8) HScan(ctx context.Context, key, match string, count int64, fn func(fields map[string]string) error) error {
+	const op errorx.Op = "cache/GoRedisV8.HScan"
+
+	cursor := uint64(0)
+	for {
+		values, next, err := r.client.HScan(ctx, key, cursor, match, count).Result()
+		if err != nil {
+			return errorx.E(err, op, errorx.CodeGateway)
+		}
+
+		if len(values) > 0 {
+			fields := make(map[string]string, len(values)/2)
+			for i := 0; i+1 < len(values); i += 2 {
+				fields[values[i]] = values[i+1]
+			}
+
+			if err := fn(fields); err != nil {
+				return errorx.E(err, op)
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// SScan iterates the members of a set matching the pattern with SSCAN, count members at a time,
+// and calls fn with every batch of members until fn returns an error.
+func (r *GoRedisV8) SScan(ctx context.Context, key, match string, count int64, fn func(members []string) error) error {
+	const op errorx.Op = "cache/GoRedisV8.SScan"
+
+	cursor := uint64(0)
+	for {
+		members, next, err := r.client.SScan(ctx, key, cursor, match, count).Result()
+		if err != nil {
+			return errorx.E(err, op, errorx.CodeGateway)
+		}
+
+		if len(members) > 0 {
+			if err := fn(members); err != nil {
+				return errorx.E(err, op)
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+func (r *GoRedisV8) Publish(ctx context.Context, topic, message string) (int, error) {
+	const op errorx.Op = "cache/GoRedisV8.Publish"
+
+	res, err := r.client.Publish(ctx, topic, message).Result()
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	logx.DBG(ctx, logx.KV{
+		tags.Channel: topic,
+		tags.Detail:  res,
+	}, string(op)+" success")
+	return int(res), nil
+}
+
+// Subscribe subscribes to the channels and dispatches the received messages to the handler.
+// It blocks until the context is done, the broken subscription is reconnected with backoff.
+// The subscription holds one connection until it ends, to a single node in cluster mode,
+// since the messages published to any node are broadcast to the whole cluster.
+func (r *GoRedisV8) Subscribe(ctx context.Context, handler MessageHandler, channels ...string) error {
+	const op errorx.Op = "cache/GoRedisV8.Subscribe"
+
+	if len(channels) == 0 {
+		return errorx.E("missing channels", op, errorx.CodeInvalid)
+	}
+
+	sub := &subscription{
+		op:      op,
+		names:   channels,
+		handler: handler,
+		backoff: r.backoff,
+		session: r.subscribeSession(func(ctx context.Context) *redis.PubSub {
+			return r.client.Subscribe(ctx, channels...)
+		}),
+	}
+
+	return sub.run(ctx)
+}
+
+// PSubscribe subscribes to the channels matching the patterns,
+// and dispatches the received messages to the handler.
+// It blocks until the context is done, the broken subscription is reconnected with backoff.
+// The subscription holds one connection until it ends, to a single node in cluster mode,
+// since the messages published to any node are broadcast to the whole cluster.
+func (r *GoRedisV8) PSubscribe(ctx context.Context, handler MessageHandler, patterns ...string) error {
+	const op errorx.Op = "cache/GoRedisV8.PSubscribe"
+
+	if len(patterns) == 0 {
+		return errorx.E("missing patterns", op, errorx.CodeInvalid)
+	}
+
+	sub := &subscription{
+		op:      op,
+		names:   patterns,
+		handler: handler,
+		backoff: r.backoff,
+		session: r.subscribeSession(func(ctx context.Context) *redis.PubSub {
+			return r.client.PSubscribe(ctx, patterns...)
+		}),
+	}
+
+	return sub.run(ctx)
+}
+
+// subscribeSession returns a session using a new pubsub connection.
+// The go-redis reconnection of the pubsub is not used,
+// so every broken connection is logged and backed off.
+func (r *GoRedisV8) subscribeSession(subscribe func(ctx context.Context) *redis.PubSub) subscribeSession {
+	return func(ctx context.Context, ready func(), dispatch func(ctx context.Context, msg *Message)) error {
+		pubsub := subscribe(ctx)
+		defer func() {
+			_ = pubsub.Close()
+		}()
+
+		// Closing the pubsub unblocks the receiver when the context is done.
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-ctx.Done():
+				_ = pubsub.Close()
+			case <-stop:
+			}
+		}()
+
+		// Wait for the subscription to be confirmed.
+		if _, err := pubsub.Receive(ctx); err != nil {
+			return errorx.E(err, errorx.CodeGateway)
+		}
+		ready()
+
+		for {
+			msg, err := pubsub.ReceiveMessage(ctx)
+			if err != nil {
+				return errorx.E(err, errorx.CodeGateway)
+			}
+
+			dispatch(ctx, &Message{Channel: msg.Channel, Pattern: msg.Pattern, Data: []byte(msg.Payload)})
+		}
+	}
+}
+
+// isCluster reports whether the client is connected to a cluster.
+func (r *GoRedisV8) isCluster() bool {
+	_, ok := r.client.(*redis.ClusterClient)
+	return ok
+}
+
+// forEachMaster calls fn with every master node concurrently in cluster mode,
+// or with the client itself otherwise.
+func (r *GoRedisV8) forEachMaster(ctx context.Context, fn func(ctx context.Context, client redis.Cmdable) error) error {
+	cluster, ok := r.client.(*redis.ClusterClient)
+	if !ok {
+		return fn(ctx, r.client)
+	}
+
+	return cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		return fn(ctx, client)
+	})
+}
+
+// PoolStats returns a snapshot of the stats of the connection pool, of all the nodes in cluster mode.
+func (r *GoRedisV8) PoolStats() PoolStats {
+	stats := r.client.PoolStats()
+
+	return PoolStats{
+		OpenConnections: int(stats.TotalConns),
+		InUse:           int(stats.TotalConns) - int(stats.IdleConns),
+		Idle:            int(stats.IdleConns),
+		Timeouts:        int64(stats.Timeouts),
+	}
+}
+
+// Close closes the client, releasing any open resources.
+func (r *GoRedisV8) Close() {
+	_ = r.client.Close()
+}
+
+// toInterfaces converts the strings into the variadic arguments of redis commands.
+func toInterfaces(values []string) []interface{} {
+	res := make([]interface{}, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+
+	return res
+}
+
+// fromRedisZ converts the go-redis sorted set members.
+func fromRedisZ(values []redis.Z) []ZMember {
+	res := make([]ZMember, len(values))
+	for i, v := range values {
+		member, _ := v.Member.(string)
+		res[i] = ZMember{
+			Member: member,
+			Score:  v.Score,
+		}
+	}
+
+	return res
+}
+
+// fromRedisXMessages converts the go-redis stream messages.
+func fromRedisXMessages(messages []redis.XMessage) []StreamMessage {
+	res := make([]StreamMessage, len(messages))
+	for i, v := range messages {
+		res[i].ID = v.ID
+		if v.Values == nil {
+			continue
+		}
+
+		res[i].Values = make(map[string]string, len(v.Values))
+		for field, value := range v.Values {
+			res[i].Values[field] = fmt.Sprint(value)
+		}
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGoRedisV8Instance(t *testing.T) {
+	_, err := NewGoRedisV8Instance(&RedisConfiguration{})
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+
+	// The clients connect on the first command.
+	got, err := NewGoRedisV8Instance(&RedisConfiguration{Addresses: []string{"localhost:6379"}, DB: 2})
+	assert.NoError(t, err)
+	assert.IsType(t, &redis.Client{}, got.client)
+	assert.Equal(t, 2, got.client.(*redis.Client).Options().DB)
+	got.Close()
+
+	got, err = NewGoRedisV8Instance(&RedisConfiguration{Addresses: []string{"localhost:26379"}, MasterName: "master"})
+	assert.NoError(t, err)
+	assert.IsType(t, &redis.Client{}, got.client)
+	got.Close()
+}
+
+func TestNewGoRedisClusterV8Instance(t *testing.T) {
+	_, err := NewGoRedisClusterV8Instance(&RedisConfiguration{Addresses: []string{"localhost:26379"}, MasterName: "master"})
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+
+	_, err = NewGoRedisClusterV8Instance(&RedisConfiguration{Addresses: []string{"localhost:6379"}, DB: 1})
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+
+	got, err := NewGoRedisClusterV8Instance(&RedisConfiguration{
+		Addresses: []string{"localhost:7000"},
+		Username:  "user",
+		Password:  "secret",
+	})
+	assert.NoError(t, err)
+	assert.IsType(t, &redis.ClusterClient{}, got.client)
+	got.Close()
+}
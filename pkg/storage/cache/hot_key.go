@@ -0,0 +1,253 @@
+package cache
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// HotKeyConfig configuration.
+type HotKeyConfig struct {
+	// SampleRate is the ratio of the reads counted by the detector, between 0 and 1.
+	// Lower value reduces the overhead, at the cost of the accuracy of the counts.
+	SampleRate float64
+	// Threshold is the number of reads of a key within a window making it hot.
+	Threshold int64
+	// Window is the period the reads are counted over.
+	// A hot key cools down once it stays below the threshold for a whole window.
+	Window time.Duration
+	// LocalTTL is the ttl of the hot keys in the local cache.
+	// It bounds the staleness of the values written by the other processes,
+	// the writes of the client itself invalidate the local cache.
+	LocalTTL time.Duration
+	// MaxHotKeys is the maximum number of hot keys, the keys detected beyond it are ignored.
+	MaxHotKeys int
+	// SketchWidth is the number of counters of every row of the count-min sketch counting the reads.
+	// Bigger value reduces the overestimation of the counts.
+	SketchWidth int
+	// SketchDepth is the number of rows of the count-min sketch.
+	// Bigger value reduces the probability to overestimate the counts.
+	SketchDepth int
+}
+
+// DefaultHotKeyConfig is the default configuration of the hot key detection.
+var DefaultHotKeyConfig = HotKeyConfig{
+	SampleRate:  0.1,
+	Threshold:   1000,
+	Window:      time.Second,
+	LocalTTL:    time.Second,
+	MaxHotKeys:  100,
+	SketchWidth: 4096,
+	SketchDepth: 4,
+}
+
+// HotKey is a key detected as hot.
+type HotKey struct {
+	Key string
+	// Count is the estimated number of reads of the key over the last window.
+	Count int64
+	// Since is the time the key was detected as hot.
+	Since time.Time
+}
+
+// hotKey is the state of a hot key.
+type hotKey struct {
+	since time.Time
+	// count is the sampled count of the current window, and last the one of the former window.
+	count, last uint32
+	// fields are the fields of the hash read from the local cache.
+	fields map[string]struct{}
+}
+
+// hotKeys detects the hot keys by counting a sample of the reads with a count-min sketch,
+// the counts are reset every window.
+type hotKeys struct {
+	config HotKeyConfig
+	// threshold is the sampled count making a key hot.
+	threshold uint32
+	now       func() time.Time
+	sample    func() bool
+
+	// hot is a snapshot of the keys, so the reads neither sampled nor hot skip the lock.
+	// It is replaced under the lock whenever the keys change.
+	hot atomic.Value
+
+	mu     sync.Mutex
+	sketch *countMinSketch
+	expiry time.Time
+	keys   map[string]*hotKey
+}
+
+// newHotKeys returns a hot key detector.
+func newHotKeys(config HotKeyConfig) *hotKeys {
+	// Defaults
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = DefaultHotKeyConfig.SampleRate
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultHotKeyConfig.Threshold
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultHotKeyConfig.Window
+	}
+	if config.LocalTTL <= 0 {
+		config.LocalTTL = DefaultHotKeyConfig.LocalTTL
+	}
+	if config.MaxHotKeys <= 0 {
+		config.MaxHotKeys = DefaultHotKeyConfig.MaxHotKeys
+	}
+	if config.SketchWidth <= 0 {
+		config.SketchWidth = DefaultHotKeyConfig.SketchWidth
+	}
+	if config.SketchDepth <= 0 {
+		config.SketchDepth = DefaultHotKeyConfig.SketchDepth
+	}
+
+	rate := config.SampleRate
+	h := &hotKeys{
+		config:    config,
+		threshold: uint32(math.Ceil(float64(config.Threshold) * rate)),
+		now:       time.Now,
+		// nolint:gosec
+		sample: func() bool { return rate >= 1 || rand.Float64() < rate },
+		sketch: newCountMinSketch(config.SketchWidth, config.SketchDepth),
+		keys:   make(map[string]*hotKey),
+	}
+	h.hot.Store(map[string]struct{}{})
+
+	return h
+}
+
+// record counts a read of the key when it is sampled, and reports whether the key is hot.
+// The field is the field of the hash read from the local cache, empty for a string.
+func (h *hotKeys) record(ctx context.Context, op string, key, field string) bool {
+	sampled := h.sample()
+	if !sampled && !h.isHot(key) {
+		return false
+	}
+	now := h.now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.roll(now)
+
+	hot, ok := h.keys[key]
+	if sampled {
+		count := h.sketch.add(key)
+		if ok {
+			hot.count = count
+		} else if count >= h.threshold && len(h.keys) < h.config.MaxHotKeys {
+			hot, ok = &hotKey{since: now, count: count, fields: make(map[string]struct{})}, true
+			h.keys[key] = hot
+			h.snapshot()
+			logx.INF(ctx, logx.KV{tags.Key: key, tags.Count: count}, op+" hot key detected")
+		}
+	}
+
+	if ok && field != "" {
+		hot.fields[field] = struct{}{}
+	}
+
+	return ok
+}
+
+// roll resets the counts at the end of the window, and cools down the keys below the threshold.
+func (h *hotKeys) roll(now time.Time) {
+	if now.Before(h.expiry) {
+		return
+	}
+
+	cooled := false
+	for key, hot := range h.keys {
+		if hot.count < h.threshold {
+			delete(h.keys, key)
+			cooled = true
+			continue
+		}
+		hot.count, hot.last = 0, hot.count
+	}
+	if cooled {
+		h.snapshot()
+	}
+
+	h.sketch.reset()
+	h.expiry = now.Add(h.config.Window)
+}
+
+// isHot reports whether the key is in the snapshot of the hot keys.
+func (h *hotKeys) isHot(key string) bool {
+	_, ok := h.hot.Load().(map[string]struct{})[key]
+	return ok
+}
+
+// snapshot replaces the snapshot of the hot keys, the lock must be held.
+func (h *hotKeys) snapshot() {
+	hot := make(map[string]struct{}, len(h.keys))
+	for key := range h.keys {
+		hot[key] = struct{}{}
+	}
+	h.hot.Store(hot)
+}
+
+// localKeys returns the keys of the local cache holding the values of the key,
+// and stops tracking its hash fields.
+func (h *hotKeys) localKeys(key string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hot, ok := h.keys[key]
+	if !ok {
+		return nil
+	}
+
+	res := make([]string, 0, len(hot.fields)+1)
+	res = append(res, key)
+	for field := range hot.fields {
+		res = append(res, localFieldKey(key, field))
+	}
+	hot.fields = make(map[string]struct{})
+
+	return res
+}
+
+// report returns the hot keys, the hottest first.
+func (h *hotKeys) report() []HotKey {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := make([]HotKey, 0, len(h.keys))
+	for key, hot := range h.keys {
+		count := hot.count
+		if hot.last > count {
+			count = hot.last
+		}
+
+		res = append(res, HotKey{
+			Key:   key,
+			Count: int64(math.Round(float64(count) / h.config.SampleRate)),
+			Since: hot.since,
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Key < res[j].Key
+	})
+
+	return res
+}
+
+// localFieldKey returns the key of the local cache holding the field of a hash.
+func localFieldKey(key, field string) string {
+	return key + "\x00" + field
+}
@@ -0,0 +1,383 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// HotKeyRedis is a redis client detecting the hot keys, the keys read so often they overload their redis shard,
+// and serving them from a local cache for a short ttl.
+// The detection counts a sample of the Get and HGet commands with a count-min sketch, in a fixed memory,
+// and the keys read more than the threshold of the configuration within a window are hot.
+// The writes of the client invalidate the local cache, the values written by the other processes
+// are stale for up to the local ttl.
+//
+// Example:
+//
+//	local, _ := cache.NewRistrettoInstance(&cache.RistrettoConfiguration{NumCounters: 1e4, MaxCost: 1e3, BufferItems: 64})
+//	redis := cache.NewHotKeyRedis(client, local)
+//	_, _ = redis.Get(ctx, "product:1")
+//	hot := redis.HotKeys()
+type HotKeyRedis struct {
+	client RedisItf
+	local  RistrettoItf
+	keys   *hotKeys
+}
+
+// NewHotKeyRedis returns the client detecting the hot keys of the redis client, with the default configuration.
+// The local cache must be dedicated to the client, since it holds the values under the keys of redis.
+func NewHotKeyRedis(client RedisItf, local RistrettoItf) *HotKeyRedis {
+	return NewHotKeyRedisWithConfig(client, local, DefaultHotKeyConfig)
+}
+
+// NewHotKeyRedisWithConfig returns the client detecting the hot keys of the redis client.
+// The local cache must be dedicated to the client, since it holds the values under the keys of redis.
+func NewHotKeyRedisWithConfig(client RedisItf, local RistrettoItf, config HotKeyConfig) *HotKeyRedis {
+	return &HotKeyRedis{
+		client: client,
+		local:  local,
+		keys:   newHotKeys(config),
+	}
+}
+
+// HotKeys returns the current hot keys, the hottest first.
+func (r *HotKeyRedis) HotKeys() []HotKey {
+	return r.keys.report()
+}
+
+// Get gets the value from redis in []byte form.
+// Missing key is reported as an error with errorx.CodeNotFound.
+// The value of a hot key is served from the local cache.
+func (r *HotKeyRedis) Get(ctx context.Context, key string) ([]byte, error) {
+	const op errorx.Op = "cache/HotKeyRedis.Get"
+
+	hot := r.keys.record(ctx, string(op), key, "")
+	if hot {
+		if res, ok := r.lookup(ctx, key); ok {
+			return res, nil
+		}
+	}
+
+	res, err := r.client.Get(ctx, key)
+	if err == nil && hot {
+		r.store(ctx, key, res)
+	}
+
+	return res, err
+}
+
+// SimpleSet sets value to key in redis without any additional options.
+// Key doesn't have a TTL.
+func (r *HotKeyRedis) SimpleSet(ctx context.Context, key, value string) error {
+	err := r.client.SimpleSet(ctx, key, value)
+	r.invalidate(ctx, key)
+
+	return err
+}
+
+// SetEX sets the value to a key with timeout in seconds.
+func (r *HotKeyRedis) SetEX(ctx context.Context, key string, seconds int64, value string) error {
+	err := r.client.SetEX(ctx, key, seconds, value)
+	r.invalidate(ctx, key)
+
+	return err
+}
+
+// SetNX sets a value to a key with specified timeouts.
+// SetNX returns false if the key exists.
+func (r *HotKeyRedis) SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error) {
+	res, err := r.client.SetNX(ctx, key, seconds, value)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// Exists checks whether the key exists in redis.
+func (r *HotKeyRedis) Exists(ctx context.Context, key string) (bool, error) {
+	return r.client.Exists(ctx, key)
+}
+
+// Expire sets the TTL of a key to specified value in seconds.
+func (r *HotKeyRedis) Expire(ctx context.Context, key string, seconds int64) (bool, error) {
+	res, err := r.client.Expire(ctx, key, seconds)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// ExpireAt sets the TTL of a key to a certain unix timestamp in seconds.
+func (r *HotKeyRedis) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
+	res, err := r.client.ExpireAt(ctx, key, timestamp)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+// It returns -2 if the key does not exist and -1 if the key has no expiry.
+func (r *HotKeyRedis) TTL(ctx context.Context, key string) (int64, error) {
+	return r.client.TTL(ctx, key)
+}
+
+// Del deletes a key.
+func (r *HotKeyRedis) Del(ctx context.Context, key ...interface{}) (int64, error) {
+	res, err := r.client.Del(ctx, key...)
+	for _, v := range key {
+		switch k := v.(type) {
+		case string:
+			r.invalidate(ctx, k)
+		case []byte:
+			r.invalidate(ctx, string(k))
+		default:
+			r.invalidate(ctx, fmt.Sprint(k))
+		}
+	}
+
+	return res, err
+}
+
+// Incr increments the integer value of a key by 1.
+func (r *HotKeyRedis) Incr(ctx context.Context, key string) (int64, error) {
+	res, err := r.client.Incr(ctx, key)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// Decr decrements the integer value of a key by 1.
+func (r *HotKeyRedis) Decr(ctx context.Context, key string) (int64, error) {
+	res, err := r.client.Decr(ctx, key)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// IncrBy increments the integer value of a key by the given amount.
+func (r *HotKeyRedis) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	res, err := r.client.IncrBy(ctx, key, by)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// IncrByEx increments the integer value of a key by the given amount,
+// and sets the TTL of the key to specified value in seconds.
+func (r *HotKeyRedis) IncrByEx(ctx context.Context, key string, by int64, expires int64) (int64, error) {
+	res, err := r.client.IncrByEx(ctx, key, by, expires)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// HGet gets the value of a hash field.
+// Missing key or field is reported as an error with errorx.CodeNotFound.
+// The fields of a hot hash are served from the local cache.
+func (r *HotKeyRedis) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	const op errorx.Op = "cache/HotKeyRedis.HGet"
+
+	hot := r.keys.record(ctx, string(op), key, field)
+	if hot {
+		if res, ok := r.lookup(ctx, localFieldKey(key, field)); ok {
+			return res, nil
+		}
+	}
+
+	res, err := r.client.HGet(ctx, key, field)
+	if err == nil && hot {
+		r.store(ctx, localFieldKey(key, field), res)
+	}
+
+	return res, err
+}
+
+// HMGet gets the values of multiple hash fields in the order of the fields.
+// Missing field is returned as a nil value.
+func (r *HotKeyRedis) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
+	return r.client.HMGet(ctx, key, fields...)
+}
+
+// HGetAll gets all the fields and values in a hash.
+func (r *HotKeyRedis) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key)
+}
+
+// HKeys gets all the fields in a hash.
+func (r *HotKeyRedis) HKeys(ctx context.Context, key string) ([]string, error) {
+	return r.client.HKeys(ctx, key)
+}
+
+// HExists determines if a hash field exists.
+func (r *HotKeyRedis) HExists(ctx context.Context, key, field string) (bool, error) {
+	return r.client.HExists(ctx, key, field)
+}
+
+// HSet sets the string value of a hash field.
+func (r *HotKeyRedis) HSet(ctx context.Context, key, field, value string) (bool, error) {
+	res, err := r.client.HSet(ctx, key, field, value)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// HDel deletes hash fields and returns the number of deleted fields.
+func (r *HotKeyRedis) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	res, err := r.client.HDel(ctx, key, fields...)
+	r.invalidate(ctx, key)
+
+	return res, err
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (r *HotKeyRedis) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	return r.client.LPush(ctx, key, values...)
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (r *HotKeyRedis) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	return r.client.RPush(ctx, key, values...)
+}
+
+// LRange gets the elements of a list between index start and stop.
+func (r *HotKeyRedis) LRange(ctx context.Context, key string, start, stop int64) ([][]byte, error) {
+	return r.client.LRange(ctx, key, start, stop)
+}
+
+// LTrim trims a list to the elements between index start and stop.
+func (r *HotKeyRedis) LTrim(ctx context.Context, key string, start, stop int64) error {
+	return r.client.LTrim(ctx, key, start, stop)
+}
+
+// LLen gets the length of a list.
+func (r *HotKeyRedis) LLen(ctx context.Context, key string) (int64, error) {
+	return r.client.LLen(ctx, key)
+}
+
+// SAdd add the specified members to the set stored at key.
+// It returns false if key and value combination exists.
+func (r *HotKeyRedis) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
+	return r.client.SAdd(ctx, key, value...)
+}
+
+// SRem removes the members from a set and returns the number of removed members.
+func (r *HotKeyRedis) SRem(ctx context.Context, key string, value ...string) (int64, error) {
+	return r.client.SRem(ctx, key, value...)
+}
+
+// SMembers gets all the members of a set.
+func (r *HotKeyRedis) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key)
+}
+
+// SIsMember determines if a value is a member of a set.
+func (r *HotKeyRedis) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	return r.client.SIsMember(ctx, key, value)
+}
+
+// SCard gets the number of members of a set.
+func (r *HotKeyRedis) SCard(ctx context.Context, key string) (int64, error) {
+	return r.client.SCard(ctx, key)
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (r *HotKeyRedis) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	return r.client.ZAdd(ctx, key, members...)
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (r *HotKeyRedis) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	return r.client.ZIncrBy(ctx, key, increment, member)
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (r *HotKeyRedis) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	return r.client.ZRange(ctx, key, start, stop)
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (r *HotKeyRedis) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	return r.client.ZRevRange(ctx, key, start, stop)
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Min and max accept the redis score syntax, e.g. "-inf", "+inf" and "(1" for exclusive bound.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (r *HotKeyRedis) ZRangeByScore(ctx context.Context, key, min, max string, offset, count int64) ([]ZMember, error) {
+	return r.client.ZRangeByScore(ctx, key, min, max, offset, count)
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *HotKeyRedis) ZRank(ctx context.Context, key, member string) (int64, error) {
+	return r.client.ZRank(ctx, key, member)
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *HotKeyRedis) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	return r.client.ZRevRank(ctx, key, member)
+}
+
+// ZScore gets the score of a sorted set member.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *HotKeyRedis) ZScore(ctx context.Context, key, member string) (float64, error) {
+	return r.client.ZScore(ctx, key, member)
+}
+
+// ZCard gets the number of members of a sorted set.
+func (r *HotKeyRedis) ZCard(ctx context.Context, key string) (int64, error) {
+	return r.client.ZCard(ctx, key)
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (r *HotKeyRedis) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	return r.client.ZRem(ctx, key, members...)
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (r *HotKeyRedis) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	return r.client.ZRemRangeByScore(ctx, key, min, max)
+}
+
+// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+func (r *HotKeyRedis) Publish(ctx context.Context, topic, message string) (int, error) {
+	return r.client.Publish(ctx, topic, message)
+}
+
+// Close closes the client, releasing any open resources.
+// The local cache isn't closed, since it is owned by the caller.
+func (r *HotKeyRedis) Close() {
+	r.client.Close()
+}
+
+// lookup returns a copy of the value of the local cache, so the callers can't alter the cached value.
+func (r *HotKeyRedis) lookup(ctx context.Context, key string) ([]byte, bool) {
+	res, ok := r.local.Get(ctx, key)
+	if !ok {
+		return nil, false
+	}
+
+	value, ok := res.([]byte)
+	if !ok {
+		return nil, false
+	}
+
+	return append([]byte(nil), value...), true
+}
+
+// store stores a copy of the value in the local cache,
+// so the callers mutating their result don't corrupt the local cache.
+func (r *HotKeyRedis) store(ctx context.Context, key string, value []byte) {
+	r.local.SetEX(ctx, key, append([]byte(nil), value...), r.keys.config.LocalTTL)
+}
+
+// invalidate deletes the values of the key from the local cache after a write.
+func (r *HotKeyRedis) invalidate(ctx context.Context, key string) {
+	for _, v := range r.keys.localKeys(key) {
+		r.local.Del(ctx, v)
+	}
+}
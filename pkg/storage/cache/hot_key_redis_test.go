@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotKeyRedis(t *testing.T) {
+	local, err := NewRistrettoInstance(&RistrettoConfiguration{NumCounters: 1e3, MaxCost: 100, BufferItems: 64})
+	require.NoError(t, err)
+	defer local.Close()
+
+	redis := NewMemoryRedis()
+	client := NewHotKeyRedisWithConfig(redis, local, HotKeyConfig{SampleRate: 1, Threshold: 2})
+	ctx := context.Background()
+
+	require.NoError(t, redis.SimpleSet(ctx, "key", "value"))
+	_, err = redis.HSet(ctx, "hash", "field", "value")
+	require.NoError(t, err)
+
+	get := func(want string) {
+		t.Helper()
+
+		got, err := client.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(want), got)
+		local.Wait()
+	}
+	hget := func(want string) {
+		t.Helper()
+
+		got, err := client.HGet(ctx, "hash", "field")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(want), got)
+		local.Wait()
+	}
+
+	// The keys are cached locally once they are hot.
+	get("value")
+	_, cached := local.Get(ctx, "key")
+	assert.False(t, cached)
+
+	get("value")
+	hget("value")
+	hget("value")
+	_, cached = local.Get(ctx, "key")
+	assert.True(t, cached)
+	_, cached = local.Get(ctx, localFieldKey("hash", "field"))
+	assert.True(t, cached)
+
+	// The hot keys are served from the local cache, even when changed by other clients.
+	require.NoError(t, redis.SimpleSet(ctx, "key", "other"))
+	_, err = redis.HSet(ctx, "hash", "field", "other")
+	require.NoError(t, err)
+	get("value")
+	hget("value")
+
+	// The writes of the client invalidate the local cache.
+	require.NoError(t, client.SimpleSet(ctx, "key", "changed"))
+	_, err = client.HSet(ctx, "hash", "field", "changed")
+	require.NoError(t, err)
+	get("changed")
+	hget("changed")
+
+	_, err = client.Del(ctx, "key", []byte("hash"))
+	require.NoError(t, err)
+	_, err = client.Get(ctx, "key")
+	assert.Equal(t, errorx.CodeNotFound, errorx.GetCode(err))
+	_, err = client.HGet(ctx, "hash", "field")
+	assert.Equal(t, errorx.CodeNotFound, errorx.GetCode(err))
+
+	report := client.HotKeys()
+	if assert.Len(t, report, 2) {
+		assert.Equal(t, HotKey{Key: "hash", Count: 5, Since: report[0].Since}, report[0])
+		assert.Equal(t, HotKey{Key: "key", Count: 5, Since: report[1].Since}, report[1])
+	}
+}
+
+func TestHotKeyRedis_Copy(t *testing.T) {
+	local, err := NewRistrettoInstance(&RistrettoConfiguration{NumCounters: 1e3, MaxCost: 100, BufferItems: 64})
+	require.NoError(t, err)
+	defer local.Close()
+
+	redis := NewMemoryRedis()
+	client := NewHotKeyRedisWithConfig(redis, local, HotKeyConfig{SampleRate: 1, Threshold: 1})
+	ctx := context.Background()
+
+	require.NoError(t, redis.SimpleSet(ctx, "key", "value"))
+	got, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+	local.Wait()
+
+	// The callers can't alter the value stored in the local cache,
+	// nor the value served from it.
+	got[0] = 'V'
+	got, err = client.Get(ctx, "key")
+	require.NoError(t, err)
+	got[0] = 'V'
+
+	got, err = client.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotKeys(t *testing.T) {
+	type step struct {
+		// wait moves the clock before the reads.
+		wait    time.Duration
+		key     string
+		reads   int
+		wantHot bool
+	}
+
+	tests := []struct {
+		name   string
+		config HotKeyConfig
+		steps  []step
+		want   []string
+	}{
+		{
+			name:   "Below threshold",
+			config: HotKeyConfig{SampleRate: 1, Threshold: 3},
+			steps: []step{
+				{key: "key", reads: 2, wantHot: false},
+			},
+			want: []string{},
+		},
+		{
+			name:   "Reaching threshold",
+			config: HotKeyConfig{SampleRate: 1, Threshold: 3},
+			steps: []step{
+				{key: "key", reads: 2, wantHot: false},
+				{key: "key", reads: 1, wantHot: true},
+				{key: "other", reads: 1, wantHot: false},
+			},
+			want: []string{"key"},
+		},
+		{
+			name:   "Counts reset every window",
+			config: HotKeyConfig{SampleRate: 1, Threshold: 3, Window: time.Second},
+			steps: []step{
+				{key: "key", reads: 2, wantHot: false},
+				{wait: time.Second, key: "key", reads: 2, wantHot: false},
+			},
+			want: []string{},
+		},
+		{
+			name:   "Staying hot",
+			config: HotKeyConfig{SampleRate: 1, Threshold: 3, Window: time.Second},
+			steps: []step{
+				{key: "key", reads: 3, wantHot: true},
+				{wait: time.Second, key: "key", reads: 3, wantHot: true},
+				{wait: time.Second, key: "key", reads: 1, wantHot: true},
+			},
+			want: []string{"key"},
+		},
+		{
+			name:   "Cooling down",
+			config: HotKeyConfig{SampleRate: 1, Threshold: 3, Window: time.Second},
+			steps: []step{
+				{key: "key", reads: 3, wantHot: true},
+				{wait: time.Second, key: "key", reads: 2, wantHot: true},
+				{wait: time.Second, key: "key", reads: 1, wantHot: false},
+			},
+			want: []string{},
+		},
+		{
+			name:   "Max hot keys",
+			config: HotKeyConfig{SampleRate: 1, Threshold: 1, MaxHotKeys: 2},
+			steps: []step{
+				{key: "first", reads: 2, wantHot: true},
+				{key: "second", reads: 1, wantHot: true},
+				{key: "third", reads: 3, wantHot: false},
+			},
+			want: []string{"first", "second"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Now()}
+			h := newHotKeys(tt.config)
+			h.now = clock.Now
+
+			ctx := context.Background()
+			for i, v := range tt.steps {
+				clock.now = clock.now.Add(v.wait)
+
+				var hot bool
+				for j := 0; j < v.reads; j++ {
+					hot = h.record(ctx, "test", v.key, "")
+				}
+				assert.Equal(t, v.wantHot, hot, "step %d", i)
+			}
+
+			got := make([]string, 0)
+			for _, v := range h.report() {
+				got = append(got, v.Key)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHotKeys_NotSampled(t *testing.T) {
+	h := newHotKeys(HotKeyConfig{SampleRate: 1, Threshold: 2, Window: time.Hour})
+	sampled := true
+	h.sample = func() bool { return sampled }
+
+	ctx := context.Background()
+	h.record(ctx, "test", "hot", "")
+	h.record(ctx, "test", "hot", "")
+	h.record(ctx, "test", "cold", "")
+
+	// The reads not sampled are still served from the local cache when the key is hot.
+	sampled = false
+	assert.True(t, h.record(ctx, "test", "hot", "field"))
+	assert.False(t, h.record(ctx, "test", "cold", ""))
+	assert.Equal(t, []string{"hot", localFieldKey("hot", "field")}, h.localKeys("hot"))
+}
+
+func TestHotKeys_Report(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	h := newHotKeys(HotKeyConfig{SampleRate: 0.5, Threshold: 2, Window: time.Second})
+	h.now = clock.Now
+	h.sample = func() bool { return true }
+
+	since := clock.now
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		h.record(ctx, "test", "first", "")
+	}
+	for i := 0; i < 2; i++ {
+		h.record(ctx, "test", "second", "")
+	}
+
+	// The counts of the samples are scaled by the sample rate.
+	want := []HotKey{
+		{Key: "first", Count: 8, Since: since},
+		{Key: "second", Count: 4, Since: since},
+	}
+	assert.Equal(t, want, h.report())
+
+	// The count of the last window is reported until the current one is greater.
+	clock.now = clock.now.Add(time.Second)
+	h.record(ctx, "test", "second", "")
+	assert.Equal(t, want, h.report())
+}
+
+func TestHotKeys_Hotspot(t *testing.T) {
+	const (
+		keys  = 1000
+		reads = 100000
+	)
+
+	// The hotspot generator reads the first 10% of the keys 90% of the time,
+	// about 900 reads of every hot key, and 11 reads of every cold key.
+	h := newHotKeys(HotKeyConfig{SampleRate: 0.2, Threshold: 400, Window: time.Hour, MaxHotKeys: keys})
+	generator := benchmark.NewHotspot(keys)
+
+	ctx := context.Background()
+	for i := 0; i < reads; i++ {
+		h.record(ctx, "test", generator.Next(), "")
+	}
+
+	report := h.report()
+	assert.True(t, len(report) >= 95, "%d hot keys", len(report))
+	for _, v := range report {
+		n, err := strconv.Atoi(v.Key)
+		assert.NoError(t, err)
+		assert.True(t, n < keys/10, "cold key %s detected", v.Key)
+	}
+}
@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// InstrumentConfig is the configuration of the instrumented clients.
+type InstrumentConfig struct {
+	// Buckets are the upper bounds of the latency histogram, in increasing order.
+	Buckets []time.Duration
+	// OnCommand is called after every command, e.g. to report the command to another metrics system.
+	OnCommand func(ctx context.Context, event CommandEvent)
+}
+
+// DefaultInstrumentConfig is the default configuration of the instrumented clients.
+var DefaultInstrumentConfig = InstrumentConfig{
+	Buckets: []time.Duration{
+		500 * time.Microsecond,
+		time.Millisecond,
+		2500 * time.Microsecond,
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		25 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+	},
+	OnCommand: nil,
+}
+
+// CommandEvent describes a command of an instrumented client.
+type CommandEvent struct {
+	// Client is the name of the instrumented client.
+	Client string
+	// Command is the name of the client method, e.g. "Get".
+	Command string
+	Latency time.Duration
+	// Hit and Miss report whether a lookup command found the key, both are false for the other commands.
+	Hit  bool
+	Miss bool
+	// Err is the error of the command, the missing keys of the lookup commands are reported as misses instead.
+	Err error
+}
+
+// CacheStats is a snapshot of the stats of an instrumented client.
+type CacheStats struct {
+	// Client is the name of the instrumented client.
+	Client string
+	// Commands are the stats of the commands by the name of the client method.
+	Commands map[string]CommandStats
+	// Buckets are the upper bounds of the latency histogram of the commands.
+	Buckets []time.Duration
+	// Pool is nil when the client doesn't report the stats of its connection pool.
+	Pool *PoolStats
+	// Ristretto is nil when the client isn't an in-process cache with the metrics enabled.
+	Ristretto *RistrettoMetrics
+}
+
+// CommandStats are the stats of a command.
+type CommandStats struct {
+	Calls uint64
+	// Hits and Misses are only counted by the lookup commands, e.g. Get and Exists.
+	Hits   uint64
+	Misses uint64
+	// Errors are the numbers of errors by their code, the missing keys of the lookup commands aren't errors.
+	Errors map[errorx.Code]uint64
+	// Latency is the total latency of the calls.
+	Latency time.Duration
+	// LatencyBuckets are the numbers of calls with a latency up to the bounds of the buckets,
+	// the calls slower than the last bound are only counted by Calls.
+	LatencyBuckets []uint64
+}
+
+// PoolStats are the stats of a connection pool, of all the nodes in cluster mode.
+type PoolStats struct {
+	// OpenConnections is the number of connections in the pool, in use or idle.
+	OpenConnections int
+	InUse           int
+	Idle            int
+	// WaitCount and WaitDuration are the total number of waits for a connection and their total duration,
+	// only reported by redigo.
+	WaitCount    int64
+	WaitDuration time.Duration
+	// Timeouts is the number of waits for a connection timing out, only reported by go-redis.
+	Timeouts int64
+}
+
+// RistrettoMetrics are the metrics of an in-process cache.
+type RistrettoMetrics struct {
+	Hits         uint64
+	Misses       uint64
+	KeysAdded    uint64
+	KeysUpdated  uint64
+	KeysEvicted  uint64
+	CostAdded    uint64
+	CostEvicted  uint64
+	SetsDropped  uint64
+	SetsRejected uint64
+	GetsDropped  uint64
+	GetsKept     uint64
+	// Ratio is the ratio of the hits to the lookups.
+	Ratio float64
+}
+
+// recorder records the commands of an instrumented client.
+type recorder struct {
+	client string
+	config InstrumentConfig
+
+	mu       sync.Mutex
+	commands map[string]*CommandStats
+}
+
+// newRecorder returns a recorder of the client with the configuration.
+func newRecorder(client string, config InstrumentConfig) *recorder {
+	// Defaults
+	if len(config.Buckets) == 0 {
+		config.Buckets = DefaultInstrumentConfig.Buckets
+	}
+
+	buckets := append([]time.Duration(nil), config.Buckets...)
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i] < buckets[j]
+	})
+	config.Buckets = buckets
+
+	return &recorder{
+		client:   client,
+		config:   config,
+		commands: make(map[string]*CommandStats),
+	}
+}
+
+// record records a command started at start.
+func (r *recorder) record(ctx context.Context, command string, start time.Time, err error) {
+	r.add(ctx, CommandEvent{
+		Client:  r.client,
+		Command: command,
+		Latency: time.Since(start),
+		Err:     err,
+	})
+}
+
+// lookup records a lookup command started at start, the found keys are hits and the missing keys are misses.
+// The missing keys reported as an error with errorx.CodeNotFound are misses too.
+func (r *recorder) lookup(ctx context.Context, command string, start time.Time, err error, found bool) {
+	event := CommandEvent{
+		Client:  r.client,
+		Command: command,
+		Latency: time.Since(start),
+		Err:     err,
+	}
+	switch {
+	case err != nil && errorx.Is(errorx.CodeNotFound, err):
+		event.Miss, event.Err = true, nil
+	case err != nil:
+		// The other errors are counted by code.
+	case found:
+		event.Hit = true
+	default:
+		event.Miss = true
+	}
+
+	r.add(ctx, event)
+}
+
+// add counts the event, and passes it to the hook of the configuration.
+func (r *recorder) add(ctx context.Context, event CommandEvent) {
+	r.mu.Lock()
+	stats, ok := r.commands[event.Command]
+	if !ok {
+		stats = &CommandStats{
+			Errors:         make(map[errorx.Code]uint64),
+			LatencyBuckets: make([]uint64, len(r.config.Buckets)),
+		}
+		r.commands[event.Command] = stats
+	}
+
+	stats.Calls++
+	stats.Latency += event.Latency
+	for i, bound := range r.config.Buckets {
+		if event.Latency <= bound {
+			stats.LatencyBuckets[i]++
+		}
+	}
+	switch {
+	case event.Hit:
+		stats.Hits++
+	case event.Miss:
+		stats.Misses++
+	}
+	if event.Err != nil {
+		stats.Errors[errorx.GetCode(event.Err)]++
+	}
+	r.mu.Unlock()
+
+	if r.config.OnCommand != nil {
+		r.config.OnCommand(ctx, event)
+	}
+}
+
+// stats returns a snapshot of the stats of the commands.
+func (r *recorder) stats() CacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	commands := make(map[string]CommandStats, len(r.commands))
+	for command, v := range r.commands {
+		stats := *v
+		stats.Errors = make(map[errorx.Code]uint64, len(v.Errors))
+		for code, count := range v.Errors {
+			stats.Errors[code] = count
+		}
+		stats.LatencyBuckets = append([]uint64(nil), v.LatencyBuckets...)
+		commands[command] = stats
+	}
+
+	return CacheStats{
+		Client:   r.client,
+		Commands: commands,
+		Buckets:  append([]time.Duration(nil), r.config.Buckets...),
+	}
+}
@@ -0,0 +1,440 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// InstrumentedRedis is a redis client recording the latency of the commands, the hits and misses of the lookups,
+// and the errors by code, see CacheStats.
+// The commands are passed to the instrumented client as is, and their results are returned unchanged.
+type InstrumentedRedis struct {
+	client   RedisItf
+	recorder *recorder
+}
+
+// NewInstrumentedRedis returns the client instrumenting the redis client, with the default configuration.
+// The name identifies the client in the stats.
+func NewInstrumentedRedis(name string, client RedisItf) *InstrumentedRedis {
+	return NewInstrumentedRedisWithConfig(name, client, DefaultInstrumentConfig)
+}
+
+// NewInstrumentedRedisWithConfig returns the client instrumenting the redis client.
+// The name identifies the client in the stats.
+func NewInstrumentedRedisWithConfig(name string, client RedisItf, config InstrumentConfig) *InstrumentedRedis {
+	return &InstrumentedRedis{
+		client:   client,
+		recorder: newRecorder(name, config),
+	}
+}
+
+// Stats returns a snapshot of the stats of the commands,
+// and of the connection pool when the instrumented client implements PoolReporterItf.
+func (r *InstrumentedRedis) Stats() CacheStats {
+	stats := r.recorder.stats()
+	if client, ok := r.client.(PoolReporterItf); ok {
+		pool := client.PoolStats()
+		stats.Pool = &pool
+	}
+
+	return stats
+}
+
+// Get gets the value from redis in []byte form.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *InstrumentedRedis) Get(ctx context.Context, key string) ([]byte, error) {
+	began := time.Now()
+	res, err := r.client.Get(ctx, key)
+	r.recorder.lookup(ctx, "Get", began, err, err == nil)
+
+	return res, err
+}
+
+// SimpleSet sets value to key in redis without any additional options.
+// Key doesn't have a TTL.
+func (r *InstrumentedRedis) SimpleSet(ctx context.Context, key, value string) error {
+	began := time.Now()
+	err := r.client.SimpleSet(ctx, key, value)
+	r.recorder.record(ctx, "SimpleSet", began, err)
+
+	return err
+}
+
+// SetEX sets the value to a key with timeout in seconds.
+func (r *InstrumentedRedis) SetEX(ctx context.Context, key string, seconds int64, value string) error {
+	began := time.Now()
+	err := r.client.SetEX(ctx, key, seconds, value)
+	r.recorder.record(ctx, "SetEX", began, err)
+
+	return err
+}
+
+// SetNX sets a value to a key with specified timeouts.
+// SetNX returns false if the key exists.
+func (r *InstrumentedRedis) SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error) {
+	began := time.Now()
+	res, err := r.client.SetNX(ctx, key, seconds, value)
+	r.recorder.record(ctx, "SetNX", began, err)
+
+	return res, err
+}
+
+// Exists checks whether the key exists in redis.
+func (r *InstrumentedRedis) Exists(ctx context.Context, key string) (bool, error) {
+	began := time.Now()
+	res, err := r.client.Exists(ctx, key)
+	r.recorder.lookup(ctx, "Exists", began, err, res)
+
+	return res, err
+}
+
+// Expire sets the TTL of a key to specified value in seconds.
+func (r *InstrumentedRedis) Expire(ctx context.Context, key string, seconds int64) (bool, error) {
+	began := time.Now()
+	res, err := r.client.Expire(ctx, key, seconds)
+	r.recorder.record(ctx, "Expire", began, err)
+
+	return res, err
+}
+
+// ExpireAt sets the TTL of a key to a certain unix timestamp in seconds.
+func (r *InstrumentedRedis) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
+	began := time.Now()
+	res, err := r.client.ExpireAt(ctx, key, timestamp)
+	r.recorder.record(ctx, "ExpireAt", began, err)
+
+	return res, err
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+// It returns -2 if the key does not exist and -1 if the key has no expiry.
+func (r *InstrumentedRedis) TTL(ctx context.Context, key string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.TTL(ctx, key)
+	r.recorder.record(ctx, "TTL", began, err)
+
+	return res, err
+}
+
+// Del deletes a key.
+func (r *InstrumentedRedis) Del(ctx context.Context, key ...interface{}) (int64, error) {
+	began := time.Now()
+	res, err := r.client.Del(ctx, key...)
+	r.recorder.record(ctx, "Del", began, err)
+
+	return res, err
+}
+
+// Incr increments the integer value of a key by 1.
+func (r *InstrumentedRedis) Incr(ctx context.Context, key string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.Incr(ctx, key)
+	r.recorder.record(ctx, "Incr", began, err)
+
+	return res, err
+}
+
+// Decr decrements the integer value of a key by 1.
+func (r *InstrumentedRedis) Decr(ctx context.Context, key string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.Decr(ctx, key)
+	r.recorder.record(ctx, "Decr", began, err)
+
+	return res, err
+}
+
+// IncrBy increments the integer value of a key by the given amount.
+func (r *InstrumentedRedis) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	began := time.Now()
+	res, err := r.client.IncrBy(ctx, key, by)
+	r.recorder.record(ctx, "IncrBy", began, err)
+
+	return res, err
+}
+
+// IncrByEx increments the integer value of a key by the given amount,
+// and sets the TTL of the key to specified value in seconds.
+func (r *InstrumentedRedis) IncrByEx(ctx context.Context, key string, by int64, expires int64) (int64, error) {
+	began := time.Now()
+	res, err := r.client.IncrByEx(ctx, key, by, expires)
+	r.recorder.record(ctx, "IncrByEx", began, err)
+
+	return res, err
+}
+
+// HGet gets the value of a hash field.
+// Missing key or field is reported as an error with errorx.CodeNotFound.
+func (r *InstrumentedRedis) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	began := time.Now()
+	res, err := r.client.HGet(ctx, key, field)
+	r.recorder.lookup(ctx, "HGet", began, err, err == nil)
+
+	return res, err
+}
+
+// HMGet gets the values of multiple hash fields in the order of the fields.
+// Missing field is returned as a nil value.
+func (r *InstrumentedRedis) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
+	began := time.Now()
+	res, err := r.client.HMGet(ctx, key, fields...)
+	r.recorder.record(ctx, "HMGet", began, err)
+
+	return res, err
+}
+
+// HGetAll gets all the fields and values in a hash.
+func (r *InstrumentedRedis) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	began := time.Now()
+	res, err := r.client.HGetAll(ctx, key)
+	r.recorder.record(ctx, "HGetAll", began, err)
+
+	return res, err
+}
+
+// HKeys gets all the fields in a hash.
+func (r *InstrumentedRedis) HKeys(ctx context.Context, key string) ([]string, error) {
+	began := time.Now()
+	res, err := r.client.HKeys(ctx, key)
+	r.recorder.record(ctx, "HKeys", began, err)
+
+	return res, err
+}
+
+// HExists determines if a hash field exists.
+func (r *InstrumentedRedis) HExists(ctx context.Context, key, field string) (bool, error) {
+	began := time.Now()
+	res, err := r.client.HExists(ctx, key, field)
+	r.recorder.lookup(ctx, "HExists", began, err, res)
+
+	return res, err
+}
+
+// HSet sets the string value of a hash field.
+func (r *InstrumentedRedis) HSet(ctx context.Context, key, field, value string) (bool, error) {
+	began := time.Now()
+	res, err := r.client.HSet(ctx, key, field, value)
+	r.recorder.record(ctx, "HSet", began, err)
+
+	return res, err
+}
+
+// HDel deletes hash fields and returns the number of deleted fields.
+func (r *InstrumentedRedis) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.HDel(ctx, key, fields...)
+	r.recorder.record(ctx, "HDel", began, err)
+
+	return res, err
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (r *InstrumentedRedis) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.LPush(ctx, key, values...)
+	r.recorder.record(ctx, "LPush", began, err)
+
+	return res, err
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (r *InstrumentedRedis) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.RPush(ctx, key, values...)
+	r.recorder.record(ctx, "RPush", began, err)
+
+	return res, err
+}
+
+// LRange gets the elements of a list between index start and stop.
+func (r *InstrumentedRedis) LRange(ctx context.Context, key string, start, stop int64) ([][]byte, error) {
+	began := time.Now()
+	res, err := r.client.LRange(ctx, key, start, stop)
+	r.recorder.record(ctx, "LRange", began, err)
+
+	return res, err
+}
+
+// LTrim trims a list to the elements between index start and stop.
+func (r *InstrumentedRedis) LTrim(ctx context.Context, key string, start, stop int64) error {
+	began := time.Now()
+	err := r.client.LTrim(ctx, key, start, stop)
+	r.recorder.record(ctx, "LTrim", began, err)
+
+	return err
+}
+
+// LLen gets the length of a list.
+func (r *InstrumentedRedis) LLen(ctx context.Context, key string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.LLen(ctx, key)
+	r.recorder.record(ctx, "LLen", began, err)
+
+	return res, err
+}
+
+// SAdd add the specified members to the set stored at key.
+// It returns false if key and value combination exists.
+func (r *InstrumentedRedis) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
+	began := time.Now()
+	res, err := r.client.SAdd(ctx, key, value...)
+	r.recorder.record(ctx, "SAdd", began, err)
+
+	return res, err
+}
+
+// SRem removes the members from a set and returns the number of removed members.
+func (r *InstrumentedRedis) SRem(ctx context.Context, key string, value ...string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.SRem(ctx, key, value...)
+	r.recorder.record(ctx, "SRem", began, err)
+
+	return res, err
+}
+
+// SMembers gets all the members of a set.
+func (r *InstrumentedRedis) SMembers(ctx context.Context, key string) ([]string, error) {
+	began := time.Now()
+	res, err := r.client.SMembers(ctx, key)
+	r.recorder.record(ctx, "SMembers", began, err)
+
+	return res, err
+}
+
+// SIsMember determines if a value is a member of a set.
+func (r *InstrumentedRedis) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	began := time.Now()
+	res, err := r.client.SIsMember(ctx, key, value)
+	r.recorder.lookup(ctx, "SIsMember", began, err, res)
+
+	return res, err
+}
+
+// SCard gets the number of members of a set.
+func (r *InstrumentedRedis) SCard(ctx context.Context, key string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.SCard(ctx, key)
+	r.recorder.record(ctx, "SCard", began, err)
+
+	return res, err
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (r *InstrumentedRedis) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	began := time.Now()
+	res, err := r.client.ZAdd(ctx, key, members...)
+	r.recorder.record(ctx, "ZAdd", began, err)
+
+	return res, err
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (r *InstrumentedRedis) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	began := time.Now()
+	res, err := r.client.ZIncrBy(ctx, key, increment, member)
+	r.recorder.record(ctx, "ZIncrBy", began, err)
+
+	return res, err
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (r *InstrumentedRedis) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	began := time.Now()
+	res, err := r.client.ZRange(ctx, key, start, stop)
+	r.recorder.record(ctx, "ZRange", began, err)
+
+	return res, err
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (r *InstrumentedRedis) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	began := time.Now()
+	res, err := r.client.ZRevRange(ctx, key, start, stop)
+	r.recorder.record(ctx, "ZRevRange", began, err)
+
+	return res, err
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Min and max accept the redis score syntax, e.g. "-inf", "+inf" and "(1" for exclusive bound.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (r *InstrumentedRedis) ZRangeByScore(ctx context.Context, key, min, max string, offset, count int64) ([]ZMember, error) {
+	began := time.Now()
+	res, err := r.client.ZRangeByScore(ctx, key, min, max, offset, count)
+	r.recorder.record(ctx, "ZRangeByScore", began, err)
+
+	return res, err
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *InstrumentedRedis) ZRank(ctx context.Context, key, member string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.ZRank(ctx, key, member)
+	r.recorder.lookup(ctx, "ZRank", began, err, err == nil)
+
+	return res, err
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *InstrumentedRedis) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.ZRevRank(ctx, key, member)
+	r.recorder.lookup(ctx, "ZRevRank", began, err, err == nil)
+
+	return res, err
+}
+
+// ZScore gets the score of a sorted set member.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *InstrumentedRedis) ZScore(ctx context.Context, key, member string) (float64, error) {
+	began := time.Now()
+	res, err := r.client.ZScore(ctx, key, member)
+	r.recorder.lookup(ctx, "ZScore", began, err, err == nil)
+
+	return res, err
+}
+
+// ZCard gets the number of members of a sorted set.
+func (r *InstrumentedRedis) ZCard(ctx context.Context, key string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.ZCard(ctx, key)
+	r.recorder.record(ctx, "ZCard", began, err)
+
+	return res, err
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (r *InstrumentedRedis) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.ZRem(ctx, key, members...)
+	r.recorder.record(ctx, "ZRem", began, err)
+
+	return res, err
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (r *InstrumentedRedis) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	began := time.Now()
+	res, err := r.client.ZRemRangeByScore(ctx, key, min, max)
+	r.recorder.record(ctx, "ZRemRangeByScore", began, err)
+
+	return res, err
+}
+
+// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+func (r *InstrumentedRedis) Publish(ctx context.Context, topic, message string) (int, error) {
+	began := time.Now()
+	res, err := r.client.Publish(ctx, topic, message)
+	r.recorder.record(ctx, "Publish", began, err)
+
+	return res, err
+}
+
+// Close closes the client, releasing any open resources.
+func (r *InstrumentedRedis) Close() {
+	r.client.Close()
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// pooledClient is a redis client reporting the stats of its connection pool.
+type pooledClient struct {
+	*MockRedisItf
+	*MockPoolReporterItf
+}
+
+func TestInstrumentedRedis(t *testing.T) {
+	tests := []struct {
+		name string
+		mock func(client *MockRedisItf)
+		call func(client *InstrumentedRedis)
+		want map[string]CommandStats
+	}{
+		{
+			name: "Hit",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "key").Return([]byte("value"), nil)
+			},
+			call: func(client *InstrumentedRedis) {
+				_, _ = client.Get(context.Background(), "key")
+			},
+			want: map[string]CommandStats{
+				"Get": {Calls: 1, Hits: 1, Errors: map[errorx.Code]uint64{}},
+			},
+		},
+		{
+			name: "Miss reported as an error",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+			},
+			call: func(client *InstrumentedRedis) {
+				_, _ = client.Get(context.Background(), "key")
+			},
+			want: map[string]CommandStats{
+				"Get": {Calls: 1, Misses: 1, Errors: map[errorx.Code]uint64{}},
+			},
+		},
+		{
+			name: "Miss reported as false",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().Exists(gomock.Any(), "key").Return(true, nil)
+				client.EXPECT().Exists(gomock.Any(), "key").Return(false, nil)
+			},
+			call: func(client *InstrumentedRedis) {
+				_, _ = client.Exists(context.Background(), "key")
+				_, _ = client.Exists(context.Background(), "key")
+			},
+			want: map[string]CommandStats{
+				"Exists": {Calls: 2, Hits: 1, Misses: 1, Errors: map[errorx.Code]uint64{}},
+			},
+		},
+		{
+			name: "Errors by code",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("redis error", errorx.CodeGateway))
+				client.EXPECT().SetEX(gomock.Any(), "key", int64(60), "value").Return(errorx.E("redis error", errorx.CodeGateway))
+				client.EXPECT().SetEX(gomock.Any(), "key", int64(60), "value").Return(errorx.E("closed", errorx.CodeConfig))
+			},
+			call: func(client *InstrumentedRedis) {
+				_, _ = client.Get(context.Background(), "key")
+				_ = client.SetEX(context.Background(), "key", 60, "value")
+				_ = client.SetEX(context.Background(), "key", 60, "value")
+			},
+			want: map[string]CommandStats{
+				"Get":   {Calls: 1, Errors: map[errorx.Code]uint64{errorx.CodeGateway: 1}},
+				"SetEX": {Calls: 2, Errors: map[errorx.Code]uint64{errorx.CodeGateway: 1, errorx.CodeConfig: 1}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := NewMockRedisItf(ctrl)
+			tt.mock(client)
+
+			instrumented := NewInstrumentedRedisWithConfig("session", client, InstrumentConfig{
+				Buckets: []time.Duration{time.Hour},
+			})
+			tt.call(instrumented)
+
+			got := instrumented.Stats()
+			assert.Equal(t, "session", got.Client)
+			assert.Nil(t, got.Pool)
+			assert.Len(t, got.Commands, len(tt.want))
+			for command, want := range tt.want {
+				stats := got.Commands[command]
+				assert.Equal(t, want.Calls, stats.Calls, command)
+				assert.Equal(t, want.Hits, stats.Hits, command)
+				assert.Equal(t, want.Misses, stats.Misses, command)
+				assert.Equal(t, want.Errors, stats.Errors, command)
+				assert.Equal(t, []uint64{want.Calls}, stats.LatencyBuckets, command)
+			}
+		})
+	}
+}
+
+func TestInstrumentedRedis_OnCommand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := NewMockRedisItf(ctrl)
+	client.EXPECT().HGet(gomock.Any(), "key", "field").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+
+	var events []CommandEvent
+	instrumented := NewInstrumentedRedisWithConfig("session", client, InstrumentConfig{
+		OnCommand: func(_ context.Context, event CommandEvent) {
+			events = append(events, event)
+		},
+	})
+
+	_, err := instrumented.HGet(context.Background(), "key", "field")
+	assert.True(t, errorx.Is(errorx.CodeNotFound, err))
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "session", events[0].Client)
+		assert.Equal(t, "HGet", events[0].Command)
+		assert.True(t, events[0].Miss)
+		assert.NoError(t, events[0].Err)
+	}
+	assert.Equal(t, DefaultInstrumentConfig.Buckets, instrumented.Stats().Buckets)
+}
+
+func TestInstrumentedRedis_Stats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pool := NewMockPoolReporterItf(ctrl)
+	pool.EXPECT().PoolStats().Return(PoolStats{OpenConnections: 3, InUse: 1, Idle: 2, WaitCount: 4})
+
+	instrumented := NewInstrumentedRedis("session", pooledClient{
+		MockRedisItf:        NewMockRedisItf(ctrl),
+		MockPoolReporterItf: pool,
+	})
+
+	got := instrumented.Stats()
+	assert.Equal(t, &PoolStats{OpenConnections: 3, InUse: 1, Idle: 2, WaitCount: 4}, got.Pool)
+	assert.Empty(t, got.Commands)
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// InstrumentedRistretto is an in-process cache client recording the latency of the commands,
+// and the hits and misses of the lookups, see CacheStats.
+// The commands are passed to the instrumented client as is, and their results are returned unchanged.
+type InstrumentedRistretto struct {
+	client   RistrettoItf
+	recorder *recorder
+}
+
+// NewInstrumentedRistretto returns the client instrumenting the in-process cache client, with the default configuration.
+// The name identifies the client in the stats.
+func NewInstrumentedRistretto(name string, client RistrettoItf) *InstrumentedRistretto {
+	return NewInstrumentedRistrettoWithConfig(name, client, DefaultInstrumentConfig)
+}
+
+// NewInstrumentedRistrettoWithConfig returns the client instrumenting the in-process cache client.
+// The name identifies the client in the stats.
+func NewInstrumentedRistrettoWithConfig(name string, client RistrettoItf, config InstrumentConfig) *InstrumentedRistretto {
+	return &InstrumentedRistretto{
+		client:   client,
+		recorder: newRecorder(name, config),
+	}
+}
+
+// Stats returns a snapshot of the stats of the commands,
+// and of the metrics of the cache when the instrumented client implements RistrettoMetricsItf.
+func (r *InstrumentedRistretto) Stats() CacheStats {
+	stats := r.recorder.stats()
+	if client, ok := r.client.(RistrettoMetricsItf); ok {
+		stats.Ristretto = client.Metrics()
+	}
+
+	return stats
+}
+
+// Get returns the value (if any) and a boolean representing whether the value was found or not.
+func (r *InstrumentedRistretto) Get(ctx context.Context, key string) (res interface{}, exists bool) {
+	began := time.Now()
+	res, exists = r.client.Get(ctx, key)
+	r.recorder.lookup(ctx, "Get", began, nil, exists)
+
+	return res, exists
+}
+
+// Lookup works like Get but reports the missing key as an error with errorx.CodeNotFound.
+func (r *InstrumentedRistretto) Lookup(ctx context.Context, key string) (interface{}, error) {
+	began := time.Now()
+	res, err := r.client.Lookup(ctx, key)
+	r.recorder.lookup(ctx, "Lookup", began, err, err == nil)
+
+	return res, err
+}
+
+// Set attempts to add the key-value item to the cache.
+// If it returns false, then the Set was dropped and the key-value item isn't added to the cache.
+// If it returns true, there's still a chance it could be dropped by the policy
+// if its determined that the key-value item isn't worth keeping,
+// but otherwise the item will be added and other items will be evicted in order to make room.
+func (r *InstrumentedRistretto) Set(ctx context.Context, key string, value interface{}) bool {
+	began := time.Now()
+	res := r.client.Set(ctx, key, value)
+	r.recorder.record(ctx, "Set", began, nil)
+
+	return res
+}
+
+// SetEX works like Set but adds a key-value pair to the cache
+// that will expire after the specified TTL (time to live) has passed.
+// A zero value means the value never expires, which is identical to calling Set.
+// A negative value is a no-op and the value is discarded.
+func (r *InstrumentedRistretto) SetEX(ctx context.Context, key string, value interface{}, ttl time.Duration) bool {
+	began := time.Now()
+	res := r.client.SetEX(ctx, key, value, ttl)
+	r.recorder.record(ctx, "SetEX", began, nil)
+
+	return res
+}
+
+// SetWithCost works like Set but adds the item with the given cost, e.g. its size in bytes.
+// The items are evicted once the sum of their costs reaches MaxCost.
+func (r *InstrumentedRistretto) SetWithCost(ctx context.Context, key string, value interface{}, cost int64) bool {
+	began := time.Now()
+	res := r.client.SetWithCost(ctx, key, value, cost)
+	r.recorder.record(ctx, "SetWithCost", began, nil)
+
+	return res
+}
+
+// SetEXWithCost works like SetEX but adds the item with the given cost.
+func (r *InstrumentedRistretto) SetEXWithCost(
+	ctx context.Context,
+	key string,
+	value interface{},
+	cost int64,
+	ttl time.Duration,
+) bool {
+	began := time.Now()
+	res := r.client.SetEXWithCost(ctx, key, value, cost, ttl)
+	r.recorder.record(ctx, "SetEXWithCost", began, nil)
+
+	return res
+}
+
+// GetTTL returns the remaining time to live of a key, zero when the key never expires,
+// and a boolean representing whether the key was found or not.
+func (r *InstrumentedRistretto) GetTTL(ctx context.Context, key string) (time.Duration, bool) {
+	began := time.Now()
+	res, exists := r.client.GetTTL(ctx, key)
+	r.recorder.lookup(ctx, "GetTTL", began, nil, exists)
+
+	return res, exists
+}
+
+// Wait blocks until the pending sets are applied, so they are visible to Get.
+// The sets are buffered and applied asynchronously, it is mostly useful in tests.
+func (r *InstrumentedRistretto) Wait() {
+	r.client.Wait()
+}
+
+// Del deletes the key-value item from the cache if it exists.
+func (r *InstrumentedRistretto) Del(ctx context.Context, key string) {
+	began := time.Now()
+	r.client.Del(ctx, key)
+	r.recorder.record(ctx, "Del", began, nil)
+}
+
+// Clear empties the cache store and zeroes all policy counters.
+func (r *InstrumentedRistretto) Clear(ctx context.Context) {
+	began := time.Now()
+	r.client.Clear(ctx)
+	r.recorder.record(ctx, "Clear", began, nil)
+}
+
+// Close stops all goroutines and closes all channels.
+func (r *InstrumentedRistretto) Close() {
+	r.client.Close()
+}
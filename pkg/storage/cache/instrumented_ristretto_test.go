@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentedRistretto(t *testing.T) {
+	client, err := NewRistrettoInstance(&RistrettoConfiguration{
+		NumCounters: 1e3,
+		MaxCost:     1 << 20,
+		BufferItems: 64,
+		Metrics:     true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	instrumented := NewInstrumentedRistretto("local", client)
+	defer instrumented.Close()
+
+	ctx := context.Background()
+	instrumented.Set(ctx, "key", "value")
+	instrumented.Wait()
+
+	_, _ = instrumented.Get(ctx, "key")
+	_, _ = instrumented.Get(ctx, "missing")
+	_, _ = instrumented.Lookup(ctx, "missing")
+
+	got := instrumented.Stats()
+	assert.Equal(t, "local", got.Client)
+	assert.Equal(t, uint64(1), got.Commands["Set"].Calls)
+	assert.Equal(t, uint64(2), got.Commands["Get"].Calls)
+	assert.Equal(t, uint64(1), got.Commands["Get"].Hits)
+	assert.Equal(t, uint64(1), got.Commands["Get"].Misses)
+	assert.Equal(t, uint64(1), got.Commands["Lookup"].Misses)
+	assert.Empty(t, got.Commands["Lookup"].Errors)
+	if assert.NotNil(t, got.Ristretto) {
+		assert.Equal(t, uint64(1), got.Ristretto.Hits)
+		assert.Equal(t, uint64(2), got.Ristretto.Misses)
+		assert.Equal(t, uint64(1), got.Ristretto.KeysAdded)
+	}
+}
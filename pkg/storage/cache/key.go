@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/peractio/gdk/pkg/env"
+)
+
+// KeyBuilderConfig configuration.
+type KeyBuilderConfig struct {
+	// Service is the name of the service owning the keys, e.g. "order".
+	// Empty string means the keys aren't prefixed by the service.
+	Service string
+	// Env is the environment of the keys, empty string means the current environment, see env.GetCurrent.
+	Env string
+	// Version is bumped when the format of the values changes, so the values of the former format are never read.
+	// Zero value means the keys aren't versioned.
+	Version int
+	// Separator joins the parts of the keys.
+	Separator string
+	// MaxLength is the maximum length of the keys, the longer keys are hashed with SHA-256.
+	// Zero value means the keys are never hashed.
+	MaxLength int
+}
+
+// DefaultKeyBuilderConfig is the default configuration of the key builders.
+var DefaultKeyBuilderConfig = KeyBuilderConfig{
+	Service:   "",
+	Env:       "",
+	Version:   0,
+	Separator: ":",
+	MaxLength: 0,
+}
+
+// KeyBuilder builds the keys of a service, prefixed by the service, the environment and the version,
+// so the services sharing a redis cluster never collide.
+//
+// Example:
+//
+//	keys := cache.NewKeyBuilder("order")
+//	keys.Key("user", "1")              // "order:production:user:1"
+//	keys.TaggedKey("user:1", "cart")   // "order:production:{user:1}:cart"
+type KeyBuilder struct {
+	config KeyBuilderConfig
+	prefix string
+}
+
+// NewKeyBuilder returns the key builder of a service, with the default configuration.
+func NewKeyBuilder(service string) *KeyBuilder {
+	config := DefaultKeyBuilderConfig
+	config.Service = service
+
+	return NewKeyBuilderWithConfig(config)
+}
+
+// NewKeyBuilderWithConfig returns a key builder with custom configuration.
+func NewKeyBuilderWithConfig(config KeyBuilderConfig) *KeyBuilder {
+	// Defaults
+	if config.Env == "" {
+		config.Env = env.GetCurrent()
+	}
+	if config.Separator == "" {
+		config.Separator = DefaultKeyBuilderConfig.Separator
+	}
+
+	parts := make([]string, 0, 3)
+	if config.Service != "" {
+		parts = append(parts, config.Service)
+	}
+	parts = append(parts, config.Env)
+	if config.Version > 0 {
+		parts = append(parts, "v"+strconv.Itoa(config.Version))
+	}
+
+	return &KeyBuilder{
+		config: config,
+		prefix: strings.Join(parts, config.Separator) + config.Separator,
+	}
+}
+
+// Prefix returns the prefix of the keys, e.g. "order:production:v2:".
+func (b *KeyBuilder) Prefix() string {
+	return b.prefix
+}
+
+// Key joins the parts with the separator and prefixes them, e.g. "order:production:user:1".
+// The key longer than MaxLength is hashed, see Hash.
+func (b *KeyBuilder) Key(parts ...string) string {
+	return b.Hash(b.prefix + strings.Join(parts, b.config.Separator))
+}
+
+// TaggedKey returns the key of the parts with the hash tag, e.g. "order:production:{user:1}:cart".
+// The keys of the same tag share the cluster hash slot, so they can be used by the same multi-key command,
+// pipeline or script.
+func (b *KeyBuilder) TaggedKey(tag string, parts ...string) string {
+	return b.Key(append([]string{WithHashTag(tag)}, parts...)...)
+}
+
+// Hash returns the key hashed with SHA-256 when it is longer than MaxLength, or the key as is.
+// The prefix and the hash tag are kept, so the hashed key stays in the namespace and in the same hash slot,
+// e.g. "order:production:{user:1}:<hex digest>".
+func (b *KeyBuilder) Hash(key string) string {
+	if b.config.MaxLength <= 0 || len(key) <= b.config.MaxLength {
+		return key
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	digest := hex.EncodeToString(sum[:])
+
+	prefix := ""
+	if strings.HasPrefix(key, b.prefix) {
+		prefix = b.prefix
+	}
+	if tag := HashTag(key); tag != key {
+		prefix += WithHashTag(tag) + b.config.Separator
+	}
+
+	return prefix + digest
+}
+
+// WithHashTag wraps the tag in braces, the keys containing the same hash tag share the cluster hash slot.
+func WithHashTag(tag string) string {
+	return "{" + tag + "}"
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peractio/gdk/pkg/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyBuilder_Key(t *testing.T) {
+	tests := []struct {
+		name   string
+		config KeyBuilderConfig
+		parts  []string
+		want   string
+	}{
+		{
+			name:   "Current environment",
+			config: KeyBuilderConfig{Service: "order"},
+			parts:  []string{"user", "1"},
+			want:   "order:" + env.GetCurrent() + ":user:1",
+		},
+		{
+			name:   "Without service",
+			config: KeyBuilderConfig{Env: env.Production},
+			parts:  []string{"user", "1"},
+			want:   "production:user:1",
+		},
+		{
+			name:   "Versioned",
+			config: KeyBuilderConfig{Service: "order", Env: env.Production, Version: 2},
+			parts:  []string{"user", "1"},
+			want:   "order:production:v2:user:1",
+		},
+		{
+			name:   "Custom separator",
+			config: KeyBuilderConfig{Service: "order", Env: env.Staging, Separator: "."},
+			parts:  []string{"user", "1"},
+			want:   "order.staging.user.1",
+		},
+		{
+			name:   "Short key",
+			config: KeyBuilderConfig{Service: "order", Env: env.Production, MaxLength: 32},
+			parts:  []string{"user", "1"},
+			want:   "order:production:user:1",
+		},
+		{
+			name:   "Long key",
+			config: KeyBuilderConfig{Service: "order", Env: env.Production, MaxLength: 32},
+			parts:  []string{"search", strings.Repeat("q", 32)},
+			want:   "order:production:78f27e9ca8588b392e0c13993ae07763b25cc8baa7bb23b16b373cad8da243fb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewKeyBuilderWithConfig(tt.config).Key(tt.parts...))
+		})
+	}
+}
+
+func TestKeyBuilder_TaggedKey(t *testing.T) {
+	keys := NewKeyBuilderWithConfig(KeyBuilderConfig{Service: "order", Env: env.Production, MaxLength: 48})
+
+	cart := keys.TaggedKey("user:1", "cart")
+	assert.Equal(t, "order:production:{user:1}:cart", cart)
+
+	// The hashed key keeps the hash tag, so it stays in the hash slot of the other keys of the tag.
+	search := keys.TaggedKey("user:1", "search", strings.Repeat("q", 32))
+	assert.Equal(t, "order:production:{user:1}:f7770396ec4f3cb2ec9841851ccdca658c7e53535217ed7c02df018dd18fdc61", search)
+	assert.Equal(t, Slot(cart), Slot(search))
+}
+
+func TestKeyBuilder_Hash(t *testing.T) {
+	keys := NewKeyBuilderWithConfig(KeyBuilderConfig{Service: "order", Env: env.Production, MaxLength: 16})
+
+	// The key outside the namespace is hashed without the prefix.
+	assert.Equal(t,
+		"{user:1}:f921d91f42d7064de54a18b490d69235b57cc71730dfbd8a38da6831a1fec9b8",
+		keys.Hash("{user:1}:search:"+strings.Repeat("q", 32)),
+	)
+	assert.Equal(t, "order:production:", keys.Prefix())
+}
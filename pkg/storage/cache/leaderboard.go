@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// Rank is a leaderboard member with its score and position.
+type Rank struct {
+	Member string
+	Score  float64
+	// Position is the 1-based position of the member, the highest score comes first.
+	Position int64
+}
+
+// Leaderboard ranks the members of a redis sorted set from the highest score.
+// Time-windowed rankings can use one leaderboard per window,
+// e.g. by suffixing the key with the date and expiring the key after the window.
+type Leaderboard struct {
+	redis RedisItf
+	key   string
+}
+
+// NewLeaderboard returns a leaderboard stored in the sorted set key.
+func NewLeaderboard(redis RedisItf, key string) *Leaderboard {
+	return &Leaderboard{
+		redis: redis,
+		key:   key,
+	}
+}
+
+// Set sets the score of a member.
+func (l *Leaderboard) Set(ctx context.Context, member string, score float64) error {
+	const op errorx.Op = "cache/Leaderboard.Set"
+
+	if _, err := l.redis.ZAdd(ctx, l.key, ZMember{Member: member, Score: score}); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// Incr increments the score of a member and returns the new score.
+func (l *Leaderboard) Incr(ctx context.Context, member string, by float64) (float64, error) {
+	const op errorx.Op = "cache/Leaderboard.Incr"
+
+	res, err := l.redis.ZIncrBy(ctx, l.key, by, member)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// Remove removes the members from the leaderboard.
+func (l *Leaderboard) Remove(ctx context.Context, members ...string) error {
+	const op errorx.Op = "cache/Leaderboard.Remove"
+
+	if _, err := l.redis.ZRem(ctx, l.key, members...); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// Top returns the n members with the highest score.
+func (l *Leaderboard) Top(ctx context.Context, n int64) ([]Rank, error) {
+	const op errorx.Op = "cache/Leaderboard.Top"
+
+	if n <= 0 {
+		return []Rank{}, nil
+	}
+
+	res, err := l.ranks(ctx, 0, n-1)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// RankOf returns the rank of a member.
+// Missing member is reported as an error with errorx.CodeNotFound.
+func (l *Leaderboard) RankOf(ctx context.Context, member string) (Rank, error) {
+	const op errorx.Op = "cache/Leaderboard.RankOf"
+
+	idx, err := l.redis.ZRevRank(ctx, l.key, member)
+	if err != nil {
+		return Rank{}, errorx.E(err, op)
+	}
+
+	score, err := l.redis.ZScore(ctx, l.key, member)
+	if err != nil {
+		return Rank{}, errorx.E(err, op)
+	}
+
+	return Rank{
+		Member:   member,
+		Score:    score,
+		Position: idx + 1,
+	}, nil
+}
+
+// Around returns the members ranked around a member, up to radius members before and after it.
+// Missing member is reported as an error with errorx.CodeNotFound.
+func (l *Leaderboard) Around(ctx context.Context, member string, radius int64) ([]Rank, error) {
+	const op errorx.Op = "cache/Leaderboard.Around"
+
+	if radius < 0 {
+		return nil, errorx.E("radius must not be negative", op, errorx.CodeInvalid)
+	}
+
+	idx, err := l.redis.ZRevRank(ctx, l.key, member)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	start := idx - radius
+	if start < 0 {
+		start = 0
+	}
+
+	res, err := l.ranks(ctx, start, idx+radius)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// ranks returns the members between index start and stop, ordered from the highest score.
+func (l *Leaderboard) ranks(ctx context.Context, start, stop int64) ([]Rank, error) {
+	members, err := l.redis.ZRevRange(ctx, l.key, start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Rank, len(members))
+	for i, v := range members {
+		res[i] = Rank{
+			Member:   v.Member,
+			Score:    v.Score,
+			Position: start + int64(i) + 1,
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderboard_Top(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redis := NewMockRedisItf(ctrl)
+	redis.EXPECT().ZRevRange(gomock.Any(), "board", int64(0), int64(1)).
+		Return([]ZMember{{Member: "a", Score: 3}, {Member: "b", Score: 2}}, nil)
+
+	board := NewLeaderboard(redis, "board")
+
+	got, err := board.Top(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = board.Top(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Rank{
+		{Member: "a", Score: 3, Position: 1},
+		{Member: "b", Score: 2, Position: 2},
+	}, got)
+}
+
+func TestLeaderboard_RankOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(redis *MockRedisItf)
+		want     Rank
+		wantCode errorx.Code
+	}{
+		{
+			name: "Not found",
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().ZRevRank(gomock.Any(), "board", "a").
+					Return(int64(0), errorx.E("member not found", errorx.CodeNotFound))
+			},
+			want:     Rank{},
+			wantCode: errorx.CodeNotFound,
+		},
+		{
+			name: "Success",
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().ZRevRank(gomock.Any(), "board", "a").Return(int64(4), nil)
+				redis.EXPECT().ZScore(gomock.Any(), "board", "a").Return(float64(10), nil)
+			},
+			want:     Rank{Member: "a", Score: 10, Position: 5},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			redis := NewMockRedisItf(ctrl)
+			tt.mock(redis)
+
+			got, err := NewLeaderboard(redis, "board").RankOf(context.Background(), "a")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLeaderboard_Around(t *testing.T) {
+	tests := []struct {
+		name     string
+		radius   int64
+		mock     func(redis *MockRedisItf)
+		want     []Rank
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Negative radius",
+			radius:   -1,
+			mock:     func(redis *MockRedisItf) {},
+			want:     nil,
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:   "Near the top",
+			radius: 2,
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().ZRevRank(gomock.Any(), "board", "b").Return(int64(1), nil)
+				redis.EXPECT().ZRevRange(gomock.Any(), "board", int64(0), int64(3)).
+					Return([]ZMember{{Member: "a", Score: 4}, {Member: "b", Score: 3}, {Member: "c", Score: 2}}, nil)
+			},
+			want: []Rank{
+				{Member: "a", Score: 4, Position: 1},
+				{Member: "b", Score: 3, Position: 2},
+				{Member: "c", Score: 2, Position: 3},
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:   "In the middle",
+			radius: 1,
+			mock: func(redis *MockRedisItf) {
+				redis.EXPECT().ZRevRank(gomock.Any(), "board", "b").Return(int64(9), nil)
+				redis.EXPECT().ZRevRange(gomock.Any(), "board", int64(8), int64(10)).
+					Return([]ZMember{{Member: "a", Score: 4}, {Member: "b", Score: 3}, {Member: "c", Score: 2}}, nil)
+			},
+			want: []Rank{
+				{Member: "a", Score: 4, Position: 9},
+				{Member: "b", Score: 3, Position: 10},
+				{Member: "c", Score: 2, Position: 11},
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			redis := NewMockRedisItf(ctrl)
+			tt.mock(redis)
+
+			got, err := NewLeaderboard(redis, "board").Around(context.Background(), "b", tt.radius)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+)
+
+//go:generate mockgen -destination=backend_mock.go -package=lock -source=backend.go
+
+// Backend stores the locks and their fencing counters.
+// Every operation must be atomic.
+type Backend interface {
+	// Acquire sets the owner token of a free lock that will expire after the ttl has passed,
+	// and increments the fencing counter of the key.
+	// It returns the fencing token, or 0 if the lock is held.
+	Acquire(ctx context.Context, key, token string, ttl time.Duration) (int64, error)
+	// Release deletes the lock if it is still owned by the token.
+	// It returns false if the lock is held by other owner or expired.
+	Release(ctx context.Context, key, token string) (bool, error)
+	// Renew sets the ttl of the lock if it is still owned by the token.
+	// It returns false if the lock is held by other owner or expired.
+	Renew(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+}
+
+// RedisBackend is a backend using the lock scripts of the cache package.
+// It works with every redis client able to run scripts, including the cluster clients.
+type RedisBackend struct {
+	scripter cache.ScripterItf
+}
+
+// NewRedisBackend returns a backend using the lock scripts of the cache package.
+func NewRedisBackend(scripter cache.ScripterItf) *RedisBackend {
+	return &RedisBackend{
+		scripter: scripter,
+	}
+}
+
+// Acquire sets the owner token of a free lock that will expire after the ttl has passed,
+// and increments the fencing counter of the key.
+// It returns the fencing token, or 0 if the lock is held.
+func (b *RedisBackend) Acquire(ctx context.Context, key, token string, ttl time.Duration) (int64, error) {
+	const op errorx.Op = "lock/RedisBackend.Acquire"
+
+	res, err := b.scripter.RunScript(ctx, cache.ScriptLockAcquire, keys(key), token, ttl.Milliseconds())
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	fence, ok := res.(int64)
+	if !ok {
+		return 0, errorx.E("unexpected reply type", op, errorx.CodeGateway)
+	}
+
+	return fence, nil
+}
+
+// Release deletes the lock if it is still owned by the token.
+// It returns false if the lock is held by other owner or expired.
+func (b *RedisBackend) Release(ctx context.Context, key, token string) (bool, error) {
+	const op errorx.Op = "lock/RedisBackend.Release"
+
+	res, err := b.scripter.RunScript(ctx, cache.ScriptLockRelease, keys(key)[:1], token)
+	if err != nil {
+		return false, errorx.E(err, op)
+	}
+
+	return res == int64(1), nil
+}
+
+// Renew sets the ttl of the lock if it is still owned by the token.
+// It returns false if the lock is held by other owner or expired.
+func (b *RedisBackend) Renew(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	const op errorx.Op = "lock/RedisBackend.Renew"
+
+	res, err := b.scripter.RunScript(ctx, cache.ScriptLockRenew, keys(key)[:1], token, ttl.Milliseconds())
+	if err != nil {
+		return false, errorx.E(err, op)
+	}
+
+	return res == int64(1), nil
+}
+
+// keys returns the lock key and the fencing counter key.
+// Both keys share the hash tag of the key, or the key itself is used as the hash tag,
+// so they are stored in the same cluster slot.
+func keys(key string) []string {
+	if cache.HashTag(key) == key {
+		key = "{" + key + "}"
+	}
+
+	return []string{key + ":lock", key + ":fence"}
+}
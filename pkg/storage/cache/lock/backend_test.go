@@ -0,0 +1,144 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/peractio/gdk/pkg/storage/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisBackend_Acquire(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		mock     func(scripter *cache.MockScripterItf)
+		want     int64
+		wantCode errorx.Code
+	}{
+		{
+			name: "Script error",
+			key:  "key",
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().RunScript(gomock.Any(), cache.ScriptLockAcquire, gomock.Any(), gomock.Any()).
+					Return(nil, errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:     0,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Unexpected reply",
+			key:  "key",
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().RunScript(gomock.Any(), cache.ScriptLockAcquire, gomock.Any(), gomock.Any()).
+					Return("OK", nil)
+			},
+			want:     0,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Key without hash tag",
+			key:  "key",
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().
+					RunScript(gomock.Any(), cache.ScriptLockAcquire, []string{"{key}:lock", "{key}:fence"}, "token", int64(1000)).
+					Return(int64(3), nil)
+			},
+			want:     3,
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Key with hash tag",
+			key:  "{user:1}:profile",
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().
+					RunScript(gomock.Any(), cache.ScriptLockAcquire, []string{"{user:1}:profile:lock", "{user:1}:profile:fence"}, "token", int64(1000)).
+					Return(int64(3), nil)
+			},
+			want:     3,
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scripter := cache.NewMockScripterItf(ctrl)
+			tt.mock(scripter)
+
+			got, err := NewRedisBackend(scripter).Acquire(context.Background(), tt.key, "token", time.Second)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedisBackend_Release(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scripter := cache.NewMockScripterItf(ctrl)
+	scripter.EXPECT().RunScript(gomock.Any(), cache.ScriptLockRelease, []string{"{key}:lock"}, "token").Return(int64(1), nil)
+	scripter.EXPECT().RunScript(gomock.Any(), cache.ScriptLockRelease, []string{"{key}:lock"}, "token").Return(int64(0), nil)
+
+	backend := NewRedisBackend(scripter)
+
+	ok, err := backend.Release(context.Background(), "key", "token")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = backend.Release(context.Background(), "key", "token")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisBackend_Renew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scripter := cache.NewMockScripterItf(ctrl)
+	scripter.EXPECT().
+		RunScript(gomock.Any(), cache.ScriptLockRenew, []string{"{key}:lock"}, "token", int64(1000)).
+		Return(int64(1), nil)
+
+	ok, err := NewRedisBackend(scripter).Renew(context.Background(), "key", "token", time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestRedisBackend_MemoryRedis(t *testing.T) {
+	clock := cachetest.NewFakeClock(time.Now())
+	backend := NewRedisBackend(cache.NewMemoryRedisWithConfig(cache.MemoryRedisConfig{Now: clock.Now}))
+	ctx := context.Background()
+
+	fence, err := backend.Acquire(ctx, "key", "first", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), fence)
+
+	fence, err = backend.Acquire(ctx, "key", "second", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), fence)
+
+	ok, err := backend.Renew(ctx, "key", "first", 2*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// The lock expires, so the next owner acquires it with a greater fencing token.
+	clock.Advance(2 * time.Second)
+	fence, err = backend.Acquire(ctx, "key", "second", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), fence)
+
+	ok, err = backend.Release(ctx, "key", "first")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = backend.Release(ctx, "key", "second")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
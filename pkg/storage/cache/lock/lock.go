@@ -0,0 +1,230 @@
+// Package lock provides a distributed lock with owner token, fencing token and renewal.
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/tags"
+	"github.com/segmentio/ksuid"
+)
+
+// Config configuration.
+type Config struct {
+	// RetryCount is the maximum number of retries when the lock is held by other owner.
+	// Zero value means Acquire fails immediately, a negative value retries until the context is done.
+	RetryCount int
+	// RetryDelay is the delay between the retries.
+	RetryDelay time.Duration
+	// AutoRenew renews the lock periodically until it is released or lost.
+	AutoRenew bool
+	// RenewInterval is the interval between the renewals.
+	// Zero value means a third of the lock ttl.
+	RenewInterval time.Duration
+}
+
+// DefaultConfig is the default configuration of the locker.
+var DefaultConfig = Config{
+	RetryCount:    0,
+	RetryDelay:    100 * time.Millisecond,
+	AutoRenew:     false,
+	RenewInterval: 0,
+}
+
+// Locker acquires the locks stored in the backend.
+type Locker struct {
+	backend Backend
+	config  Config
+}
+
+// NewLocker returns a locker with the default configuration.
+func NewLocker(backend Backend) *Locker {
+	return NewLockerWithConfig(backend, DefaultConfig)
+}
+
+// NewLockerWithConfig returns a locker.
+func NewLockerWithConfig(backend Backend, config Config) *Locker {
+	return &Locker{
+		backend: backend,
+		config:  config,
+	}
+}
+
+// Acquire acquires the lock of a key that will expire after the ttl has passed,
+// unless it is renewed.
+// The lock held by other owner is reported as an error with errorx.CodeConflict,
+// after all the retries have been made.
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	const op errorx.Op = "lock/Locker.Acquire"
+
+	if ttl <= 0 {
+		return nil, errorx.E("ttl must be greater than zero", op, errorx.CodeInvalid)
+	}
+	if l.config.AutoRenew {
+		// The lock would expire between the renewals.
+		if interval := l.renewInterval(ttl); interval <= 0 || interval >= ttl {
+			return nil, errorx.E("renew interval must be greater than zero and shorter than the ttl", op,
+				errorx.CodeInvalid, errorx.Fields{tags.Detail: interval.String()})
+		}
+	}
+
+	token := ksuid.New().String()
+	for retry := 0; ; retry++ {
+		fence, err := l.backend.Acquire(ctx, key, token, ttl)
+		if err != nil {
+			return nil, errorx.E(err, op)
+		}
+		if fence > 0 {
+			return l.newLock(ctx, key, token, fence, ttl), nil
+		}
+
+		if l.config.RetryCount >= 0 && retry >= l.config.RetryCount {
+			return nil, errorx.E("lock is held by other owner", op, errorx.CodeConflict)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errorx.E(ctx.Err(), op, errorx.CodeConflict)
+		case <-time.After(l.config.RetryDelay):
+		}
+	}
+}
+
+// newLock returns an acquired lock, and starts the renewal when configured.
+func (l *Locker) newLock(ctx context.Context, key, token string, fence int64, ttl time.Duration) *Lock {
+	res := &Lock{
+		backend: l.backend,
+		key:     key,
+		token:   token,
+		fence:   fence,
+		ttl:     ttl,
+		lost:    make(chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if !l.config.AutoRenew {
+		close(res.done)
+		return res
+	}
+
+	// Detach from the request context, since the lock may outlive the request.
+	renewCtx := logx.SetRequestID(context.Background(), logx.GetRequestID(ctx))
+	go res.autoRenew(renewCtx, l.renewInterval(ttl))
+
+	return res
+}
+
+// renewInterval returns the interval between the renewals of a lock.
+func (l *Locker) renewInterval(ttl time.Duration) time.Duration {
+	if l.config.RenewInterval > 0 {
+		return l.config.RenewInterval
+	}
+
+	return ttl / 3
+}
+
+// Lock is an acquired lock.
+type Lock struct {
+	backend Backend
+	key     string
+	token   string
+	fence   int64
+	ttl     time.Duration
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// Key returns the key of the lock.
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token returns the random owner token of the lock.
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// Fence returns the fencing token of the lock.
+// The fencing token increases every time the lock of the key is acquired,
+// so a storage can reject the writes of an owner that has lost the lock,
+// by rejecting the writes with a lower fencing token than the last seen.
+func (l *Lock) Fence() int64 {
+	return l.fence
+}
+
+// Lost returns a channel that is closed when the lock is found to be held by other owner or expired.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Renew resets the ttl of the lock.
+// The lock held by other owner or expired is reported as an error with errorx.CodeConflict.
+func (l *Lock) Renew(ctx context.Context) error {
+	const op errorx.Op = "lock/Lock.Renew"
+
+	ok, err := l.backend.Renew(ctx, l.key, l.token, l.ttl)
+	if err != nil {
+		return errorx.E(err, op)
+	}
+	if !ok {
+		l.lostOnce.Do(func() { close(l.lost) })
+		return errorx.E("lock is held by other owner or expired", op, errorx.CodeConflict)
+	}
+
+	return nil
+}
+
+// Release stops the renewal and releases the lock.
+// The lock held by other owner or expired is reported as an error with errorx.CodeConflict.
+func (l *Lock) Release(ctx context.Context) error {
+	const op errorx.Op = "lock/Lock.Release"
+
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	ok, err := l.backend.Release(ctx, l.key, l.token)
+	if err != nil {
+		return errorx.E(err, op)
+	}
+	if !ok {
+		l.lostOnce.Do(func() { close(l.lost) })
+		return errorx.E("lock is held by other owner or expired", op, errorx.CodeConflict)
+	}
+
+	return nil
+}
+
+// autoRenew renews the lock periodically until it is released or lost.
+func (l *Lock) autoRenew(ctx context.Context, interval time.Duration) {
+	const op errorx.Op = "lock/Lock.autoRenew"
+
+	defer close(l.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			err := l.Renew(ctx)
+			if errorx.Is(errorx.CodeConflict, err) {
+				logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: l.key}), string(op)+" lock lost")
+				return
+			}
+			// A failed renewal is retried on the next tick, until the lock expires.
+			if err != nil {
+				logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: l.key}), string(op)+" failed")
+			}
+		}
+	}
+}
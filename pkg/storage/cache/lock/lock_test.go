@@ -0,0 +1,192 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocker_Acquire(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		ttl       time.Duration
+		mock      func(backend *MockBackend)
+		wantFence int64
+		wantCode  errorx.Code
+	}{
+		{
+			name:      "Invalid ttl",
+			config:    DefaultConfig,
+			ttl:       0,
+			mock:      func(backend *MockBackend) {},
+			wantFence: 0,
+			wantCode:  errorx.CodeInvalid,
+		},
+		{
+			name:      "Ttl too short to be renewed",
+			config:    Config{AutoRenew: true},
+			ttl:       2 * time.Nanosecond,
+			mock:      func(backend *MockBackend) {},
+			wantFence: 0,
+			wantCode:  errorx.CodeInvalid,
+		},
+		{
+			name:      "Renew interval not shorter than the ttl",
+			config:    Config{AutoRenew: true, RenewInterval: time.Second},
+			ttl:       time.Second,
+			mock:      func(backend *MockBackend) {},
+			wantFence: 0,
+			wantCode:  errorx.CodeInvalid,
+		},
+		{
+			name:   "Backend error",
+			config: DefaultConfig,
+			ttl:    time.Second,
+			mock: func(backend *MockBackend) {
+				backend.EXPECT().Acquire(gomock.Any(), "key", gomock.Any(), time.Second).
+					Return(int64(0), errorx.E("redis error", errorx.CodeGateway))
+			},
+			wantFence: 0,
+			wantCode:  errorx.CodeGateway,
+		},
+		{
+			name:   "Held without retry",
+			config: DefaultConfig,
+			ttl:    time.Second,
+			mock: func(backend *MockBackend) {
+				backend.EXPECT().Acquire(gomock.Any(), "key", gomock.Any(), time.Second).Return(int64(0), nil)
+			},
+			wantFence: 0,
+			wantCode:  errorx.CodeConflict,
+		},
+		{
+			name:   "Held after retries",
+			config: Config{RetryCount: 2, RetryDelay: time.Millisecond},
+			ttl:    time.Second,
+			mock: func(backend *MockBackend) {
+				backend.EXPECT().Acquire(gomock.Any(), "key", gomock.Any(), time.Second).Return(int64(0), nil).Times(3)
+			},
+			wantFence: 0,
+			wantCode:  errorx.CodeConflict,
+		},
+		{
+			name:   "Acquired after retry",
+			config: Config{RetryCount: 2, RetryDelay: time.Millisecond},
+			ttl:    time.Second,
+			mock: func(backend *MockBackend) {
+				backend.EXPECT().Acquire(gomock.Any(), "key", gomock.Any(), time.Second).Return(int64(0), nil)
+				backend.EXPECT().Acquire(gomock.Any(), "key", gomock.Any(), time.Second).Return(int64(7), nil)
+			},
+			wantFence: 7,
+			wantCode:  errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			backend := NewMockBackend(ctrl)
+			tt.mock(backend)
+
+			got, err := NewLockerWithConfig(backend, tt.config).Acquire(context.Background(), "key", tt.ttl)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			if tt.wantFence > 0 {
+				assert.Equal(t, tt.wantFence, got.Fence())
+				assert.Equal(t, "key", got.Key())
+				assert.NotEmpty(t, got.Token())
+			}
+		})
+	}
+}
+
+func TestLocker_Acquire_Wait(t *testing.T) {
+	backend := NewMemoryBackend()
+	locker := NewLockerWithConfig(backend, Config{RetryCount: -1, RetryDelay: 5 * time.Millisecond})
+
+	first, err := locker.Acquire(context.Background(), "key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), first.Fence())
+
+	// Wait until the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = locker.Acquire(ctx, "key", time.Minute)
+	assert.True(t, errorx.Is(errorx.CodeConflict, err))
+
+	// Wait until the lock is released.
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = first.Release(context.Background())
+	}()
+	second, err := locker.Acquire(context.Background(), "key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), second.Fence())
+	assert.NotEqual(t, first.Token(), second.Token())
+}
+
+func TestLock_Release(t *testing.T) {
+	backend := NewMemoryBackend()
+	locker := NewLocker(backend)
+
+	lock, err := locker.Acquire(context.Background(), "key", time.Minute)
+	assert.NoError(t, err)
+
+	// Other owner can't release the lock.
+	ok, err := backend.Release(context.Background(), "key", "other")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, lock.Release(context.Background()))
+
+	// The lock is already released.
+	err = lock.Release(context.Background())
+	assert.True(t, errorx.Is(errorx.CodeConflict, err))
+	<-lock.Lost()
+}
+
+func TestLock_AutoRenew(t *testing.T) {
+	backend := NewMemoryBackend()
+	locker := NewLockerWithConfig(backend, Config{AutoRenew: true, RenewInterval: 10 * time.Millisecond})
+
+	lock, err := locker.Acquire(context.Background(), "key", 30*time.Millisecond)
+	assert.NoError(t, err)
+
+	// The lock outlives its ttl while it is renewed.
+	time.Sleep(100 * time.Millisecond)
+	fence, err := backend.Acquire(context.Background(), "key", "other", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), fence)
+
+	assert.NoError(t, lock.Release(context.Background()))
+}
+
+func TestLock_AutoRenew_Lost(t *testing.T) {
+	backend := NewMemoryBackend()
+	locker := NewLockerWithConfig(backend, Config{AutoRenew: true, RenewInterval: 10 * time.Millisecond})
+
+	lock, err := locker.Acquire(context.Background(), "key", time.Minute)
+	assert.NoError(t, err)
+
+	// Other process steals the lock.
+	ok, err := backend.Release(context.Background(), "key", lock.Token())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	fence, err := backend.Acquire(context.Background(), "key", "other", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), fence)
+
+	select {
+	case <-lock.Lost():
+	case <-time.After(time.Second):
+		assert.Fail(t, "lock is not lost")
+	}
+
+	err = lock.Release(context.Background())
+	assert.True(t, errorx.Is(errorx.CodeConflict, err))
+}
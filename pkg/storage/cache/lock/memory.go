@@ -0,0 +1,97 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryLock is a lock held in memory.
+type memoryLock struct {
+	token  string
+	expiry time.Time
+}
+
+// MemoryBackend is an in-process backend, useful for tests and single instance services.
+type MemoryBackend struct {
+	mu     sync.Mutex
+	locks  map[string]memoryLock
+	fences map[string]int64
+	now    func() time.Time
+}
+
+// NewMemoryBackend returns an in-process backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		locks:  make(map[string]memoryLock),
+		fences: make(map[string]int64),
+		now:    time.Now,
+	}
+}
+
+// Acquire sets the owner token of a free lock that will expire after the ttl has passed,
+// and increments the fencing counter of the key.
+// It returns the fencing token, or 0 if the lock is held.
+func (b *MemoryBackend) Acquire(_ context.Context, key, token string, ttl time.Duration) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.get(key); ok {
+		return 0, nil
+	}
+
+	b.locks[key] = memoryLock{
+		token:  token,
+		expiry: b.now().Add(ttl),
+	}
+	b.fences[key]++
+
+	return b.fences[key], nil
+}
+
+// Release deletes the lock if it is still owned by the token.
+// It returns false if the lock is held by other owner or expired.
+func (b *MemoryBackend) Release(_ context.Context, key, token string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	l, ok := b.get(key)
+	if !ok || l.token != token {
+		return false, nil
+	}
+
+	delete(b.locks, key)
+	return true, nil
+}
+
+// Renew sets the ttl of the lock if it is still owned by the token.
+// It returns false if the lock is held by other owner or expired.
+func (b *MemoryBackend) Renew(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	l, ok := b.get(key)
+	if !ok || l.token != token {
+		return false, nil
+	}
+
+	l.expiry = b.now().Add(ttl)
+	b.locks[key] = l
+	return true, nil
+}
+
+// get returns the lock of a key, expired lock is deleted.
+// The caller must hold the mutex.
+func (b *MemoryBackend) get(key string) (memoryLock, bool) {
+	l, ok := b.locks[key]
+	if !ok {
+		return l, false
+	}
+
+	if !b.now().Before(l.expiry) {
+		delete(b.locks, key)
+		return l, false
+	}
+
+	return l, true
+}
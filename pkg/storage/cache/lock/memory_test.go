@@ -0,0 +1,47 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	now := time.Now()
+	backend := NewMemoryBackend()
+	backend.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	fence, err := backend.Acquire(ctx, "key", "a", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), fence)
+
+	fence, err = backend.Acquire(ctx, "key", "b", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), fence)
+
+	ok, err := backend.Renew(ctx, "key", "b", time.Second)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = backend.Renew(ctx, "key", "a", 2*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// The lock expires after the renewed ttl.
+	now = now.Add(2 * time.Second)
+	ok, err = backend.Release(ctx, "key", "a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	fence, err = backend.Acquire(ctx, "key", "b", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), fence)
+
+	ok, err = backend.Release(ctx, "key", "b")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// memorySubscriber is a subscription session of the in-memory redis.
+// The published messages are queued, so Publish never waits for the handlers.
+type memorySubscriber struct {
+	channels map[string]struct{}
+	patterns []string
+
+	mu     sync.Mutex
+	queue  []*Message
+	notify chan struct{}
+}
+
+// push queues the message, and wakes up the session.
+func (s *memorySubscriber) push(msg *Message) {
+	s.mu.Lock()
+	s.queue = append(s.queue, msg)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the queued messages.
+func (s *memorySubscriber) pop() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := s.queue
+	s.queue = nil
+	return res
+}
+
+// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+// A subscriber matching the topic by its channel and its patterns receives the message more than once.
+func (m *MemoryRedis) Publish(_ context.Context, topic, message string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := 0
+	for sub := range m.subscribers {
+		if _, ok := sub.channels[topic]; ok {
+			sub.push(&Message{Channel: topic, Data: []byte(message)})
+			res++
+		}
+		for _, pattern := range sub.patterns {
+			if matchPattern(pattern, topic) {
+				sub.push(&Message{Channel: topic, Pattern: pattern, Data: []byte(message)})
+				res++
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Subscribe subscribes to the channels and dispatches the received messages to the handler.
+// It blocks until the context is done.
+// The handler error and panic are logged, and don't stop the subscription.
+func (m *MemoryRedis) Subscribe(ctx context.Context, handler MessageHandler, channels ...string) error {
+	const op errorx.Op = "cache/MemoryRedis.Subscribe"
+
+	if len(channels) == 0 {
+		return errorx.E("missing channels", op, errorx.CodeInvalid)
+	}
+
+	sub := &subscription{
+		op:      op,
+		names:   channels,
+		handler: handler,
+		session: m.subscribeSession(channels, nil),
+	}
+
+	return sub.run(ctx)
+}
+
+// PSubscribe subscribes to the channels matching the patterns, e.g. "news.*",
+// and dispatches the received messages to the handler.
+// It blocks until the context is done.
+// The handler error and panic are logged, and don't stop the subscription.
+func (m *MemoryRedis) PSubscribe(ctx context.Context, handler MessageHandler, patterns ...string) error {
+	const op errorx.Op = "cache/MemoryRedis.PSubscribe"
+
+	if len(patterns) == 0 {
+		return errorx.E("missing patterns", op, errorx.CodeInvalid)
+	}
+
+	sub := &subscription{
+		op:      op,
+		names:   patterns,
+		handler: handler,
+		session: m.subscribeSession(nil, patterns),
+	}
+
+	return sub.run(ctx)
+}
+
+// subscribeSession returns a session receiving the messages published to the channels and patterns,
+// until the context is done.
+func (m *MemoryRedis) subscribeSession(channels, patterns []string) subscribeSession {
+	return func(ctx context.Context, ready func(), dispatch func(ctx context.Context, msg *Message)) error {
+		sub := &memorySubscriber{
+			channels: make(map[string]struct{}, len(channels)),
+			patterns: patterns,
+			notify:   make(chan struct{}, 1),
+		}
+		for _, channel := range channels {
+			sub.channels[channel] = struct{}{}
+		}
+
+		m.mu.Lock()
+		m.subscribers[sub] = struct{}{}
+		m.mu.Unlock()
+		defer func() {
+			m.mu.Lock()
+			delete(m.subscribers, sub)
+			m.mu.Unlock()
+		}()
+
+		ready()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-sub.notify:
+				for _, msg := range sub.pop() {
+					dispatch(ctx, msg)
+				}
+			}
+		}
+	}
+}
+
+// matchPattern reports whether the string matches the glob-style pattern of redis,
+// supporting "*", "?", "[abc]", "[^abc]", "[a-z]" and the escape "\".
+func matchPattern(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchPattern(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			var ok bool
+			if pattern, ok = matchClass(pattern[1:], s[0]); !ok {
+				return false
+			}
+			s = s[1:]
+
+		default:
+			if pattern[0] == '\\' && len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		}
+	}
+
+	return len(s) == 0
+}
+
+// matchClass matches the character against the class following "[",
+// and returns the pattern after the class.
+// An unclosed class ends with the pattern.
+func matchClass(pattern string, c byte) (string, bool) {
+	negate := len(pattern) > 0 && pattern[0] == '^'
+	if negate {
+		pattern = pattern[1:]
+	}
+
+	match := false
+	for len(pattern) > 0 && pattern[0] != ']' {
+		switch {
+		case pattern[0] == '\\' && len(pattern) > 1:
+			match = match || pattern[1] == c
+			pattern = pattern[2:]
+		case len(pattern) > 2 && pattern[1] == '-' && pattern[2] != ']':
+			lo, hi := pattern[0], pattern[2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			match = match || (lo <= c && c <= hi)
+			pattern = pattern[3:]
+		default:
+			match = match || pattern[0] == c
+			pattern = pattern[1:]
+		}
+	}
+	if len(pattern) > 0 {
+		pattern = pattern[1:]
+	}
+
+	return pattern, match != negate
+}
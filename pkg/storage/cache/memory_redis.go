@@ -0,0 +1,1287 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// MemoryRedisConfig configuration.
+type MemoryRedisConfig struct {
+	// Now returns the current time, which drives the expiry of the keys.
+	// Tests replace it with a fake clock, e.g. cachetest.FakeClock.Now.
+	Now func() time.Time
+}
+
+// DefaultMemoryRedisConfig is the default configuration of the in-memory redis.
+var DefaultMemoryRedisConfig = MemoryRedisConfig{
+	Now: time.Now,
+}
+
+// Errors replied by the in-memory redis, with the messages of redis.
+var (
+	errMemoryWrongType     = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	errMemoryNotInteger    = errors.New("ERR value is not an integer or out of range")
+	errMemoryNotFloat      = errors.New("ERR value is not a valid float")
+	errMemoryInvalidExpire = errors.New("ERR invalid expire time in set")
+	errMemoryMinMax        = errors.New("ERR min or max is not a float")
+)
+
+// memoryKind is the type of a value.
+type memoryKind int
+
+// Types of the values.
+const (
+	memoryString memoryKind = iota
+	memoryHash
+	memoryList
+	memorySet
+	memoryZSet
+)
+
+// memoryValue is a value of the in-memory redis, only the field of its kind is used.
+type memoryValue struct {
+	kind memoryKind
+	str  string
+	hash map[string]string
+	list []string
+	set  map[string]struct{}
+	zset map[string]float64
+	// expiry is zero when the key doesn't expire.
+	expiry time.Time
+}
+
+// empty reports whether a collection has no more members, redis deletes the empty collections.
+func (v *memoryValue) empty() bool {
+	switch v.kind {
+	case memoryHash:
+		return len(v.hash) == 0
+	case memoryList:
+		return len(v.list) == 0
+	case memorySet:
+		return len(v.set) == 0
+	case memoryZSet:
+		return len(v.zset) == 0
+	default:
+		return false
+	}
+}
+
+// memoryScript is the Go implementation of a script shipped with the package, with its number of arguments.
+type memoryScript struct {
+	args int
+	run  func(m *MemoryRedis, keys []string, args []string) (interface{}, error)
+}
+
+// memoryScripts are the scripts supported by the in-memory redis, by their hash.
+var memoryScripts = map[string]memoryScript{
+	ScriptIncrByEx.Hash():             {args: 2, run: memoryScriptIncrByEx},
+	ScriptInvalidateTag.Hash():        {args: 0, run: memoryScriptInvalidateTag},
+	ScriptTagKey.Hash():               {args: 2, run: memoryScriptTagKey},
+	ScriptLockAcquire.Hash():          {args: 2, run: memoryScriptLockAcquire},
+	ScriptLockRelease.Hash():          {args: 1, run: memoryScriptLockRelease},
+	ScriptLockRenew.Hash():            {args: 2, run: memoryScriptLockRenew},
+	ScriptRateLimitFixedWindow.Hash(): {args: 2, run: memoryScriptRateLimitFixedWindow},
+	ScriptRateLimitSlidingLog.Hash():  {args: 4, run: memoryScriptRateLimitSlidingLog},
+	ScriptRateLimitTokenBucket.Hash(): {args: 4, run: memoryScriptRateLimitTokenBucket},
+}
+
+// MemoryRedis is an in-process redis implementing RedisItf, ScripterItf, ScannerItf and PubSubItf,
+// so the code using redis can be tested without a redis server.
+// It supports strings, hashes, lists, sets, sorted sets, key expiry, scans and pub/sub, with the semantics of redis.
+// The scripts shipped with the package are run by their Go implementation, and the other scripts are rejected,
+// since it can't run Lua.
+// The replies of the scripts have the types of redigo, e.g. int64, []byte and []interface{}.
+//
+// Example:
+//
+//	clock := cachetest.NewFakeClock(time.Now())
+//	redis := cache.NewMemoryRedisWithConfig(cache.MemoryRedisConfig{Now: clock.Now})
+//	_ = redis.SetEX(ctx, "key", 60, "value")
+//	clock.Advance(time.Minute)
+//	_, err := redis.Get(ctx, "key") // errorx.CodeNotFound
+type MemoryRedis struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	data        map[string]*memoryValue
+	subscribers map[*memorySubscriber]struct{}
+}
+
+// NewMemoryRedis returns an empty in-memory redis with the default configuration.
+func NewMemoryRedis() *MemoryRedis {
+	return NewMemoryRedisWithConfig(DefaultMemoryRedisConfig)
+}
+
+// NewMemoryRedisWithConfig returns an empty in-memory redis with custom configuration.
+func NewMemoryRedisWithConfig(config MemoryRedisConfig) *MemoryRedis {
+	// Defaults
+	if config.Now == nil {
+		config.Now = DefaultMemoryRedisConfig.Now
+	}
+
+	return &MemoryRedis{
+		now:         config.Now,
+		data:        make(map[string]*memoryValue),
+		subscribers: make(map[*memorySubscriber]struct{}),
+	}
+}
+
+// Get gets the value from redis in []byte form.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (m *MemoryRedis) Get(_ context.Context, key string) ([]byte, error) {
+	const op errorx.Op = "cache/MemoryRedis.Get"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryString)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+
+	return []byte(v.str), nil
+}
+
+// SimpleSet sets value to key in redis without any additional options.
+// Key doesn't have a TTL.
+func (m *MemoryRedis) SimpleSet(_ context.Context, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.set(key, value, time.Time{})
+	return nil
+}
+
+// SetEX sets the value to a key with timeout in seconds.
+func (m *MemoryRedis) SetEX(_ context.Context, key string, seconds int64, value string) error {
+	const op errorx.Op = "cache/MemoryRedis.SetEX"
+
+	if seconds <= 0 {
+		return errorx.E(errMemoryInvalidExpire, op, errorx.CodeGateway)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.set(key, value, m.now().Add(time.Duration(seconds)*time.Second))
+	return nil
+}
+
+// SetNX sets a value to a key with specified timeouts.
+// SetNX returns false if the key exists.
+func (m *MemoryRedis) SetNX(_ context.Context, key string, seconds int64, value string) (bool, error) {
+	const op errorx.Op = "cache/MemoryRedis.SetNX"
+
+	if seconds <= 0 {
+		return false, errorx.E(errMemoryInvalidExpire, op, errorx.CodeGateway)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setNX(key, value, time.Duration(seconds)*time.Second), nil
+}
+
+// Exists checks whether the key exists in redis.
+func (m *MemoryRedis) Exists(_ context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.get(key) != nil, nil
+}
+
+// Expire sets the TTL of a key to specified value in seconds.
+// Non-positive ttl deletes the key.
+func (m *MemoryRedis) Expire(_ context.Context, key string, seconds int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.expire(key, m.now().Add(time.Duration(seconds)*time.Second)), nil
+}
+
+// ExpireAt sets the TTL of a key to a certain timestamp.
+// Past timestamp deletes the key.
+func (m *MemoryRedis) ExpireAt(_ context.Context, key string, timestamp int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.expire(key, time.Unix(timestamp, 0)), nil
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+// It returns -2 when the key doesn't exist, and -1 when the key doesn't expire.
+func (m *MemoryRedis) TTL(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ttl := m.pttl(key)
+	if ttl < 0 {
+		return ttl, nil
+	}
+
+	return (ttl + 500) / 1000, nil
+}
+
+// Del deletes the keys and returns the number of deleted keys.
+func (m *MemoryRedis) Del(_ context.Context, key ...interface{}) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var res int64
+	for _, v := range key {
+		if m.del(memoryArg(v)) {
+			res++
+		}
+	}
+
+	return res, nil
+}
+
+// Incr increments the integer value of a key by 1.
+func (m *MemoryRedis) Incr(ctx context.Context, key string) (int64, error) {
+	return m.IncrBy(ctx, key, 1)
+}
+
+// Decr decrements the integer value of a key by 1.
+func (m *MemoryRedis) Decr(ctx context.Context, key string) (int64, error) {
+	return m.IncrBy(ctx, key, -1)
+}
+
+// IncrBy increments the integer value of a key by the given amount.
+func (m *MemoryRedis) IncrBy(_ context.Context, key string, by int64) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.IncrBy"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.incrBy(key, by)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// IncrByEx increments redis key by adding expired.
+func (m *MemoryRedis) IncrByEx(ctx context.Context, key string, by, expires int64) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.IncrByEx"
+
+	res, err := m.RunScript(ctx, ScriptIncrByEx, []string{key}, by, expires)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	data, err := replyInt64(res)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// HGet gets the value of a hash field.
+// Missing key or field is reported as an error with errorx.CodeNotFound.
+func (m *MemoryRedis) HGet(_ context.Context, key, field string) ([]byte, error) {
+	const op errorx.Op = "cache/MemoryRedis.HGet"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryHash)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+
+	res, ok := v.hash[field]
+	if !ok {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+
+	return []byte(res), nil
+}
+
+// HMGet gets a value of multiple fields from hash key.
+// Missing field is returned as a nil value.
+func (m *MemoryRedis) HMGet(_ context.Context, key string, fields ...string) ([][]byte, error) {
+	const op errorx.Op = "cache/MemoryRedis.HMGet"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryHash)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res := make([][]byte, len(fields))
+	for i, field := range fields {
+		if v == nil {
+			continue
+		}
+		if value, ok := v.hash[field]; ok {
+			res[i] = []byte(value)
+		}
+	}
+
+	return res, nil
+}
+
+// HGetAll gets all the fields and values in a hash.
+func (m *MemoryRedis) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	const op errorx.Op = "cache/MemoryRedis.HGetAll"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryHash)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res := make(map[string]string)
+	if v != nil {
+		for field, value := range v.hash {
+			res[field] = value
+		}
+	}
+
+	return res, nil
+}
+
+// HKeys gets all the fields in a hash, in lexicographical order.
+func (m *MemoryRedis) HKeys(_ context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/MemoryRedis.HKeys"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryHash)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res := make([]string, 0)
+	if v != nil {
+		for field := range v.hash {
+			res = append(res, field)
+		}
+	}
+	sort.Strings(res)
+
+	return res, nil
+}
+
+// HExists determines if a hash field exists.
+func (m *MemoryRedis) HExists(_ context.Context, key, field string) (bool, error) {
+	const op errorx.Op = "cache/MemoryRedis.HExists"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryHash)
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return false, nil
+	}
+
+	_, ok := v.hash[field]
+	return ok, nil
+}
+
+// HSet sets the string value of a hash field.
+func (m *MemoryRedis) HSet(_ context.Context, key, field, value string) (bool, error) {
+	const op errorx.Op = "cache/MemoryRedis.HSet"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.create(key, memoryHash)
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	v.hash[field] = value
+	return true, nil
+}
+
+// HDel deletes a hash field.
+func (m *MemoryRedis) HDel(_ context.Context, key string, fields ...string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.HDel"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryHash)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return 0, nil
+	}
+
+	var res int64
+	for _, field := range fields {
+		if _, ok := v.hash[field]; ok {
+			delete(v.hash, field)
+			res++
+		}
+	}
+	m.cleanup(key, v)
+
+	return res, nil
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (m *MemoryRedis) LPush(_ context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.LPush"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.create(key, memoryList)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	list := make([]string, 0, len(values)+len(v.list))
+	for i := len(values) - 1; i >= 0; i-- {
+		list = append(list, values[i])
+	}
+	v.list = append(list, v.list...)
+
+	return int64(len(v.list)), nil
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (m *MemoryRedis) RPush(_ context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.RPush"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.create(key, memoryList)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	v.list = append(v.list, values...)
+	return int64(len(v.list)), nil
+}
+
+// LRange gets the elements of a list between index start and stop, negative index counts from the end.
+func (m *MemoryRedis) LRange(_ context.Context, key string, start, stop int64) ([][]byte, error) {
+	const op errorx.Op = "cache/MemoryRedis.LRange"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryList)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res := make([][]byte, 0)
+	if v == nil {
+		return res, nil
+	}
+
+	from, to, ok := memoryRange(start, stop, len(v.list))
+	if !ok {
+		return res, nil
+	}
+	for _, value := range v.list[from:to] {
+		res = append(res, []byte(value))
+	}
+
+	return res, nil
+}
+
+// LTrim trims a list to the elements between index start and stop, negative index counts from the end.
+func (m *MemoryRedis) LTrim(_ context.Context, key string, start, stop int64) error {
+	const op errorx.Op = "cache/MemoryRedis.LTrim"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryList)
+	if err != nil {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return nil
+	}
+
+	from, to, ok := memoryRange(start, stop, len(v.list))
+	if !ok {
+		v.list = nil
+	} else {
+		v.list = append([]string(nil), v.list[from:to]...)
+	}
+	m.cleanup(key, v)
+
+	return nil
+}
+
+// LLen gets the length of a list.
+func (m *MemoryRedis) LLen(_ context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.LLen"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryList)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return 0, nil
+	}
+
+	return int64(len(v.list)), nil
+}
+
+// SAdd add the specified members to the set stored at key.
+// It returns false if key and value combination exists.
+func (m *MemoryRedis) SAdd(_ context.Context, key string, value ...string) (bool, error) {
+	const op errorx.Op = "cache/MemoryRedis.SAdd"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.create(key, memorySet)
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	added := false
+	for _, member := range value {
+		if _, ok := v.set[member]; !ok {
+			v.set[member] = struct{}{}
+			added = true
+		}
+	}
+
+	return added, nil
+}
+
+// SRem removes the members from a set and returns the number of removed members.
+func (m *MemoryRedis) SRem(_ context.Context, key string, value ...string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.SRem"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memorySet)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return 0, nil
+	}
+
+	var res int64
+	for _, member := range value {
+		if _, ok := v.set[member]; ok {
+			delete(v.set, member)
+			res++
+		}
+	}
+	m.cleanup(key, v)
+
+	return res, nil
+}
+
+// SMembers gets all the members of a set, in lexicographical order.
+func (m *MemoryRedis) SMembers(_ context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/MemoryRedis.SMembers"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.members(key)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// SIsMember determines if a value is a member of a set.
+func (m *MemoryRedis) SIsMember(_ context.Context, key, value string) (bool, error) {
+	const op errorx.Op = "cache/MemoryRedis.SIsMember"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memorySet)
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return false, nil
+	}
+
+	_, ok := v.set[value]
+	return ok, nil
+}
+
+// SCard gets the number of members of a set.
+func (m *MemoryRedis) SCard(_ context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.SCard"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memorySet)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return 0, nil
+	}
+
+	return int64(len(v.set)), nil
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (m *MemoryRedis) ZAdd(_ context.Context, key string, members ...ZMember) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZAdd"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.zadd(key, members...)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (m *MemoryRedis) ZIncrBy(_ context.Context, key string, increment float64, member string) (float64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZIncrBy"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.create(key, memoryZSet)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	v.zset[member] += increment
+	return v.zset[member], nil
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (m *MemoryRedis) ZRange(_ context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZRange"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.zrange(key, start, stop, false)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (m *MemoryRedis) ZRevRange(_ context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZRevRange"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.zrange(key, start, stop, true)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (m *MemoryRedis) ZRangeByScore(
+	_ context.Context,
+	key, min, max string,
+	offset, count int64,
+) ([]ZMember, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZRangeByScore"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	members, err := m.zrangeByScore(key, min, max)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	if offset == 0 && count == 0 {
+		return members, nil
+	}
+	if offset < 0 || offset >= int64(len(members)) {
+		return []ZMember{}, nil
+	}
+	members = members[offset:]
+	if count > 0 && count < int64(len(members)) {
+		members = members[:count]
+	}
+
+	return members, nil
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (m *MemoryRedis) ZRank(_ context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZRank"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.zrank(op, key, member, false)
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (m *MemoryRedis) ZRevRank(_ context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZRevRank"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.zrank(op, key, member, true)
+}
+
+// ZScore gets the score of a sorted set member.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (m *MemoryRedis) ZScore(_ context.Context, key, member string) (float64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZScore"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryZSet)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+
+	score, ok := v.zset[member]
+	if !ok {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+
+	return score, nil
+}
+
+// ZCard gets the number of members of a sorted set.
+func (m *MemoryRedis) ZCard(_ context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZCard"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryZSet)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return 0, nil
+	}
+
+	return int64(len(v.zset)), nil
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (m *MemoryRedis) ZRem(_ context.Context, key string, members ...string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZRem"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, err := m.lookup(key, memoryZSet)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if v == nil {
+		return 0, nil
+	}
+
+	var res int64
+	for _, member := range members {
+		if _, ok := v.zset[member]; ok {
+			delete(v.zset, member)
+			res++
+		}
+	}
+	m.cleanup(key, v)
+
+	return res, nil
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (m *MemoryRedis) ZRemRangeByScore(_ context.Context, key, min, max string) (int64, error) {
+	const op errorx.Op = "cache/MemoryRedis.ZRemRangeByScore"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.zremRangeByScore(key, min, max)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// RunScript runs the Go implementation of a script shipped with the package, atomically.
+// The other scripts are reported as an error with errorx.CodeInvalid.
+func (m *MemoryRedis) RunScript(_ context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	const op errorx.Op = "cache/MemoryRedis.RunScript"
+
+	if err := script.validate(keys); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	impl, ok := memoryScripts[script.Hash()]
+	if !ok {
+		return nil, errorx.E("script is not supported by the in-memory redis", op, errorx.CodeInvalid)
+	}
+	if len(args) < impl.args {
+		return nil, errorx.E("invalid number of script arguments", op, errorx.CodeInvalid)
+	}
+
+	argv := make([]string, len(args))
+	for i, v := range args {
+		argv[i] = memoryArg(v)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := impl.run(m, keys, argv)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// LoadScripts checks the scripts are supported by the in-memory redis.
+// The other scripts are reported as an error with errorx.CodeInvalid.
+func (m *MemoryRedis) LoadScripts(_ context.Context, scripts ...*Script) error {
+	const op errorx.Op = "cache/MemoryRedis.LoadScripts"
+
+	for _, v := range scripts {
+		if _, ok := memoryScripts[v.Hash()]; !ok {
+			return errorx.E("script is not supported by the in-memory redis", op, errorx.CodeInvalid)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the client, releasing any open resources.
+// The data is kept, so a closed in-memory redis can still be inspected by the tests.
+func (m *MemoryRedis) Close() {}
+
+// lookup returns the value of a key, nil when the key is missing or expired.
+// The value of other kind is reported as a WRONGTYPE error.
+func (m *MemoryRedis) lookup(key string, kind memoryKind) (*memoryValue, error) {
+	v := m.get(key)
+	if v != nil && v.kind != kind {
+		return nil, errMemoryWrongType
+	}
+
+	return v, nil
+}
+
+// get returns the value of a key of any kind, nil when the key is missing or expired.
+// The expired key is deleted.
+func (m *MemoryRedis) get(key string) *memoryValue {
+	v, ok := m.data[key]
+	if !ok {
+		return nil
+	}
+	if !v.expiry.IsZero() && !m.now().Before(v.expiry) {
+		delete(m.data, key)
+		return nil
+	}
+
+	return v
+}
+
+// create returns the value of a key, or an empty value of the kind when the key is missing.
+func (m *MemoryRedis) create(key string, kind memoryKind) (*memoryValue, error) {
+	v, err := m.lookup(key, kind)
+	if err != nil || v != nil {
+		return v, err
+	}
+
+	v = &memoryValue{kind: kind}
+	switch kind {
+	case memoryHash:
+		v.hash = make(map[string]string)
+	case memorySet:
+		v.set = make(map[string]struct{})
+	case memoryZSet:
+		v.zset = make(map[string]float64)
+	}
+	m.data[key] = v
+
+	return v, nil
+}
+
+// cleanup deletes the key of an empty collection.
+func (m *MemoryRedis) cleanup(key string, v *memoryValue) {
+	if v.empty() {
+		delete(m.data, key)
+	}
+}
+
+// set sets a string value replacing any value of the key, zero expiry means the key doesn't expire.
+func (m *MemoryRedis) set(key, value string, expiry time.Time) {
+	m.data[key] = &memoryValue{
+		kind:   memoryString,
+		str:    value,
+		expiry: expiry,
+	}
+}
+
+// setNX sets a string value expiring after the ttl if the key is missing.
+func (m *MemoryRedis) setNX(key, value string, ttl time.Duration) bool {
+	if m.get(key) != nil {
+		return false
+	}
+
+	m.set(key, value, m.now().Add(ttl))
+	return true
+}
+
+// del deletes a key and reports whether it existed.
+func (m *MemoryRedis) del(key string) bool {
+	if m.get(key) == nil {
+		return false
+	}
+
+	delete(m.data, key)
+	return true
+}
+
+// expire sets the expiry of a key, the key expiring now or in the past is deleted.
+func (m *MemoryRedis) expire(key string, expiry time.Time) bool {
+	v := m.get(key)
+	if v == nil {
+		return false
+	}
+
+	if !expiry.After(m.now()) {
+		delete(m.data, key)
+		return true
+	}
+
+	v.expiry = expiry
+	return true
+}
+
+// pttl returns the remaining ttl of a key in milliseconds,
+// -2 when the key doesn't exist, and -1 when the key doesn't expire.
+func (m *MemoryRedis) pttl(key string) int64 {
+	v := m.get(key)
+	switch {
+	case v == nil:
+		return -2
+	case v.expiry.IsZero():
+		return -1
+	default:
+		return int64(v.expiry.Sub(m.now()) / time.Millisecond)
+	}
+}
+
+// incrBy increments the integer value of a key, the missing key counts as zero and the ttl is kept.
+func (m *MemoryRedis) incrBy(key string, by int64) (int64, error) {
+	v, err := m.lookup(key, memoryString)
+	if err != nil {
+		return 0, err
+	}
+	if v == nil {
+		v = &memoryValue{kind: memoryString, str: "0"}
+		m.data[key] = v
+	}
+
+	n, err := strconv.ParseInt(v.str, 10, 64)
+	if err != nil {
+		return 0, errMemoryNotInteger
+	}
+
+	n += by
+	v.str = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+// members returns the members of a set in lexicographical order.
+func (m *MemoryRedis) members(key string) ([]string, error) {
+	v, err := m.lookup(key, memorySet)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0)
+	if v != nil {
+		for member := range v.set {
+			res = append(res, member)
+		}
+	}
+	sort.Strings(res)
+
+	return res, nil
+}
+
+// zadd adds the members to a sorted set and returns the number of added members.
+func (m *MemoryRedis) zadd(key string, members ...ZMember) (int64, error) {
+	v, err := m.create(key, memoryZSet)
+	if err != nil {
+		return 0, err
+	}
+
+	var res int64
+	for _, member := range members {
+		if _, ok := v.zset[member.Member]; !ok {
+			res++
+		}
+		v.zset[member.Member] = member.Score
+	}
+
+	return res, nil
+}
+
+// zsorted returns the members of a sorted set ordered by score, then by member.
+func (m *MemoryRedis) zsorted(key string, rev bool) ([]ZMember, error) {
+	v, err := m.lookup(key, memoryZSet)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ZMember, 0)
+	if v == nil {
+		return res, nil
+	}
+	for member, score := range v.zset {
+		res = append(res, ZMember{Member: member, Score: score})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score < res[j].Score != rev
+		}
+		return res[i].Member < res[j].Member != rev
+	})
+
+	return res, nil
+}
+
+// zrange returns the members of a sorted set between index start and stop.
+func (m *MemoryRedis) zrange(key string, start, stop int64, rev bool) ([]ZMember, error) {
+	members, err := m.zsorted(key, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, ok := memoryRange(start, stop, len(members))
+	if !ok {
+		return []ZMember{}, nil
+	}
+
+	return members[from:to], nil
+}
+
+// zrangeByScore returns the members of a sorted set with a score between min and max, from the lowest score.
+func (m *MemoryRedis) zrangeByScore(key, min, max string) ([]ZMember, error) {
+	lower, err := parseMemoryBound(min)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parseMemoryBound(max)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := m.zsorted(key, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ZMember, 0, len(members))
+	for _, member := range members {
+		if lower.below(member.Score) && upper.above(member.Score) {
+			res = append(res, member)
+		}
+	}
+
+	return res, nil
+}
+
+// zremRangeByScore removes the members of a sorted set with a score between min and max.
+func (m *MemoryRedis) zremRangeByScore(key, min, max string) (int64, error) {
+	members, err := m.zrangeByScore(key, min, max)
+	if err != nil || len(members) == 0 {
+		return 0, err
+	}
+
+	v := m.data[key]
+	for _, member := range members {
+		delete(v.zset, member.Member)
+	}
+	m.cleanup(key, v)
+
+	return int64(len(members)), nil
+}
+
+// zrank returns the index of a sorted set member.
+func (m *MemoryRedis) zrank(op errorx.Op, key, member string, rev bool) (int64, error) {
+	members, err := m.zsorted(key, rev)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	for i, v := range members {
+		if v.Member == member {
+			return int64(i), nil
+		}
+	}
+
+	return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+}
+
+// memoryRange converts the inclusive start and stop index of redis, negative index counting from the end,
+// into the bounds of a slice of length n.
+// It returns false when the range is empty.
+func memoryRange(start, stop int64, n int) (from, to int, ok bool) {
+	length := int64(n)
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+	if start > stop || start >= length {
+		return 0, 0, false
+	}
+
+	return int(start), int(stop) + 1, true
+}
+
+// memoryBound is a score bound of ZRANGEBYSCORE, e.g. "1", "(1", "-inf" or "+inf".
+type memoryBound struct {
+	score     float64
+	exclusive bool
+}
+
+// parseMemoryBound parses a score bound.
+func parseMemoryBound(s string) (memoryBound, error) {
+	var b memoryBound
+	if len(s) > 0 && s[0] == '(' {
+		b.exclusive = true
+		s = s[1:]
+	}
+
+	switch s {
+	case "-inf":
+		b.score = math.Inf(-1)
+	case "+inf", "inf":
+		b.score = math.Inf(1)
+	default:
+		score, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return b, errMemoryMinMax
+		}
+		b.score = score
+	}
+
+	return b, nil
+}
+
+// below reports whether the score is above the lower bound.
+func (b memoryBound) below(score float64) bool {
+	if b.exclusive {
+		return b.score < score
+	}
+	return b.score <= score
+}
+
+// above reports whether the score is below the upper bound.
+func (b memoryBound) above(score float64) bool {
+	if b.exclusive {
+		return score < b.score
+	}
+	return score <= b.score
+}
+
+// memoryArg formats an argument like redis clients do.
+func memoryArg(v interface{}) string {
+	switch arg := v.(type) {
+	case string:
+		return arg
+	case []byte:
+		return string(arg)
+	case int:
+		return strconv.Itoa(arg)
+	case int64:
+		return strconv.FormatInt(arg, 10)
+	case float64:
+		return strconv.FormatFloat(arg, 'g', -1, 64)
+	default:
+		return fmt.Sprint(arg)
+	}
+}
+
+// memoryInt parses an integer argument of a script.
+func memoryInt(arg string) (int64, error) {
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, errMemoryNotInteger
+	}
+
+	return n, nil
+}
+
+// memoryFloat parses a number argument of a script.
+func memoryFloat(arg string) (float64, error) {
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, errMemoryNotFloat
+	}
+
+	return n, nil
+}
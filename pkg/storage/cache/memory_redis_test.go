@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRedis_Expiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1600000000, 0)}
+	m := NewMemoryRedisWithConfig(MemoryRedisConfig{Now: clock.Now})
+	ctx := context.Background()
+
+	require.NoError(t, m.SetEX(ctx, "string", 10, "value"))
+	_, err := m.HSet(ctx, "hash", "field", "value")
+	require.NoError(t, err)
+	_, err = m.Expire(ctx, "hash", 20)
+	require.NoError(t, err)
+	_, err = m.ExpireAt(ctx, "set", clock.now.Unix())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		wait      time.Duration
+		key       string
+		wantTTL   int64
+		wantFound bool
+	}{
+		{name: "Before expiry", wait: 0, key: "string", wantTTL: 10, wantFound: true},
+		{name: "Rounded ttl", wait: 4400 * time.Millisecond, key: "string", wantTTL: 6, wantFound: true},
+		{name: "At expiry", wait: 5600 * time.Millisecond, key: "string", wantTTL: -2, wantFound: false},
+		{name: "Hash before expiry", wait: 0, key: "hash", wantTTL: 10, wantFound: true},
+		{name: "Hash after expiry", wait: 10 * time.Second, key: "hash", wantTTL: -2, wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.now = clock.now.Add(tt.wait)
+
+			ttl, err := m.TTL(ctx, tt.key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTTL, ttl)
+
+			found, err := m.Exists(ctx, tt.key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFound, found)
+		})
+	}
+}
+
+func TestMemoryRedis_RunScript(t *testing.T) {
+	m := NewMemoryRedis()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		script   *Script
+		keys     []string
+		args     []interface{}
+		want     interface{}
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Unsupported script",
+			script:   NewScript(1, `return 1`),
+			keys:     []string{"key"},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Missing keys",
+			script:   ScriptLockAcquire,
+			keys:     []string{"lock"},
+			args:     []interface{}{"owner", 1000},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Missing arguments",
+			script:   ScriptLockAcquire,
+			keys:     []string{"{lock}", "{lock}:fence"},
+			args:     []interface{}{"owner"},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Invalid argument",
+			script:   ScriptLockAcquire,
+			keys:     []string{"{lock}", "{lock}:fence"},
+			args:     []interface{}{"owner", "soon"},
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name:   "Success",
+			script: ScriptIncrByEx,
+			keys:   []string{"counter"},
+			args:   []interface{}{5, 60},
+			want:   int64(5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.RunScript(ctx, tt.script, tt.keys, tt.args...)
+			if tt.wantCode != "" {
+				assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.NoError(t, m.LoadScripts(ctx, ScriptLockAcquire, ScriptRateLimitTokenBucket))
+	assert.Equal(t, errorx.CodeInvalid, errorx.GetCode(m.LoadScripts(ctx, NewScript(0, `return 1`))))
+}
+
+func TestMemoryRedis_Subscribe(t *testing.T) {
+	m := NewMemoryRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan *Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- m.PSubscribe(ctx, func(_ context.Context, msg *Message) error {
+			received <- msg
+			return nil
+		}, "news.*", "news.sport")
+	}()
+
+	// The subscriber matching both patterns receives the message twice.
+	assert.Eventually(t, func() bool {
+		n, err := m.Publish(ctx, "news.sport", "goal")
+		return err == nil && n == 2
+	}, time.Second, 10*time.Millisecond)
+
+	n, err := m.Publish(ctx, "weather", "rain")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	for _, pattern := range []string{"news.*", "news.sport"} {
+		msg := <-received
+		assert.Equal(t, &Message{Channel: "news.sport", Pattern: pattern, Data: []byte("goal")}, msg)
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	n, err = m.Publish(context.Background(), "news.sport", "goal")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestMemoryRedis_Client(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	redis := NewMemoryRedisWithConfig(MemoryRedisConfig{Now: clock.Now})
+
+	config := DefaultClientConfig
+	config.EarlyRefreshBeta = 0
+	config.Tagger = redis
+	client := NewClientWithConfig(NewRedisStore(redis), NewGobCodec(), config)
+	ctx := context.Background()
+
+	loads := 0
+	loader := func(context.Context) (interface{}, error) {
+		loads++
+		return "value", nil
+	}
+	get := func() {
+		var got string
+		assert.NoError(t, client.GetOrLoad(ctx, "key", time.Minute, &got, loader))
+		assert.Equal(t, "value", got)
+	}
+
+	get()
+	get()
+	assert.Equal(t, 1, loads)
+
+	// The value expires with the key.
+	clock.now = clock.now.Add(time.Minute)
+	get()
+	assert.Equal(t, 2, loads)
+
+	// The value is deleted with its tag.
+	assert.NoError(t, client.SetObject(ctx, "key", time.Minute, "value", "user:1"))
+	assert.NoError(t, client.InvalidateTag(ctx, "user:1"))
+	get()
+	assert.Equal(t, 3, loads)
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{pattern: "*", s: "", want: true},
+		{pattern: "*", s: "news", want: true},
+		{pattern: "news.*", s: "news.sport", want: true},
+		{pattern: "news.*", s: "news", want: false},
+		{pattern: "*.sport", s: "news.sport", want: true},
+		{pattern: "n**s", s: "news", want: true},
+		{pattern: "h?llo", s: "hello", want: true},
+		{pattern: "h?llo", s: "hllo", want: false},
+		{pattern: "h[ae]llo", s: "hallo", want: true},
+		{pattern: "h[ae]llo", s: "hillo", want: false},
+		{pattern: "h[^e]llo", s: "hallo", want: true},
+		{pattern: "h[^e]llo", s: "hello", want: false},
+		{pattern: "h[a-f]llo", s: "hello", want: true},
+		{pattern: "h[f-a]llo", s: "hello", want: true},
+		{pattern: "h[a-d]llo", s: "hello", want: false},
+		{pattern: `h\*llo`, s: "h*llo", want: true},
+		{pattern: `h\*llo`, s: "hello", want: false},
+		{pattern: `h[\]]llo`, s: "h]llo", want: true},
+		{pattern: "h[el", s: "he", want: true},
+		{pattern: "hello", s: "hello!", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.s, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchPattern(tt.pattern, tt.s))
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"sort"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// memoryScanCount is the default number of elements of a scan batch, as redis does.
+const memoryScanCount = 10
+
+// ScanKeys iterates the keys matching the pattern, count keys at a time in lexicographical order,
+// and calls fn with every batch of keys until fn returns an error.
+// The keys are collected before the first call, so fn can use the client.
+func (m *MemoryRedis) ScanKeys(_ context.Context, match string, count int64, fn func(keys []string) error) error {
+	const op errorx.Op = "cache/MemoryRedis.ScanKeys"
+
+	m.mu.Lock()
+	keys := make([]string, 0)
+	for key := range m.data {
+		if m.get(key) != nil && scanMatch(match, key) {
+			keys = append(keys, key)
+		}
+	}
+	m.mu.Unlock()
+
+	if err := memoryScan(keys, count, fn); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// HScan iterates the fields of a hash matching the pattern, count fields at a time in lexicographical order,
+// and calls fn with every batch of fields and their values until fn returns an error.
+// The fields are collected before the first call, so fn can use the client.
+func (m *MemoryRedis) HScan(_ context.Context, key, match string, count int64, fn func(fields map[string]string) error) error {
+	const op errorx.Op = "cache/MemoryRedis.HScan"
+
+	m.mu.Lock()
+	v, err := m.lookup(key, memoryHash)
+	if err != nil {
+		m.mu.Unlock()
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	values := make(map[string]string)
+	fields := make([]string, 0)
+	if v != nil {
+		for field, value := range v.hash {
+			if scanMatch(match, field) {
+				values[field] = value
+				fields = append(fields, field)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	err = memoryScan(fields, count, func(batch []string) error {
+		res := make(map[string]string, len(batch))
+		for _, field := range batch {
+			res[field] = values[field]
+		}
+
+		return fn(res)
+	})
+	if err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// SScan iterates the members of a set matching the pattern, count members at a time in lexicographical order,
+// and calls fn with every batch of members until fn returns an error.
+// The members are collected before the first call, so fn can use the client.
+func (m *MemoryRedis) SScan(_ context.Context, key, match string, count int64, fn func(members []string) error) error {
+	const op errorx.Op = "cache/MemoryRedis.SScan"
+
+	m.mu.Lock()
+	members, err := m.members(key)
+	m.mu.Unlock()
+	if err != nil {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	matched := make([]string, 0, len(members))
+	for _, member := range members {
+		if scanMatch(match, member) {
+			matched = append(matched, member)
+		}
+	}
+
+	if err := memoryScan(matched, count, fn); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// scanMatch reports whether s matches the pattern of a scan, empty pattern matches everything.
+func scanMatch(pattern, s string) bool {
+	return pattern == "" || matchPattern(pattern, s)
+}
+
+// memoryScan sorts the elements, and calls fn with every batch of count elements until fn returns an error.
+func memoryScan(elements []string, count int64, fn func(batch []string) error) error {
+	if count <= 0 {
+		count = memoryScanCount
+	}
+
+	sort.Strings(elements)
+	for len(elements) > 0 {
+		n := int(count)
+		if n > len(elements) {
+			n = len(elements)
+		}
+
+		if err := fn(elements[:n:n]); err != nil {
+			return err
+		}
+		elements = elements[n:]
+	}
+
+	return nil
+}
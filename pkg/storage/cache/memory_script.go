@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// The Go implementations of the scripts shipped with the package, run by MemoryRedis with its lock held.
+// They follow the Lua source of the scripts, and reply with the types of redigo.
+// Every script should be covered by cachetest.RunScripterConformance, which also runs the Lua source.
+
+// memoryScriptIncrByEx implements ScriptIncrByEx.
+func memoryScriptIncrByEx(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	by, err := memoryInt(args[0])
+	if err != nil {
+		return nil, err
+	}
+	seconds, err := memoryInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.incrBy(keys[0], by)
+	if err != nil {
+		return nil, err
+	}
+	m.expire(keys[0], m.now().Add(time.Duration(seconds)*time.Second))
+
+	return res, nil
+}
+
+// memoryScriptTagKey implements ScriptTagKey.
+func memoryScriptTagKey(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	seconds, err := memoryInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := m.create(keys[0], memorySet)
+	if err != nil {
+		return nil, err
+	}
+
+	added := int64(0)
+	if _, ok := v.set[args[0]]; !ok {
+		v.set[args[0]] = struct{}{}
+		added = 1
+	}
+	m.expire(keys[0], m.now().Add(time.Duration(seconds)*time.Second))
+
+	return added, nil
+}
+
+// memoryScriptInvalidateTag implements ScriptInvalidateTag.
+func memoryScriptInvalidateTag(m *MemoryRedis, keys, _ []string) (interface{}, error) {
+	members, err := m.members(keys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]interface{}, len(members))
+	for i, member := range members {
+		m.del(member)
+		res[i] = []byte(member)
+	}
+	m.del(keys[0])
+
+	return res, nil
+}
+
+// memoryScriptLockAcquire implements ScriptLockAcquire.
+func memoryScriptLockAcquire(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	ttl, err := memoryInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.setNX(keys[0], args[0], time.Duration(ttl)*time.Millisecond) {
+		return int64(0), nil
+	}
+
+	return m.incrBy(keys[1], 1)
+}
+
+// memoryScriptLockRelease implements ScriptLockRelease.
+func memoryScriptLockRelease(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	v, err := m.lookup(keys[0], memoryString)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || v.str != args[0] {
+		return int64(0), nil
+	}
+
+	m.del(keys[0])
+	return int64(1), nil
+}
+
+// memoryScriptLockRenew implements ScriptLockRenew.
+func memoryScriptLockRenew(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	ttl, err := memoryInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := m.lookup(keys[0], memoryString)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || v.str != args[0] {
+		return int64(0), nil
+	}
+
+	m.expire(keys[0], m.now().Add(time.Duration(ttl)*time.Millisecond))
+	return int64(1), nil
+}
+
+// memoryScriptRateLimitFixedWindow implements ScriptRateLimitFixedWindow.
+func memoryScriptRateLimitFixedWindow(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	limit, err := memoryInt(args[0])
+	if err != nil {
+		return nil, err
+	}
+	window, err := memoryInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := m.incrBy(keys[0], 1)
+	if err != nil {
+		return nil, err
+	}
+	if count == 1 {
+		m.expire(keys[0], m.now().Add(time.Duration(window)*time.Millisecond))
+	}
+
+	ttl := m.pttl(keys[0])
+	if count > limit {
+		return []interface{}{int64(0), int64(0), ttl}, nil
+	}
+
+	return []interface{}{int64(1), limit - count, ttl}, nil
+}
+
+// memoryScriptRateLimitSlidingLog implements ScriptRateLimitSlidingLog.
+func memoryScriptRateLimitSlidingLog(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	limit, err := memoryInt(args[0])
+	if err != nil {
+		return nil, err
+	}
+	window, err := memoryInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+	now, err := memoryInt(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := m.zremRangeByScore(keys[0], "-inf", strconv.FormatInt(now-window, 10)); err != nil {
+		return nil, err
+	}
+
+	members, err := m.zsorted(keys[0], false)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int64(len(members))
+	if count >= limit {
+		return []interface{}{int64(0), int64(0), int64(members[0].Score) + window - now}, nil
+	}
+
+	if _, err := m.zadd(keys[0], ZMember{Member: args[3], Score: float64(now)}); err != nil {
+		return nil, err
+	}
+	m.expire(keys[0], m.now().Add(time.Duration(window)*time.Millisecond))
+
+	return []interface{}{int64(1), limit - count - 1, window}, nil
+}
+
+// memoryScriptRateLimitTokenBucket implements ScriptRateLimitTokenBucket.
+func memoryScriptRateLimitTokenBucket(m *MemoryRedis, keys, args []string) (interface{}, error) {
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		v, err := memoryFloat(arg)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	capacity, rate, now, requested := values[0], values[1], values[2], values[3]
+
+	bucket, err := m.create(keys[0], memoryHash)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := strconv.ParseFloat(bucket.hash["tokens"], 64)
+	if err != nil {
+		tokens = capacity
+	}
+	timestamp, err := strconv.ParseFloat(bucket.hash["timestamp"], 64)
+	if err != nil {
+		timestamp = now
+	}
+
+	elapsed := math.Max(0, now-timestamp)
+	tokens = math.Min(capacity, tokens+elapsed*rate/1000)
+
+	var allowed, reset float64
+	if tokens >= requested {
+		allowed = 1
+		tokens -= requested
+		reset = math.Ceil((capacity - tokens) * 1000 / rate)
+	} else {
+		reset = math.Ceil((requested - tokens) * 1000 / rate)
+	}
+
+	// Lua formats the numbers with 14 significant digits, and redis with 17.
+	bucket.hash["tokens"] = fmt.Sprintf("%.14g", tokens)
+	bucket.hash["timestamp"] = fmt.Sprintf("%.17g", now)
+	m.expire(keys[0], m.now().Add(time.Duration(math.Ceil(capacity*1000/rate))*time.Millisecond))
+
+	return []interface{}{int64(allowed), int64(math.Floor(tokens)), int64(reset)}, nil
+}
@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/tags"
+	"github.com/segmentio/ksuid"
+)
+
+// MultiLevelConfig configuration.
+type MultiLevelConfig struct {
+	// L1TTL is the maximum time to live of a value in the local cache.
+	// It should be shorter than the redis ttl,
+	// since the local cache of other instances is only invalidated on write and delete.
+	// Zero value means the value lives in the local cache as long as in redis.
+	L1TTL time.Duration
+	// InvalidationChannel is the redis channel to broadcast the local cache invalidation.
+	// Empty string means the local cache of other instances is not invalidated.
+	InvalidationChannel string
+}
+
+// LoadFunc loads the value from the source of truth, usually the database.
+type LoadFunc func(ctx context.Context) ([]byte, error)
+
+// MultiLevel is a two-tier cache.
+// The first level is an in-process ristretto cache and the second level is redis.
+// Reads check the first level, then the second level and backfill the first level,
+// which costs a TTL round trip to bound the first level by the remaining ttl of the second level.
+// Writes and deletes are propagated to both levels,
+// and broadcast to other instances to evict their first level.
+// MultiLevel is a Store, so it can be used by Client to store typed values.
+type MultiLevel struct {
+	l1     *RistrettoStore
+	l2     *RedisStore
+	pubsub PubSubItf
+	config MultiLevelConfig
+	id     string
+}
+
+// NewMultiLevel returns a two-tier cache.
+// Nil pubsub means the local cache of other instances is not invalidated.
+func NewMultiLevel(
+	l1 RistrettoItf,
+	l2 RedisItf,
+	pubsub PubSubItf,
+	config MultiLevelConfig,
+) *MultiLevel {
+	return &MultiLevel{
+		l1:     NewRistrettoStore(l1),
+		l2:     NewRedisStore(l2),
+		pubsub: pubsub,
+		config: config,
+		id:     ksuid.New().String(),
+	}
+}
+
+// Get gets the value of a key from the first level, then the second level.
+// Value found in the second level is backfilled to the first level.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (m *MultiLevel) Get(ctx context.Context, key string) ([]byte, error) {
+	const op errorx.Op = "cache/MultiLevel.Get"
+
+	res, err := m.l1.Get(ctx, key)
+	if err == nil {
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "l1"}, string(op)+" success")
+		return res, nil
+	}
+
+	res, err = m.l2.Get(ctx, key)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	// The first level is best effort, a dropped value will be loaded from the second level.
+	if ttl, ok := m.backfillTTL(ctx, key); ok {
+		_ = m.l1.Set(ctx, key, res, ttl)
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "l2"}, string(op)+" success")
+	return res, nil
+}
+
+// backfillTTL returns the ttl of a value backfilled to the first level,
+// the shorter of L1TTL and the remaining ttl of the second level,
+// so the first level never serves a value expired from the second level.
+// It reports false when the value shouldn't be backfilled, the remaining ttl being unknown or elapsed.
+func (m *MultiLevel) backfillTTL(ctx context.Context, key string) (time.Duration, bool) {
+	const op errorx.Op = "cache/MultiLevel.backfillTTL"
+
+	seconds, err := m.l2.redis.TTL(ctx, key)
+	if err != nil {
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" failed")
+		return 0, false
+	}
+
+	switch {
+	case seconds == -1:
+		// The second level never expires.
+		return m.config.L1TTL, true
+	case seconds <= 0:
+		return 0, false
+	}
+
+	ttl := time.Duration(seconds) * time.Second
+	if m.config.L1TTL > 0 && m.config.L1TTL < ttl {
+		ttl = m.config.L1TTL
+	}
+
+	return ttl, true
+}
+
+// Fetch gets the value of a key, and loads it from the source on a miss.
+// The loaded value is stored to both levels, the second level expires after the ttl.
+func (m *MultiLevel) Fetch(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	loader LoadFunc,
+) ([]byte, error) {
+	const op errorx.Op = "cache/MultiLevel.Fetch"
+
+	res, err := m.Get(ctx, key)
+	if err == nil {
+		return res, nil
+	}
+	if !IsNotFound(err) {
+		return nil, errorx.E(err, op)
+	}
+
+	res, err = loader(ctx)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	if err := m.Set(ctx, key, res, ttl); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// Set sets the value of a key to both levels.
+// The second level expires after the ttl,
+// while the first level expires after the shorter of the ttl and L1TTL.
+func (m *MultiLevel) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	const op errorx.Op = "cache/MultiLevel.Set"
+
+	if err := m.l2.Set(ctx, key, value, ttl); err != nil {
+		return errorx.E(err, op)
+	}
+
+	l1TTL := m.config.L1TTL
+	if l1TTL <= 0 || ttl < l1TTL {
+		l1TTL = ttl
+	}
+	_ = m.l1.Set(ctx, key, value, l1TTL)
+
+	m.invalidate(ctx, key)
+	return nil
+}
+
+// Del deletes a key from both levels, and evicts it from the first level of other instances.
+func (m *MultiLevel) Del(ctx context.Context, key string) error {
+	const op errorx.Op = "cache/MultiLevel.Del"
+
+	if err := m.l2.Del(ctx, key); err != nil {
+		return errorx.E(err, op)
+	}
+
+	_ = m.l1.Del(ctx, key)
+
+	m.invalidate(ctx, key)
+	return nil
+}
+
+// Listen subscribes to the invalidation channel,
+// and evicts the first level whenever other instances write or delete a key.
+// It blocks until the context is done, the broken subscription is reconnected with backoff.
+func (m *MultiLevel) Listen(ctx context.Context) error {
+	const op errorx.Op = "cache/MultiLevel.Listen"
+
+	if m.pubsub == nil || m.config.InvalidationChannel == "" {
+		return errorx.E("missing pubsub client or invalidation channel", op, errorx.CodeConfig)
+	}
+
+	err := m.pubsub.Subscribe(ctx, func(ctx context.Context, msg *Message) error {
+		// Message format is "<instance id>:<key>".
+		data := string(msg.Data)
+		idx := strings.Index(data, ":")
+		if idx < 0 {
+			return errorx.E("invalid invalidation message", op, errorx.CodeInvalid)
+		}
+
+		// Skip the message from the current instance.
+		if data[:idx] == m.id {
+			return nil
+		}
+
+		return m.l1.Del(ctx, data[idx+1:])
+	}, m.config.InvalidationChannel)
+	if err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// evict deletes the keys from the first level, and evicts them from the first level of other instances.
+// It is used when the keys are already deleted from the second level.
+func (m *MultiLevel) evict(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		_ = m.l1.Del(ctx, key)
+		m.invalidate(ctx, key)
+	}
+}
+
+// invalidate broadcasts the key to other instances to evict their first level.
+func (m *MultiLevel) invalidate(ctx context.Context, key string) {
+	const op errorx.Op = "cache/MultiLevel.invalidate"
+
+	if m.pubsub == nil || m.config.InvalidationChannel == "" {
+		return
+	}
+
+	if _, err := m.pubsub.Publish(ctx, m.config.InvalidationChannel, m.id+":"+key); err != nil {
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" failed")
+	}
+}
@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiLevel_Get(t *testing.T) {
+	tests := []struct {
+		name     string
+		l1TTL    time.Duration
+		mock     func(l1 *MockRistrettoItf, l2 *MockRedisItf)
+		want     []byte
+		wantCode errorx.Code
+	}{
+		{
+			name:  "Found in first level",
+			l1TTL: time.Minute,
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return([]byte("l1"), nil)
+			},
+			want:     []byte("l1"),
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:  "Found in second level and backfill first level",
+			l1TTL: time.Minute,
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return([]byte("l2"), nil)
+				l2.EXPECT().TTL(gomock.Any(), "key").Return(int64(3600), nil)
+				l1.EXPECT().SetEX(gomock.Any(), "key", []byte("l2"), time.Minute).Return(true)
+			},
+			want:     []byte("l2"),
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:  "Backfill capped by the second level ttl",
+			l1TTL: time.Minute,
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return([]byte("l2"), nil)
+				l2.EXPECT().TTL(gomock.Any(), "key").Return(int64(20), nil)
+				l1.EXPECT().SetEX(gomock.Any(), "key", []byte("l2"), 20*time.Second).Return(true)
+			},
+			want:     []byte("l2"),
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:  "Backfill without first level ttl",
+			l1TTL: 0,
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return([]byte("l2"), nil)
+				l2.EXPECT().TTL(gomock.Any(), "key").Return(int64(30), nil)
+				l1.EXPECT().SetEX(gomock.Any(), "key", []byte("l2"), 30*time.Second).Return(true)
+			},
+			want:     []byte("l2"),
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:  "No backfill when the second level ttl is unknown",
+			l1TTL: time.Minute,
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return([]byte("l2"), nil)
+				l2.EXPECT().TTL(gomock.Any(), "key").Return(int64(0), errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:     []byte("l2"),
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:  "Not found",
+			l1TTL: time.Minute,
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+			},
+			want:     nil,
+			wantCode: errorx.CodeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			l1 := NewMockRistrettoItf(ctrl)
+			l2 := NewMockRedisItf(ctrl)
+			tt.mock(l1, l2)
+
+			m := NewMultiLevel(l1, l2, nil, MultiLevelConfig{L1TTL: tt.l1TTL})
+			got, err := m.Get(context.Background(), "key")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMultiLevel_Fetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(l1 *MockRistrettoItf, l2 *MockRedisItf, pubsub *MockPubSubItf)
+		loader   LoadFunc
+		want     []byte
+		wantCode errorx.Code
+	}{
+		{
+			name: "Redis error",
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf, pubsub *MockPubSubItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("redis error", errorx.CodeGateway))
+			},
+			loader:   nil,
+			want:     nil,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Loader error",
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf, pubsub *MockPubSubItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+			},
+			loader: func(ctx context.Context) ([]byte, error) {
+				return nil, errorx.E("db error", errorx.CodeDB)
+			},
+			want:     nil,
+			wantCode: errorx.CodeDB,
+		},
+		{
+			name: "Load and backfill both levels",
+			mock: func(l1 *MockRistrettoItf, l2 *MockRedisItf, pubsub *MockPubSubItf) {
+				l1.EXPECT().Lookup(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+				l2.EXPECT().SetEX(gomock.Any(), "key", int64(30), "db").Return(nil)
+				l1.EXPECT().SetEX(gomock.Any(), "key", []byte("db"), 10*time.Second).Return(true)
+				pubsub.EXPECT().Publish(gomock.Any(), "invalidation", gomock.Any()).
+					Return(0, errors.New("publish error"))
+			},
+			loader: func(ctx context.Context) ([]byte, error) {
+				return []byte("db"), nil
+			},
+			want:     []byte("db"),
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			l1 := NewMockRistrettoItf(ctrl)
+			l2 := NewMockRedisItf(ctrl)
+			pubsub := NewMockPubSubItf(ctrl)
+			tt.mock(l1, l2, pubsub)
+
+			m := NewMultiLevel(l1, l2, pubsub, MultiLevelConfig{
+				L1TTL:               10 * time.Second,
+				InvalidationChannel: "invalidation",
+			})
+			got, err := m.Fetch(context.Background(), "key", 30*time.Second, tt.loader)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMultiLevel_Listen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	l1 := NewMockRistrettoItf(ctrl)
+	l2 := NewMockRedisItf(ctrl)
+	pubsub := NewMockPubSubItf(ctrl)
+
+	remote := NewMultiLevel(l1, l2, pubsub, MultiLevelConfig{InvalidationChannel: "invalidation"})
+	local := NewMultiLevel(l1, l2, pubsub, MultiLevelConfig{InvalidationChannel: "invalidation"})
+
+	// Delete on the remote instance and capture the broadcast message.
+	var published string
+	l2.EXPECT().Del(gomock.Any(), "key").Return(int64(1), nil)
+	l1.EXPECT().Del(gomock.Any(), "key")
+	pubsub.EXPECT().Publish(gomock.Any(), "invalidation", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, message string) (int, error) {
+			published = message
+			return 1, nil
+		})
+	assert.NoError(t, remote.Del(context.Background(), "key"))
+	assert.True(t, strings.HasSuffix(published, ":key"))
+
+	// The local instance evicts the key, while the remote instance skips its own message.
+	l1.EXPECT().Del(gomock.Any(), "key").Times(1)
+	pubsub.EXPECT().Subscribe(gomock.Any(), gomock.Any(), "invalidation").
+		DoAndReturn(func(ctx context.Context, handler MessageHandler, _ ...string) error {
+			msg := &Message{Channel: "invalidation", Data: []byte(published)}
+			assert.NoError(t, handler(ctx, msg))
+			assert.Error(t, handler(ctx, &Message{Channel: "invalidation", Data: []byte("broken")}))
+			return nil
+		}).Times(2)
+	assert.NoError(t, local.Listen(context.Background()))
+	assert.NoError(t, remote.Listen(context.Background()))
+
+	// Missing configuration.
+	err := NewMultiLevel(l1, l2, nil, MultiLevelConfig{}).Listen(context.Background())
+	assert.True(t, errorx.Is(errorx.CodeConfig, err))
+}
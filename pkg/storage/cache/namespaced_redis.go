@@ -0,0 +1,276 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// NamespacedRedis is a redis client applying the namespace of a key builder to the keys of every command,
+// so the services sharing a redis cluster never collide, see KeyBuilder.
+// The keys are built with KeyBuilder.Key, e.g. "user:1" becomes "order:production:user:1".
+// The published topics aren't namespaced, since the subscribers don't use the client.
+type NamespacedRedis struct {
+	client RedisItf
+	keys   *KeyBuilder
+}
+
+// NewNamespacedRedis returns the client applying the namespace of the key builder to the redis client.
+func NewNamespacedRedis(client RedisItf, keys *KeyBuilder) *NamespacedRedis {
+	return &NamespacedRedis{
+		client: client,
+		keys:   keys,
+	}
+}
+
+// Keys returns the key builder of the namespace.
+func (r *NamespacedRedis) Keys() *KeyBuilder {
+	return r.keys
+}
+
+// Get gets the value from redis in []byte form.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *NamespacedRedis) Get(ctx context.Context, key string) ([]byte, error) {
+	return r.client.Get(ctx, r.keys.Key(key))
+}
+
+// SimpleSet sets value to key in redis without any additional options.
+// Key doesn't have a TTL.
+func (r *NamespacedRedis) SimpleSet(ctx context.Context, key, value string) error {
+	return r.client.SimpleSet(ctx, r.keys.Key(key), value)
+}
+
+// SetEX sets the value to a key with timeout in seconds.
+func (r *NamespacedRedis) SetEX(ctx context.Context, key string, seconds int64, value string) error {
+	return r.client.SetEX(ctx, r.keys.Key(key), seconds, value)
+}
+
+// SetNX sets a value to a key with specified timeouts.
+// SetNX returns false if the key exists.
+func (r *NamespacedRedis) SetNX(ctx context.Context, key string, seconds int64, value string) (bool, error) {
+	return r.client.SetNX(ctx, r.keys.Key(key), seconds, value)
+}
+
+// Exists checks whether the key exists in redis.
+func (r *NamespacedRedis) Exists(ctx context.Context, key string) (bool, error) {
+	return r.client.Exists(ctx, r.keys.Key(key))
+}
+
+// Expire sets the TTL of a key to specified value in seconds.
+func (r *NamespacedRedis) Expire(ctx context.Context, key string, seconds int64) (bool, error) {
+	return r.client.Expire(ctx, r.keys.Key(key), seconds)
+}
+
+// ExpireAt sets the TTL of a key to a certain unix timestamp in seconds.
+func (r *NamespacedRedis) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
+	return r.client.ExpireAt(ctx, r.keys.Key(key), timestamp)
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+// It returns -2 if the key does not exist and -1 if the key has no expiry.
+func (r *NamespacedRedis) TTL(ctx context.Context, key string) (int64, error) {
+	return r.client.TTL(ctx, r.keys.Key(key))
+}
+
+// Del deletes a key.
+func (r *NamespacedRedis) Del(ctx context.Context, key ...interface{}) (int64, error) {
+	return r.client.Del(ctx, r.namespace(key)...)
+}
+
+// Incr increments the integer value of a key by 1.
+func (r *NamespacedRedis) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, r.keys.Key(key))
+}
+
+// Decr decrements the integer value of a key by 1.
+func (r *NamespacedRedis) Decr(ctx context.Context, key string) (int64, error) {
+	return r.client.Decr(ctx, r.keys.Key(key))
+}
+
+// IncrBy increments the integer value of a key by the given amount.
+func (r *NamespacedRedis) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	return r.client.IncrBy(ctx, r.keys.Key(key), by)
+}
+
+// IncrByEx increments the integer value of a key by the given amount,
+// and sets the TTL of the key to specified value in seconds.
+func (r *NamespacedRedis) IncrByEx(ctx context.Context, key string, by int64, expires int64) (int64, error) {
+	return r.client.IncrByEx(ctx, r.keys.Key(key), by, expires)
+}
+
+// HGet gets the value of a hash field.
+// Missing key or field is reported as an error with errorx.CodeNotFound.
+func (r *NamespacedRedis) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	return r.client.HGet(ctx, r.keys.Key(key), field)
+}
+
+// HMGet gets the values of multiple hash fields in the order of the fields.
+// Missing field is returned as a nil value.
+func (r *NamespacedRedis) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
+	return r.client.HMGet(ctx, r.keys.Key(key), fields...)
+}
+
+// HGetAll gets all the fields and values in a hash.
+func (r *NamespacedRedis) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, r.keys.Key(key))
+}
+
+// HKeys gets all the fields in a hash.
+func (r *NamespacedRedis) HKeys(ctx context.Context, key string) ([]string, error) {
+	return r.client.HKeys(ctx, r.keys.Key(key))
+}
+
+// HExists determines if a hash field exists.
+func (r *NamespacedRedis) HExists(ctx context.Context, key, field string) (bool, error) {
+	return r.client.HExists(ctx, r.keys.Key(key), field)
+}
+
+// HSet sets the string value of a hash field.
+func (r *NamespacedRedis) HSet(ctx context.Context, key, field, value string) (bool, error) {
+	return r.client.HSet(ctx, r.keys.Key(key), field, value)
+}
+
+// HDel deletes hash fields and returns the number of deleted fields.
+func (r *NamespacedRedis) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	return r.client.HDel(ctx, r.keys.Key(key), fields...)
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (r *NamespacedRedis) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	return r.client.LPush(ctx, r.keys.Key(key), values...)
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (r *NamespacedRedis) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	return r.client.RPush(ctx, r.keys.Key(key), values...)
+}
+
+// LRange gets the elements of a list between index start and stop.
+func (r *NamespacedRedis) LRange(ctx context.Context, key string, start, stop int64) ([][]byte, error) {
+	return r.client.LRange(ctx, r.keys.Key(key), start, stop)
+}
+
+// LTrim trims a list to the elements between index start and stop.
+func (r *NamespacedRedis) LTrim(ctx context.Context, key string, start, stop int64) error {
+	return r.client.LTrim(ctx, r.keys.Key(key), start, stop)
+}
+
+// LLen gets the length of a list.
+func (r *NamespacedRedis) LLen(ctx context.Context, key string) (int64, error) {
+	return r.client.LLen(ctx, r.keys.Key(key))
+}
+
+// SAdd add the specified members to the set stored at key.
+// It returns false if key and value combination exists.
+func (r *NamespacedRedis) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
+	return r.client.SAdd(ctx, r.keys.Key(key), value...)
+}
+
+// SRem removes the members from a set and returns the number of removed members.
+func (r *NamespacedRedis) SRem(ctx context.Context, key string, value ...string) (int64, error) {
+	return r.client.SRem(ctx, r.keys.Key(key), value...)
+}
+
+// SMembers gets all the members of a set.
+func (r *NamespacedRedis) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, r.keys.Key(key))
+}
+
+// SIsMember determines if a value is a member of a set.
+func (r *NamespacedRedis) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	return r.client.SIsMember(ctx, r.keys.Key(key), value)
+}
+
+// SCard gets the number of members of a set.
+func (r *NamespacedRedis) SCard(ctx context.Context, key string) (int64, error) {
+	return r.client.SCard(ctx, r.keys.Key(key))
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (r *NamespacedRedis) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	return r.client.ZAdd(ctx, r.keys.Key(key), members...)
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (r *NamespacedRedis) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	return r.client.ZIncrBy(ctx, r.keys.Key(key), increment, member)
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (r *NamespacedRedis) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	return r.client.ZRange(ctx, r.keys.Key(key), start, stop)
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (r *NamespacedRedis) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	return r.client.ZRevRange(ctx, r.keys.Key(key), start, stop)
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Min and max accept the redis score syntax, e.g. "-inf", "+inf" and "(1" for exclusive bound.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (r *NamespacedRedis) ZRangeByScore(ctx context.Context, key, min, max string, offset, count int64) ([]ZMember, error) {
+	return r.client.ZRangeByScore(ctx, r.keys.Key(key), min, max, offset, count)
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *NamespacedRedis) ZRank(ctx context.Context, key, member string) (int64, error) {
+	return r.client.ZRank(ctx, r.keys.Key(key), member)
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *NamespacedRedis) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	return r.client.ZRevRank(ctx, r.keys.Key(key), member)
+}
+
+// ZScore gets the score of a sorted set member.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *NamespacedRedis) ZScore(ctx context.Context, key, member string) (float64, error) {
+	return r.client.ZScore(ctx, r.keys.Key(key), member)
+}
+
+// ZCard gets the number of members of a sorted set.
+func (r *NamespacedRedis) ZCard(ctx context.Context, key string) (int64, error) {
+	return r.client.ZCard(ctx, r.keys.Key(key))
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (r *NamespacedRedis) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	return r.client.ZRem(ctx, r.keys.Key(key), members...)
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (r *NamespacedRedis) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	return r.client.ZRemRangeByScore(ctx, r.keys.Key(key), min, max)
+}
+
+// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+func (r *NamespacedRedis) Publish(ctx context.Context, topic, message string) (int, error) {
+	return r.client.Publish(ctx, topic, message)
+}
+
+// Close closes the client, releasing any open resources.
+func (r *NamespacedRedis) Close() {
+	r.client.Close()
+}
+
+// namespace applies the namespace to the keys of Del, the keys of other types are formatted as strings.
+func (r *NamespacedRedis) namespace(keys []interface{}) []interface{} {
+	res := make([]interface{}, len(keys))
+	for i, key := range keys {
+		switch v := key.(type) {
+		case string:
+			res[i] = r.keys.Key(v)
+		case []byte:
+			res[i] = r.keys.Key(string(v))
+		default:
+			res[i] = r.keys.Key(fmt.Sprint(v))
+		}
+	}
+
+	return res
+}
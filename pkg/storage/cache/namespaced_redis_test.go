@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacedRedis(t *testing.T) {
+	tests := []struct {
+		name string
+		mock func(client *MockRedisItf)
+		call func(client *NamespacedRedis)
+	}{
+		{
+			name: "Get",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().Get(gomock.Any(), "order:production:user:1").Return([]byte("value"), nil)
+			},
+			call: func(client *NamespacedRedis) {
+				got, err := client.Get(context.Background(), "user:1")
+				assert.NoError(t, err)
+				assert.Equal(t, []byte("value"), got)
+			},
+		},
+		{
+			name: "HSet",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().HSet(gomock.Any(), "order:production:user:1", "name", "value").Return(true, nil)
+			},
+			call: func(client *NamespacedRedis) {
+				_, _ = client.HSet(context.Background(), "user:1", "name", "value")
+			},
+		},
+		{
+			name: "Variadic values",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().SAdd(gomock.Any(), "order:production:users", "1", "2").Return(true, nil)
+			},
+			call: func(client *NamespacedRedis) {
+				_, _ = client.SAdd(context.Background(), "users", "1", "2")
+			},
+		},
+		{
+			name: "Del",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().
+					Del(gomock.Any(), "order:production:user:1", "order:production:user:2", "order:production:3").
+					Return(int64(3), nil)
+			},
+			call: func(client *NamespacedRedis) {
+				_, _ = client.Del(context.Background(), "user:1", []byte("user:2"), 3)
+			},
+		},
+		{
+			name: "Publish",
+			mock: func(client *MockRedisItf) {
+				client.EXPECT().Publish(gomock.Any(), "topic", "message").Return(1, nil)
+			},
+			call: func(client *NamespacedRedis) {
+				_, _ = client.Publish(context.Background(), "topic", "message")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := NewMockRedisItf(ctrl)
+			tt.mock(client)
+
+			keys := NewKeyBuilderWithConfig(KeyBuilderConfig{Service: "order", Env: env.Production})
+			tt.call(NewNamespacedRedis(client, keys))
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/stack"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// Default delays between the reconnections of a broken subscription.
+const (
+	DefaultSubscribeMinBackoff = 100 * time.Millisecond
+	DefaultSubscribeMaxBackoff = 30 * time.Second
+)
+
+// Message is a message received from a subscribed channel.
+type Message struct {
+	// Channel is the channel where the message is published.
+	Channel string
+	// Pattern is the matched pattern, only filled by the pattern subscriptions.
+	Pattern string
+	// Data is the message payload.
+	Data []byte
+}
+
+// MessageHandler handles a message received from a subscribed channel.
+type MessageHandler func(ctx context.Context, msg *Message) error
+
+// subscribeBackoff is the delay between the reconnections of a broken subscription.
+// The delay doubles on every failed attempt, from min up to max.
+// Zero value uses the default delays.
+type subscribeBackoff struct {
+	min time.Duration
+	max time.Duration
+}
+
+// newSubscribeBackoff returns the backoff of the configuration.
+func newSubscribeBackoff(config *RedisConfiguration) subscribeBackoff {
+	return subscribeBackoff{
+		min: config.SubscribeMinBackoff,
+		max: config.SubscribeMaxBackoff,
+	}
+}
+
+// duration returns the delay before the attempt, randomized between half and the full delay,
+// so the subscribers don't reconnect at the same time after a redis restart.
+func (b subscribeBackoff) duration(attempt int) time.Duration {
+	min, max := b.min, b.max
+	if min <= 0 {
+		min = DefaultSubscribeMinBackoff
+	}
+	if max <= 0 {
+		max = DefaultSubscribeMaxBackoff
+	}
+	if max < min {
+		max = min
+	}
+
+	d := min
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1)) //nolint:gosec
+}
+
+// subscribeSession subscribes on a new connection and dispatches the received messages,
+// until the context is done or the connection is broken.
+// It calls ready once redis has confirmed the subscription.
+type subscribeSession func(ctx context.Context, ready func(), dispatch func(ctx context.Context, msg *Message)) error
+
+// subscription is a subscription to channels or patterns, surviving the broken connections.
+type subscription struct {
+	op      errorx.Op
+	names   []string
+	handler MessageHandler
+	backoff subscribeBackoff
+	session subscribeSession
+	// sleep waits for the backoff, replaced in tests.
+	sleep func(ctx context.Context, d time.Duration)
+}
+
+// run runs the sessions until the context is done.
+// A broken session is logged and started again after the backoff,
+// the backoff is reset once a session has been confirmed by redis.
+func (s *subscription) run(ctx context.Context) error {
+	sleep := s.sleep
+	if sleep == nil {
+		sleep = sleepFor
+	}
+
+	attempt := 0
+	for {
+		var ready int32
+		err := s.session(ctx, func() { atomic.StoreInt32(&ready, 1) }, s.dispatch)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if atomic.LoadInt32(&ready) == 1 {
+			attempt = 0
+		}
+
+		if err == nil {
+			err = errorx.E("subscription ended by redis", errorx.CodeGateway)
+		}
+		logx.WRN(ctx, errorx.E(err, s.op, errorx.Fields{tags.Channel: strings.Join(s.names, ",")}),
+			string(s.op)+" subscription broken, reconnecting")
+
+		sleep(ctx, s.backoff.duration(attempt))
+		attempt++
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// dispatch calls the handler, the error and panic of the handler are logged.
+func (s *subscription) dispatch(ctx context.Context, msg *Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := errorx.E(fmt.Errorf("%v", r), s.op, errorx.CodeInternal, errorx.Fields{
+				tags.Channel:    msg.Channel,
+				tags.Panic:      r,
+				tags.StackTrace: stack.ToArr(stack.Trim(debug.Stack())),
+			})
+			logx.ERR(ctx, err, string(s.op)+" handler panicked")
+		}
+	}()
+
+	if err := s.handler(ctx, msg); err != nil {
+		logx.ERR(ctx, errorx.E(err, s.op, errorx.Fields{tags.Channel: msg.Channel}), string(s.op)+" handler failed")
+	}
+}
+
+// sleepFor sleeps for the duration or until the context is done.
+func sleepFor(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
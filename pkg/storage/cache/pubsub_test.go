@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeBackoff_duration(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff subscribeBackoff
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "Default first attempt",
+			backoff: subscribeBackoff{},
+			attempt: 0,
+			want:    DefaultSubscribeMinBackoff,
+		},
+		{
+			name:    "Doubled",
+			backoff: subscribeBackoff{min: time.Second, max: time.Minute},
+			attempt: 3,
+			want:    8 * time.Second,
+		},
+		{
+			name:    "Capped",
+			backoff: subscribeBackoff{min: time.Second, max: 5 * time.Second},
+			attempt: 100,
+			want:    5 * time.Second,
+		},
+		{
+			name:    "Max lower than min",
+			backoff: subscribeBackoff{min: time.Second, max: time.Millisecond},
+			attempt: 2,
+			want:    time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				got := tt.backoff.duration(tt.attempt)
+				assert.GreaterOrEqual(t, int64(got), int64(tt.want/2))
+				assert.LessOrEqual(t, int64(got), int64(tt.want))
+			}
+		})
+	}
+}
+
+func TestSubscription_run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		sessions int
+		received []string
+		sleeps   []time.Duration
+	)
+	sub := &subscription{
+		op:    "cache.test",
+		names: []string{"channel"},
+		handler: func(_ context.Context, msg *Message) error {
+			received = append(received, string(msg.Data))
+			switch string(msg.Data) {
+			case "panic":
+				panic("handler panicked")
+			case "error":
+				return errorx.E("handler failed", errorx.CodeInternal)
+			}
+			return nil
+		},
+		backoff: subscribeBackoff{min: time.Second, max: time.Minute},
+		session: func(ctx context.Context, ready func(), dispatch func(ctx context.Context, msg *Message)) error {
+			sessions++
+			switch sessions {
+			case 1, 2:
+				// Connection refused before the subscription is confirmed.
+				return errorx.E("connection refused", errorx.CodeGateway)
+			case 3:
+				// The handler panic and error don't break the subscription.
+				ready()
+				dispatch(ctx, &Message{Channel: "channel", Data: []byte("panic")})
+				dispatch(ctx, &Message{Channel: "channel", Data: []byte("error")})
+				dispatch(ctx, &Message{Channel: "channel", Data: []byte("ok")})
+				return errorx.E("connection reset", errorx.CodeGateway)
+			default:
+				ready()
+				cancel()
+				<-ctx.Done()
+				return nil
+			}
+		},
+		sleep: func(_ context.Context, d time.Duration) {
+			sleeps = append(sleeps, d)
+		},
+	}
+
+	assert.NoError(t, sub.run(ctx))
+	assert.Equal(t, 4, sessions)
+	assert.Equal(t, []string{"panic", "error", "ok"}, received)
+
+	// The backoff grows on the failed attempts, and is reset once the subscription is confirmed.
+	assert.Len(t, sleeps, 3)
+	assert.LessOrEqual(t, int64(sleeps[0]), int64(time.Second))
+	assert.GreaterOrEqual(t, int64(sleeps[1]), int64(time.Second))
+	assert.LessOrEqual(t, int64(sleeps[2]), int64(time.Second))
+}
+
+func TestSubscription_run_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var sessions int
+	sub := &subscription{
+		op:      "cache.test",
+		names:   []string{"channel"},
+		handler: func(context.Context, *Message) error { return nil },
+		session: func(context.Context, func(), func(context.Context, *Message)) error {
+			sessions++
+			return errorx.E("connection refused", errorx.CodeGateway)
+		},
+		sleep: func(context.Context, time.Duration) {
+			// The context is canceled while waiting for the backoff.
+			cancel()
+		},
+	}
+
+	assert.NoError(t, sub.run(ctx))
+	assert.Equal(t, 1, sessions)
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// localState is the state of a key in the local limiter.
+type localState struct {
+	// count is the number of requests in the fixed window.
+	count int64
+	// log is the time of the requests in the sliding window, the oldest first.
+	log []time.Time
+	// tokens is the number of tokens in the bucket, refilled at timestamp.
+	tokens    float64
+	timestamp time.Time
+	// expiry is the time when the state is back to its initial value.
+	expiry time.Time
+}
+
+// LocalLimiter is an in-process limiter, useful for tests and single instance services,
+// or to protect a single instance in front of the distributed limiter.
+// It follows the same algorithms as the rate limit scripts of the cache package.
+// The states are kept in a map rather than a ristretto cache,
+// since ristretto sets are asynchronous and may drop the counters.
+type LocalLimiter struct {
+	config Config
+	now    func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*localState
+	// sweep is the time of the next removal of the expired states.
+	sweep time.Time
+}
+
+// NewLocalLimiter returns an in-process limiter.
+func NewLocalLimiter(config Config) (*LocalLimiter, error) {
+	const op errorx.Op = "ratelimit.NewLocalLimiter"
+
+	if err := config.validate(); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return &LocalLimiter{
+		config: config,
+		now:    time.Now,
+		states: make(map[string]*localState),
+	}, nil
+}
+
+// Allow counts a request of the key and reports whether it is allowed.
+func (l *LocalLimiter) Allow(_ context.Context, key string) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.removeExpired(now)
+
+	key = l.config.Prefix + key
+	state, ok := l.states[key]
+	if !ok || !now.Before(state.expiry) {
+		state = &localState{}
+		l.states[key] = state
+	}
+
+	res := Result{
+		Limit: l.config.limit(),
+	}
+	switch l.config.Algorithm {
+	case FixedWindow:
+		l.fixedWindow(state, now, &res)
+	case SlidingWindow:
+		l.slidingWindow(state, now, &res)
+	case TokenBucket:
+		l.tokenBucket(state, now, &res)
+	}
+
+	return res, nil
+}
+
+// fixedWindow counts a request in the window starting at the first request.
+func (l *LocalLimiter) fixedWindow(state *localState, now time.Time, res *Result) {
+	limit := l.config.Limit
+	if state.count == 0 {
+		state.expiry = now.Add(limit.Period)
+	}
+	state.count++
+
+	res.ResetAfter = state.expiry.Sub(now)
+	if state.count > limit.Rate {
+		return
+	}
+	res.Allowed = true
+	res.Remaining = limit.Rate - state.count
+}
+
+// slidingWindow logs a request if there are less than rate requests in the last period.
+func (l *LocalLimiter) slidingWindow(state *localState, now time.Time, res *Result) {
+	limit := l.config.Limit
+
+	start := now.Add(-limit.Period)
+	i := 0
+	for i < len(state.log) && !state.log[i].After(start) {
+		i++
+	}
+	state.log = state.log[i:]
+
+	count := int64(len(state.log))
+	if count >= limit.Rate {
+		res.ResetAfter = state.log[0].Add(limit.Period).Sub(now)
+		return
+	}
+
+	state.log = append(state.log, now)
+	state.expiry = now.Add(limit.Period)
+
+	res.Allowed = true
+	res.Remaining = limit.Rate - count - 1
+	res.ResetAfter = limit.Period
+}
+
+// tokenBucket refills the bucket since the last request and takes a token.
+func (l *LocalLimiter) tokenBucket(state *localState, now time.Time, res *Result) {
+	var (
+		capacity = float64(l.config.Limit.burst())
+		rate     = l.config.Limit.perSecond()
+	)
+
+	if state.timestamp.IsZero() {
+		state.tokens = capacity
+		state.timestamp = now
+	}
+	if elapsed := now.Sub(state.timestamp); elapsed > 0 {
+		state.tokens = math.Min(capacity, state.tokens+elapsed.Seconds()*rate)
+	}
+	state.timestamp = now
+	state.expiry = now.Add(refill(capacity, rate))
+
+	if state.tokens < 1 {
+		res.ResetAfter = refill(1-state.tokens, rate)
+		res.Remaining = int64(state.tokens)
+		return
+	}
+
+	state.tokens--
+	res.Allowed = true
+	res.Remaining = int64(state.tokens)
+	res.ResetAfter = refill(capacity-state.tokens, rate)
+}
+
+// refill returns the duration to refill the tokens at the rate, rounded up to milliseconds.
+func refill(tokens, rate float64) time.Duration {
+	return time.Duration(math.Ceil(tokens*1000/rate)) * time.Millisecond
+}
+
+// removeExpired removes the expired states at most once every period,
+// so the keys seen only once don't grow the map forever.
+// The caller must hold the mutex.
+func (l *LocalLimiter) removeExpired(now time.Time) {
+	if now.Before(l.sweep) {
+		return
+	}
+	l.sweep = now.Add(l.config.Limit.Period)
+
+	for key, state := range l.states {
+		if !now.Before(state.expiry) {
+			delete(l.states, key)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// step is a request made after a delay since the previous request.
+type step struct {
+	after time.Duration
+	want  Result
+}
+
+func TestLocalLimiter_Allow(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		steps  []step
+	}{
+		{
+			name:   "Fixed window",
+			config: Config{Algorithm: FixedWindow, Limit: PerSecond(2)},
+			steps: []step{
+				{after: 0, want: Result{Limit: 2, Allowed: true, Remaining: 1, ResetAfter: time.Second}},
+				{after: 400 * time.Millisecond, want: Result{Limit: 2, Allowed: true, Remaining: 0, ResetAfter: 600 * time.Millisecond}},
+				{after: 100 * time.Millisecond, want: Result{Limit: 2, Allowed: false, Remaining: 0, ResetAfter: 500 * time.Millisecond}},
+				{after: 500 * time.Millisecond, want: Result{Limit: 2, Allowed: true, Remaining: 1, ResetAfter: time.Second}},
+			},
+		},
+		{
+			name:   "Sliding window",
+			config: Config{Algorithm: SlidingWindow, Limit: PerSecond(2)},
+			steps: []step{
+				{after: 0, want: Result{Limit: 2, Allowed: true, Remaining: 1, ResetAfter: time.Second}},
+				{after: 400 * time.Millisecond, want: Result{Limit: 2, Allowed: true, Remaining: 0, ResetAfter: time.Second}},
+				{after: 100 * time.Millisecond, want: Result{Limit: 2, Allowed: false, Remaining: 0, ResetAfter: 500 * time.Millisecond}},
+				// The first request has left the window, the second one is still in it.
+				{after: 600 * time.Millisecond, want: Result{Limit: 2, Allowed: true, Remaining: 0, ResetAfter: time.Second}},
+				{after: 100 * time.Millisecond, want: Result{Limit: 2, Allowed: false, Remaining: 0, ResetAfter: 200 * time.Millisecond}},
+			},
+		},
+		{
+			name:   "Token bucket",
+			config: Config{Algorithm: TokenBucket, Limit: Limit{Rate: 10, Period: time.Second, Burst: 2}},
+			steps: []step{
+				{after: 0, want: Result{Limit: 2, Allowed: true, Remaining: 1, ResetAfter: 100 * time.Millisecond}},
+				{after: 0, want: Result{Limit: 2, Allowed: true, Remaining: 0, ResetAfter: 200 * time.Millisecond}},
+				{after: 50 * time.Millisecond, want: Result{Limit: 2, Allowed: false, Remaining: 0, ResetAfter: 50 * time.Millisecond}},
+				{after: 50 * time.Millisecond, want: Result{Limit: 2, Allowed: true, Remaining: 0, ResetAfter: 200 * time.Millisecond}},
+				{after: time.Second, want: Result{Limit: 2, Allowed: true, Remaining: 1, ResetAfter: 100 * time.Millisecond}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := NewLocalLimiter(tt.config)
+			assert.NoError(t, err)
+
+			now := time.Now()
+			limiter.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				now = now.Add(s.after)
+				got, err := limiter.Allow(context.Background(), "key")
+				assert.NoError(t, err)
+				assert.Equal(t, s.want, got, "step %d", i)
+			}
+
+			// Other keys have their own limit.
+			got, err := limiter.Allow(context.Background(), "other")
+			assert.NoError(t, err)
+			assert.True(t, got.Allowed)
+		})
+	}
+}
+
+func TestLocalLimiter_removeExpired(t *testing.T) {
+	limiter, err := NewLocalLimiter(Config{Algorithm: FixedWindow, Limit: PerSecond(1)})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	_, _ = limiter.Allow(context.Background(), "a")
+	now = now.Add(time.Second)
+	_, _ = limiter.Allow(context.Background(), "b")
+
+	assert.Len(t, limiter.states, 1)
+	assert.Contains(t, limiter.states, "b")
+}
@@ -0,0 +1,164 @@
+// Package ratelimit provides rate limiters with fixed window, sliding window and token bucket algorithms,
+// either distributed through redis or local to the process.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// Response headers set by SetHeaders.
+const (
+	HeaderRetryAfter          = "Retry-After"
+	HeaderXRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderXRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderXRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// Algorithm is a rate limiting algorithm.
+type Algorithm int
+
+// Rate limiting algorithms.
+const (
+	// FixedWindow counts the requests in a window starting at the first request.
+	// It is the cheapest algorithm, but allows up to twice the rate around the window boundary.
+	FixedWindow Algorithm = iota
+	// SlidingWindow logs the time of every request and counts the requests in the last period.
+	// It is exact, but stores one entry for every allowed request.
+	SlidingWindow
+	// TokenBucket refills a bucket of Burst tokens at the rate, and takes a token for every request.
+	// It smooths the traffic while allowing short bursts.
+	TokenBucket
+)
+
+// String returns the name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case FixedWindow:
+		return "fixed_window"
+	case SlidingWindow:
+		return "sliding_window"
+	case TokenBucket:
+		return "token_bucket"
+	default:
+		return "unknown"
+	}
+}
+
+// Limit is the number of requests allowed every period.
+type Limit struct {
+	Rate   int64
+	Period time.Duration
+	// Burst is the capacity of the token bucket, only used by TokenBucket.
+	// Zero value means the rate.
+	Burst int64
+}
+
+// PerSecond returns a limit of rate requests every second.
+func PerSecond(rate int64) Limit {
+	return Limit{Rate: rate, Period: time.Second}
+}
+
+// PerMinute returns a limit of rate requests every minute.
+func PerMinute(rate int64) Limit {
+	return Limit{Rate: rate, Period: time.Minute}
+}
+
+// PerHour returns a limit of rate requests every hour.
+func PerHour(rate int64) Limit {
+	return Limit{Rate: rate, Period: time.Hour}
+}
+
+// burst returns the capacity of the token bucket.
+func (l Limit) burst() int64 {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// perSecond returns the number of tokens refilled every second.
+func (l Limit) perSecond() float64 {
+	return float64(l.Rate) / l.Period.Seconds()
+}
+
+// Result is the result of a rate limited request.
+type Result struct {
+	// Limit is the number of requests allowed every period, or the capacity of the token bucket.
+	Limit int64
+	// Allowed reports whether the request is allowed.
+	Allowed bool
+	// Remaining is the number of requests allowed until the limit is reached.
+	Remaining int64
+	// ResetAfter is the duration to wait before retrying when the request is not allowed,
+	// or the duration until the limit is fully reset otherwise.
+	ResetAfter time.Duration
+}
+
+// RetryAfter returns the duration to wait before retrying, zero when the request is allowed.
+func (r Result) RetryAfter() time.Duration {
+	if r.Allowed {
+		return 0
+	}
+	return r.ResetAfter
+}
+
+// SetHeaders sets the rate limit headers of a response,
+// including Retry-After when the request is not allowed.
+// Durations are rounded up to seconds.
+func SetHeaders(header http.Header, res Result) {
+	header.Set(HeaderXRateLimitLimit, strconv.FormatInt(res.Limit, 10))
+	header.Set(HeaderXRateLimitRemaining, strconv.FormatInt(res.Remaining, 10))
+	header.Set(HeaderXRateLimitReset, strconv.FormatInt(seconds(res.ResetAfter), 10))
+	if !res.Allowed {
+		header.Set(HeaderRetryAfter, strconv.FormatInt(seconds(res.RetryAfter()), 10))
+	}
+}
+
+// seconds returns the duration in seconds, rounded up.
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// Limiter limits the rate of requests by key, e.g. by user or client IP.
+type Limiter interface {
+	// Allow counts a request of the key and reports whether it is allowed.
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Config configuration.
+type Config struct {
+	Algorithm Algorithm
+	Limit     Limit
+	// Prefix is prepended to every key,
+	// so the limiters with different limits don't share the same keys.
+	Prefix string
+}
+
+// validate validates the configuration.
+func (c Config) validate() error {
+	switch {
+	case c.Algorithm < FixedWindow || c.Algorithm > TokenBucket:
+		return errorx.E("unknown algorithm", errorx.CodeConfig)
+	case c.Limit.Rate <= 0:
+		return errorx.E("rate must be greater than zero", errorx.CodeConfig)
+	case c.Limit.Period <= 0:
+		return errorx.E("period must be greater than zero", errorx.CodeConfig)
+	case c.Limit.Burst < 0:
+		return errorx.E("burst must not be negative", errorx.CodeConfig)
+	}
+	return nil
+}
+
+// limit returns the limit reported in the result.
+func (c Config) limit() int64 {
+	if c.Algorithm == TokenBucket {
+		return c.Limit.burst()
+	}
+	return c.Limit.Rate
+}
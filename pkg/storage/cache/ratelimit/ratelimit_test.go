@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		res  Result
+		want map[string]string
+	}{
+		{
+			name: "Allowed",
+			res:  Result{Limit: 10, Allowed: true, Remaining: 9, ResetAfter: 1500 * time.Millisecond},
+			want: map[string]string{
+				HeaderXRateLimitLimit:     "10",
+				HeaderXRateLimitRemaining: "9",
+				HeaderXRateLimitReset:     "2",
+			},
+		},
+		{
+			name: "Not allowed",
+			res:  Result{Limit: 10, Allowed: false, Remaining: 0, ResetAfter: 200 * time.Millisecond},
+			want: map[string]string{
+				HeaderXRateLimitLimit:     "10",
+				HeaderXRateLimitRemaining: "0",
+				HeaderXRateLimitReset:     "1",
+				HeaderRetryAfter:          "1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := http.Header{}
+			SetHeaders(got, tt.res)
+			assert.Len(t, got, len(tt.want))
+			for k, v := range tt.want {
+				assert.Equal(t, v, got.Get(k), k)
+			}
+		})
+	}
+}
+
+func TestConfig_validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Unknown algorithm",
+			config:   Config{Algorithm: Algorithm(-1), Limit: PerSecond(1)},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:     "Zero rate",
+			config:   Config{Algorithm: FixedWindow, Limit: PerSecond(0)},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:     "Zero period",
+			config:   Config{Algorithm: FixedWindow, Limit: Limit{Rate: 1}},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:     "Negative burst",
+			config:   Config{Algorithm: TokenBucket, Limit: Limit{Rate: 1, Period: time.Second, Burst: -1}},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:     "Valid",
+			config:   Config{Algorithm: TokenBucket, Limit: PerMinute(60)},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, errorx.GetCode(tt.config.validate()))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/segmentio/ksuid"
+)
+
+// RedisLimiter is a distributed limiter using the rate limit scripts of the cache package,
+// every request is counted atomically by a single script run.
+// It works with every redis client able to run scripts, including the cluster clients.
+type RedisLimiter struct {
+	scripter cache.ScripterItf
+	config   Config
+	now      func() time.Time
+}
+
+// NewRedisLimiter returns a distributed limiter.
+func NewRedisLimiter(scripter cache.ScripterItf, config Config) (*RedisLimiter, error) {
+	const op errorx.Op = "ratelimit.NewRedisLimiter"
+
+	if err := config.validate(); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return &RedisLimiter{
+		scripter: scripter,
+		config:   config,
+		now:      time.Now,
+	}, nil
+}
+
+// Allow counts a request of the key and reports whether it is allowed.
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	const op errorx.Op = "ratelimit/RedisLimiter.Allow"
+
+	var (
+		keys   = []string{l.config.Prefix + key}
+		limit  = l.config.Limit
+		period = limit.Period.Milliseconds()
+		now    = l.now().UnixNano() / int64(time.Millisecond)
+
+		res interface{}
+		err error
+	)
+	switch l.config.Algorithm {
+	case FixedWindow:
+		res, err = l.scripter.RunScript(ctx, cache.ScriptRateLimitFixedWindow, keys, limit.Rate, period)
+	case SlidingWindow:
+		res, err = l.scripter.RunScript(ctx, cache.ScriptRateLimitSlidingLog, keys, limit.Rate, period, now, ksuid.New().String())
+	case TokenBucket:
+		rate := strconv.FormatFloat(limit.perSecond(), 'f', -1, 64)
+		res, err = l.scripter.RunScript(ctx, cache.ScriptRateLimitTokenBucket, keys, limit.burst(), rate, now, 1)
+	}
+	if err != nil {
+		return Result{}, errorx.E(err, op)
+	}
+
+	reply, ok := res.([]interface{})
+	if !ok || len(reply) != 3 {
+		return Result{}, errorx.E("unexpected reply type", op, errorx.CodeGateway)
+	}
+
+	values := make([]int64, len(reply))
+	for i, v := range reply {
+		if values[i], ok = v.(int64); !ok {
+			return Result{}, errorx.E("unexpected reply type", op, errorx.CodeGateway)
+		}
+	}
+
+	return Result{
+		Limit:      l.config.limit(),
+		Allowed:    values[0] == 1,
+		Remaining:  values[1],
+		ResetAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
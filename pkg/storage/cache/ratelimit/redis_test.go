@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/peractio/gdk/pkg/storage/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisLimiter_Allow(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name     string
+		config   Config
+		mock     func(scripter *cache.MockScripterItf)
+		want     Result
+		wantCode errorx.Code
+	}{
+		{
+			name:   "Fixed window",
+			config: Config{Algorithm: FixedWindow, Limit: PerMinute(10), Prefix: "rl:"},
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().RunScript(gomock.Any(), cache.ScriptRateLimitFixedWindow, []string{"rl:key"}, int64(10), int64(60000)).
+					Return([]interface{}{int64(1), int64(9), int64(60000)}, nil)
+			},
+			want:     Result{Limit: 10, Allowed: true, Remaining: 9, ResetAfter: time.Minute},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:   "Sliding window",
+			config: Config{Algorithm: SlidingWindow, Limit: PerSecond(10)},
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().RunScript(gomock.Any(), cache.ScriptRateLimitSlidingLog, []string{"key"}, int64(10), int64(1000), int64(1000000), gomock.Any()).
+					Return([]interface{}{int64(0), int64(0), int64(300)}, nil)
+			},
+			want:     Result{Limit: 10, Allowed: false, Remaining: 0, ResetAfter: 300 * time.Millisecond},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:   "Token bucket",
+			config: Config{Algorithm: TokenBucket, Limit: Limit{Rate: 1, Period: 2 * time.Second, Burst: 5}},
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().RunScript(gomock.Any(), cache.ScriptRateLimitTokenBucket, []string{"key"}, int64(5), "0.5", int64(1000000), 1).
+					Return([]interface{}{int64(1), int64(4), int64(2000)}, nil)
+			},
+			want:     Result{Limit: 5, Allowed: true, Remaining: 4, ResetAfter: 2 * time.Second},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:   "Redis error",
+			config: Config{Algorithm: FixedWindow, Limit: PerSecond(10)},
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().RunScript(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errorx.E("connection refused", errorx.CodeGateway))
+			},
+			want:     Result{},
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name:   "Unexpected reply",
+			config: Config{Algorithm: FixedWindow, Limit: PerSecond(10)},
+			mock: func(scripter *cache.MockScripterItf) {
+				scripter.EXPECT().RunScript(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]interface{}{int64(1), "9"}, nil)
+			},
+			want:     Result{},
+			wantCode: errorx.CodeGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scripter := cache.NewMockScripterItf(ctrl)
+			tt.mock(scripter)
+
+			limiter, err := NewRedisLimiter(scripter, tt.config)
+			assert.NoError(t, err)
+			limiter.now = func() time.Time { return now }
+
+			got, err := limiter.Allow(context.Background(), "key")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedisLimiter_MemoryRedis(t *testing.T) {
+	for _, algorithm := range []Algorithm{FixedWindow, SlidingWindow, TokenBucket} {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			clock := cachetest.NewFakeClock(time.Unix(1000, 0))
+			redis := cache.NewMemoryRedisWithConfig(cache.MemoryRedisConfig{Now: clock.Now})
+
+			limiter, err := NewRedisLimiter(redis, Config{Algorithm: algorithm, Limit: PerSecond(2)})
+			assert.NoError(t, err)
+			limiter.now = clock.Now
+
+			// The third request of the period is denied, until the limit is reset.
+			for i, want := range []bool{true, true, false} {
+				got, err := limiter.Allow(context.Background(), "key")
+				assert.NoError(t, err)
+				assert.Equal(t, want, got.Allowed, "request %d", i)
+			}
+
+			clock.Advance(time.Second)
+			got, err := limiter.Allow(context.Background(), "key")
+			assert.NoError(t, err)
+			assert.True(t, got.Allowed)
+		})
+	}
+}
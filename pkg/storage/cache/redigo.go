@@ -2,91 +2,520 @@ package cache
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
+	"github.com/mna/redisc"
 	"github.com/peractio/gdk/pkg/errorx/v2"
 	"github.com/peractio/gdk/pkg/resync"
 )
 
+// Retries of the commands redirected by the cluster.
+const (
+	redigoClusterAttempts      = 3
+	redigoClusterTryAgainDelay = 100 * time.Millisecond
+)
+
 var (
-	onceNewRedigo    resync.Once
-	onceNewRedigoRes *Redigo
-	onceNewRedigoErr error
+	onceNewRedigo           resync.Once
+	onceNewRedigoRes        *Redigo
+	onceNewRedigoErr        error
+	onceNewRedigoCluster    resync.Once
+	onceNewRedigoClusterRes *Redigo
+	onceNewRedigoClusterErr error
 )
 
-// Redigo returns a redis client using redigo library.
+// Redigo returns a redis client using redigo library,
+// connected to a single instance, a sentinel monitored master, or a cluster.
+// The operations honour the deadline and the cancellation of their context,
+// the interrupted operations are reported as errors wrapping the error of the context.
 type Redigo struct {
-	client *redis.Pool
+	client redigoPool
+	// cluster is the cluster client, nil when connected to a single instance.
+	cluster *redisc.Cluster
+	// layout is the master of every slot of the cluster, nil when connected to a single instance.
+	layout *redigoLayout
+	// readTimeout is the read timeout of the connections, extended by the blocking commands.
+	readTimeout time.Duration
+	backoff     subscribeBackoff
+}
+
+// redigoPool is a pool of connections, to a single instance or to a cluster.
+type redigoPool interface {
+	Get() redis.Conn
+	Close() error
 }
 
-// NewRedigo return a redis client.
+// NewRedigo returns the redis client shared by the package.
+// The client is created on the first call, the later calls return it whatever their configuration.
+// Use NewRedigoInstance or a Registry to hold several clients.
 func NewRedigo(config *RedisConfiguration) (*Redigo, error) {
 	onceNewRedigo.Do(func() {
-		const op errorx.Op = "cache.NewRedigo"
+		onceNewRedigoRes, onceNewRedigoErr = NewRedigoInstance(config)
+	})
 
-		// Default configuration for max active and wait.
-		if config.OpenConnectionLimit == 0 &&
-			!config.WaitOpenConnection {
-			config.OpenConnectionLimit = 5
-			config.WaitOpenConnection = true
-		}
+	return onceNewRedigoRes, onceNewRedigoErr
+}
 
-		if len(config.Addresses) == 0 {
-			onceNewRedigoErr = errorx.E("missing address", op, errorx.CodeConfig)
-			return
+// NewRedigoInstance returns a new redis client connected to a single instance,
+// or to the master monitored by the sentinels when the master name is set.
+func NewRedigoInstance(config *RedisConfiguration) (*Redigo, error) {
+	const op errorx.Op = "cache.NewRedigo"
+
+	// Default configuration for max active and wait.
+	if config.OpenConnectionLimit == 0 &&
+		!config.WaitOpenConnection {
+		config.OpenConnectionLimit = 5
+		config.WaitOpenConnection = true
+	}
+
+	if len(config.Addresses) == 0 {
+		return nil, errorx.E("missing address", op, errorx.CodeConfig)
+	}
+
+	// Create connection client, to the master monitored by the sentinels when the master name is set.
+	options := redigoDialOptions(config)
+	dial := func() (redis.Conn, error) {
+		return redis.Dial("tcp", config.Addresses[0], options...)
+	}
+	testOnBorrow := redigoTestOnBorrow
+	if config.MasterName != "" {
+		dial = func() (redis.Conn, error) {
+			return dialSentinelMaster(config, options)
 		}
+		testOnBorrow = redigoTestOnBorrowMaster
+	}
+	client := newRedigoPool(config, dial, testOnBorrow)
 
-		// Create connection client.
-		client := &redis.Pool{
-			Dial: func() (redis.Conn, error) {
-				return redis.Dial("tcp", config.Addresses[0])
-			},
-			DialContext: nil,
-			TestOnBorrow: func(c redis.Conn, t time.Time) error {
-				if time.Since(t) < time.Second {
-					return nil
-				}
-				_, err := c.Do("PING")
-				return err
-			},
-			MaxIdle:         int(config.IdleConnectionLimit),
-			MaxActive:       int(config.OpenConnectionLimit),
-			IdleTimeout:     0,
-			Wait:            config.WaitOpenConnection,
-			MaxConnLifetime: 0,
+	// Try to dial the redis.
+	// On error close previous open connection client.
+	if _, err := client.Dial(); err != nil {
+		_ = client.Close()
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return &Redigo{
+		client:      client,
+		readTimeout: time.Duration(config.ReadTimeout) * time.Second,
+		backoff:     newSubscribeBackoff(config),
+	}, nil
+}
+
+// NewRedigoCluster returns the redis cluster client shared by the package.
+// The client is created on the first call, the later calls return it whatever their configuration.
+// Use NewRedigoClusterInstance or a Registry to hold several clients.
+func NewRedigoCluster(config *RedisConfiguration) (*Redigo, error) {
+	onceNewRedigoCluster.Do(func() {
+		onceNewRedigoClusterRes, onceNewRedigoClusterErr = NewRedigoClusterInstance(config)
+	})
+
+	return onceNewRedigoClusterRes, onceNewRedigoClusterErr
+}
+
+// NewRedigoClusterInstance returns a new redis cluster client.
+// The commands are sent to the master owning the slot of their key, and follow the redirections of the cluster.
+// The connection limits apply to every node.
+func NewRedigoClusterInstance(config *RedisConfiguration) (*Redigo, error) {
+	const op errorx.Op = "cache.NewRedigoCluster"
+
+	// Default configuration for max active and wait.
+	if config.OpenConnectionLimit == 0 &&
+		!config.WaitOpenConnection {
+		config.OpenConnectionLimit = 5
+		config.WaitOpenConnection = true
+	}
+
+	if len(config.Addresses) == 0 {
+		return nil, errorx.E("missing address", op, errorx.CodeConfig)
+	}
+	if config.MasterName != "" {
+		return nil, errorx.E("sentinel isn't supported by the cluster client", op, errorx.CodeConfig)
+	}
+	if config.DB != 0 {
+		return nil, errorx.E("cluster only supports the database 0", op, errorx.CodeConfig)
+	}
+
+	layout := &redigoLayout{}
+	cluster := &redisc.Cluster{
+		StartupNodes: config.Addresses,
+		DialOptions:  redigoDialOptions(config),
+		CreatePool: func(address string, options ...redis.DialOption) (*redis.Pool, error) {
+			return newRedigoPool(config, func() (redis.Conn, error) {
+				return redis.Dial("tcp", address, options...)
+			}, redigoTestOnBorrow), nil
+		},
+		PoolWaitTime:  0,
+		BgError:       nil,
+		LayoutRefresh: layout.refresh,
+	}
+
+	// Load the slots of the nodes, so the first commands are sent to the right node.
+	if err := cluster.Refresh(); err != nil {
+		_ = cluster.Close()
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return &Redigo{
+		client:      cluster,
+		cluster:     cluster,
+		layout:      layout,
+		readTimeout: time.Duration(config.ReadTimeout) * time.Second,
+		backoff:     newSubscribeBackoff(config),
+	}, nil
+}
+
+// newRedigoPool returns a pool of the connections created by dial.
+func newRedigoPool(
+	config *RedisConfiguration,
+	dial func() (redis.Conn, error),
+	testOnBorrow func(c redis.Conn, t time.Time) error,
+) *redis.Pool {
+	return &redis.Pool{
+		Dial:            dial,
+		DialContext:     nil,
+		TestOnBorrow:    testOnBorrow,
+		MaxIdle:         int(config.IdleConnectionLimit),
+		MaxActive:       int(config.OpenConnectionLimit),
+		IdleTimeout:     time.Duration(config.IdleTimeout) * time.Second,
+		Wait:            config.WaitOpenConnection,
+		MaxConnLifetime: time.Duration(config.MaxConnAge) * time.Second,
+	}
+}
+
+// redigoDialOptions returns the options of the new connections.
+func redigoDialOptions(config *RedisConfiguration) []redis.DialOption {
+	options := []redis.DialOption{
+		redis.DialUsername(config.Username),
+		redis.DialPassword(config.Password),
+		redis.DialDatabase(int(config.DB)),
+		redis.DialReadTimeout(time.Duration(config.ReadTimeout) * time.Second),
+		redis.DialWriteTimeout(time.Duration(config.WriteTimeout) * time.Second),
+	}
+	if config.DialTimeout > 0 {
+		options = append(options, redis.DialConnectTimeout(time.Duration(config.DialTimeout)*time.Second))
+	}
+	if config.TLSConfig != nil {
+		options = append(options, redis.DialUseTLS(true), redis.DialTLSConfig(config.TLSConfig))
+	}
+
+	return options
+}
+
+// dialSentinelMaster asks the sentinels for the address of the master, and connects to the master.
+// The sentinels are asked in order until one of them knows the master.
+func dialSentinelMaster(config *RedisConfiguration, options []redis.DialOption) (redis.Conn, error) {
+	sentinelConfig := *config
+	sentinelConfig.Username, sentinelConfig.Password, sentinelConfig.DB = "", config.SentinelPassword, 0
+	sentinelOptions := redigoDialOptions(&sentinelConfig)
+
+	err := errorx.E("missing sentinel address", errorx.CodeConfig)
+	for _, address := range config.Addresses {
+		var master []string
+		master, err = getSentinelMaster(address, config.MasterName, sentinelOptions)
+		if err != nil {
+			continue
 		}
 
-		// Try to dial the redis.
-		// On error close previous open connection client.
-		if _, err := client.Dial(); err != nil {
-			_ = client.Close()
-			onceNewRedigoErr = errorx.E(err, op, errorx.CodeGateway)
-			return
+		return redis.Dial("tcp", net.JoinHostPort(master[0], master[1]), options...)
+	}
+
+	return nil, err
+}
+
+// getSentinelMaster returns the host and port of the master known by the sentinel.
+func getSentinelMaster(address, masterName string, options []redis.DialOption) ([]string, error) {
+	sentinel, err := redis.Dial("tcp", address, options...)
+	if err != nil {
+		return nil, errorx.E(err, errorx.CodeGateway)
+	}
+	defer func() {
+		_ = sentinel.Close()
+	}()
+
+	master, err := redis.Strings(sentinel.Do("SENTINEL", "get-master-addr-by-name", masterName))
+	if err == redis.ErrNil || (err == nil && len(master) != 2) {
+		return nil, errorx.E(fmt.Errorf("master %s is unknown to sentinel %s", masterName, address), errorx.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errorx.E(err, errorx.CodeGateway)
+	}
+
+	return master, nil
+}
+
+// redigoTestOnBorrow pings the connections idle for more than a second.
+func redigoTestOnBorrow(c redis.Conn, t time.Time) error {
+	if time.Since(t) < time.Second {
+		return nil
+	}
+	_, err := c.Do("PING")
+	return err
+}
+
+// redigoTestOnBorrowMaster checks the connections idle for more than a second are still connected to a master,
+// so the connections to a master demoted by a failover are replaced.
+func redigoTestOnBorrowMaster(c redis.Conn, t time.Time) error {
+	if time.Since(t) < time.Second {
+		return nil
+	}
+
+	role, err := redis.Values(c.Do("ROLE"))
+	if err != nil {
+		return err
+	}
+	if len(role) == 0 {
+		return errorx.E("missing role", errorx.CodeGateway)
+	}
+	if name, _ := redis.String(role[0], nil); name != "master" {
+		return errorx.E(fmt.Errorf("connection to a %s", name), errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// get returns a pooled connection, waiting for a vacant connection until the context is done.
+// The error is reported by the methods of the returned connection.
+// In cluster mode, the connection is taken from the pool of a node on its first command,
+// and the wait isn't bounded by the context.
+func (r *Redigo) get(ctx context.Context) redis.Conn {
+	if pool, ok := r.client.(interface {
+		GetContext(ctx context.Context) (redis.Conn, error)
+	}); ok {
+		// The failed connection reports the error.
+		con, _ := pool.GetContext(ctx)
+		return con
+	}
+
+	return r.client.Get()
+}
+
+// conn returns a connection honouring the context for the commands using the keys.
+// In cluster mode, the connection is bound to the node owning the slot of the keys,
+// or of the first key of the first command, and Do follows the redirections of the cluster.
+func (r *Redigo) conn(ctx context.Context, keys ...string) redis.Conn {
+	con := r.get(ctx)
+	if r.cluster != nil && len(keys) > 0 {
+		// The keys of different slots are reported by redis on the first command.
+		_ = redisc.BindConn(con, keys...)
+	}
+
+	return &redigoConn{
+		Conn:        con,
+		ctx:         ctx,
+		cluster:     r.cluster,
+		keys:        keys,
+		readTimeout: r.readTimeout,
+	}
+}
+
+// eachMaster calls fn with a connection honouring the context to every master node in turn in cluster mode,
+// or with a single connection otherwise, until fn returns an error.
+func (r *Redigo) eachMaster(ctx context.Context, fn func(con redis.Conn) error) error {
+	if r.cluster != nil {
+		return r.cluster.EachNode(false, func(_ string, con redis.Conn) error {
+			return fn(&redigoConn{Conn: con, ctx: ctx, readTimeout: r.readTimeout})
+		})
+	}
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	return fn(con)
+}
+
+// isCluster reports whether the client is connected to a cluster.
+func (r *Redigo) isCluster() bool {
+	return r.cluster != nil
+}
+
+// slot returns the cluster hash slot of the key, or zero when connected to a single instance.
+func (r *Redigo) slot(key string) int {
+	if r.cluster == nil {
+		return 0
+	}
+
+	return Slot(key)
+}
+
+// redigoConn is a connection honouring the deadline and the cancellation of a context.
+// The commands aren't sent once the context is done, and their replies are awaited until the deadline at most.
+// The connection interrupted while awaiting a reply is broken, so the pool discards it when it is closed.
+type redigoConn struct {
+	redis.Conn
+	ctx context.Context
+	// cluster is the cluster whose redirections are followed by Do, nil to not follow them.
+	cluster *redisc.Cluster
+	// keys are bound again to the new connection after a redirection.
+	keys []string
+	// readTimeout is the read timeout of the connection, zero value means no timeout.
+	readTimeout time.Duration
+}
+
+// Do sends a command and returns its reply, within the read timeout of the connection.
+func (c *redigoConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	return c.DoWithTimeout(c.readTimeout, commandName, args...)
+}
+
+// DoWithTimeout sends a command and returns its reply, awaited up to the timeout or the deadline of the context,
+// whichever is earlier. Zero timeout means no timeout.
+// In cluster mode, the command is sent again to the node a key has moved to,
+// or to the node importing a moving key, and retried when the keys of a multi-key command are moving.
+func (c *redigoConn) DoWithTimeout(timeout time.Duration, commandName string, args ...interface{}) (interface{}, error) {
+	for attempt := 1; ; attempt++ {
+		reply, err := c.do(timeout, commandName, args...)
+		if c.cluster == nil || attempt >= redigoClusterAttempts {
+			return reply, err
 		}
 
-		onceNewRedigoRes = &Redigo{
-			client: client,
+		redir := redisc.ParseRedir(err)
+		switch {
+		case redisc.IsTryAgain(err):
+			if err := c.sleep(redigoClusterTryAgainDelay); err != nil {
+				return nil, err
+			}
+		case redir != nil && redir.Type == "ASK":
+			return c.ask(redir.Addr, timeout, commandName, args...)
+		case redir != nil:
+			// The slots of the cluster are updated by the redirection, so the new connection is bound to the new node.
+			_ = c.Conn.Close()
+			c.Conn = c.cluster.Get()
+			if len(c.keys) > 0 {
+				_ = redisc.BindConn(c.Conn, c.keys...)
+			}
+		default:
+			return reply, err
 		}
-	})
+	}
+}
 
-	return onceNewRedigoRes, onceNewRedigoErr
+// Receive returns the reply of a pipelined command, within the read timeout of the connection.
+func (c *redigoConn) Receive() (interface{}, error) {
+	return c.ReceiveWithTimeout(c.readTimeout)
+}
+
+// ReceiveWithTimeout returns the reply of a pipelined command, awaited up to the timeout or the deadline of the context,
+// whichever is earlier. Zero timeout means no timeout.
+// Once the context is done, only the replies already received are returned,
+// otherwise the connection is broken so closing it doesn't wait for the pending replies.
+func (c *redigoConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	timeout, err := c.timeout(timeout)
+	if err != nil {
+		timeout = time.Nanosecond
+	}
+
+	reply, err := redis.ReceiveWithTimeout(c.Conn, timeout)
+	return reply, c.contextErr(err)
+}
+
+// do sends a command and returns its reply, unless the context is done.
+func (c *redigoConn) do(timeout time.Duration, commandName string, args ...interface{}) (interface{}, error) {
+	timeout, err := c.timeout(timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+	return reply, c.contextErr(err)
+}
+
+// ask sends a command to the node importing the moving slot of its key, preceded by ASKING.
+func (c *redigoConn) ask(address string, timeout time.Duration, commandName string, args ...interface{}) (interface{}, error) {
+	con, err := redis.DialContext(c.ctx, "tcp", address, c.cluster.DialOptions...)
+	if err != nil {
+		return nil, c.contextErr(err)
+	}
+
+	asking := &redigoConn{Conn: con, ctx: c.ctx, readTimeout: c.readTimeout}
+	defer func() {
+		_ = asking.Close()
+	}()
+
+	if _, err := asking.do(timeout, "ASKING"); err != nil {
+		return nil, err
+	}
+
+	return asking.do(timeout, commandName, args...)
+}
+
+// sleep waits for the duration, unless the context is done.
+func (c *redigoConn) sleep(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// timeout returns the timeout shortened to the deadline of the context, or the error of the done context.
+// Zero timeout means no timeout.
+func (c *redigoConn) timeout(timeout time.Duration) (time.Duration, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	deadline, ok := c.ctx.Deadline()
+	if !ok {
+		return timeout, nil
+	}
+
+	left := time.Until(deadline)
+	if left <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+	if timeout <= 0 || left < timeout {
+		return left, nil
+	}
+
+	return timeout, nil
+}
+
+// contextErr returns the error of the context instead of the error of a command interrupted by the context.
+// The errors replied by redis are returned as is.
+func (c *redigoConn) contextErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(redis.Error); ok {
+		return err
+	}
+
+	if ctxErr := c.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if deadline, ok := c.ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+
+	return err
 }
 
 // Get gets the value from redis in []byte form.
-func (r *Redigo) Get(_ context.Context, key string) ([]byte, error) {
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) Get(ctx context.Context, key string) ([]byte, error) {
 	const op errorx.Op = "cache/Redigo.Get"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
 
 	const commandName = "GET"
 	data, err := redis.Bytes(con.Do(commandName, key))
-	if err != nil && err != redis.ErrNil {
-		return data, errorx.E(err, op, errorx.CodeGateway)
+	if err == redis.ErrNil {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
 	}
 
 	return data, nil
@@ -94,10 +523,10 @@ func (r *Redigo) Get(_ context.Context, key string) ([]byte, error) {
 
 // SimpleSet sets value to key in redis without any additional options.
 // Key doesn't have a TTL.
-func (r *Redigo) SimpleSet(_ context.Context, key, value string) error {
+func (r *Redigo) SimpleSet(ctx context.Context, key, value string) error {
 	const op errorx.Op = "cache/Redigo.SimpleSet"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -117,10 +546,10 @@ func (r *Redigo) SimpleSet(_ context.Context, key, value string) error {
 }
 
 // SetEX sets the value to a key with timeout in seconds.
-func (r *Redigo) SetEX(_ context.Context, key string, seconds int64, value string) error {
+func (r *Redigo) SetEX(ctx context.Context, key string, seconds int64, value string) error {
 	const op errorx.Op = "cache/Redigo.SetEX"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -142,14 +571,14 @@ func (r *Redigo) SetEX(_ context.Context, key string, seconds int64, value strin
 // SetNX sets a value to a key with specified timeouts.
 // SetNX returns false if the key exists.
 func (r *Redigo) SetNX(
-	_ context.Context,
+	ctx context.Context,
 	key string,
 	seconds int64,
 	value string,
 ) (bool, error) {
 	const op errorx.Op = "cache/Redigo.SetNX"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -168,16 +597,17 @@ func (r *Redigo) SetNX(
 }
 
 // HMGet gets a value of multiple fields from hash key.
-func (r *Redigo) HMGet(_ context.Context, key string, fields ...string) ([][]byte, error) {
+// Missing field is returned as a nil value.
+func (r *Redigo) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
 	const op errorx.Op = "cache/Redigo.HMGet"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
 
 	const commandName = "HMGET"
-	data, err := redis.ByteSlices(con.Do(commandName, key, fields))
+	data, err := redis.ByteSlices(con.Do(commandName, redis.Args{}.Add(key).AddFlat(fields)...))
 	if err != nil && err != redis.ErrNil {
 		return data, errorx.E(err, op, errorx.CodeGateway)
 	}
@@ -186,10 +616,10 @@ func (r *Redigo) HMGet(_ context.Context, key string, fields ...string) ([][]byt
 }
 
 // Exists checks whether the key exists in redis.
-func (r *Redigo) Exists(_ context.Context, key string) (bool, error) {
+func (r *Redigo) Exists(ctx context.Context, key string) (bool, error) {
 	const op errorx.Op = "cache/Redigo.Exists"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -207,10 +637,10 @@ func (r *Redigo) Exists(_ context.Context, key string) (bool, error) {
 }
 
 // Expire sets the ttl of a key to specified value in seconds.
-func (r *Redigo) Expire(_ context.Context, key string, seconds int64) (bool, error) {
+func (r *Redigo) Expire(ctx context.Context, key string, seconds int64) (bool, error) {
 	const op errorx.Op = "cache/Redigo.Expire"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -225,10 +655,10 @@ func (r *Redigo) Expire(_ context.Context, key string, seconds int64) (bool, err
 }
 
 // ExpireAt sets the ttl of a key to a certain timestamp.
-func (r *Redigo) ExpireAt(_ context.Context, key string, timestamp int64) (bool, error) {
+func (r *Redigo) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
 	const op errorx.Op = "cache/Redigo.ExpireAt"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -246,10 +676,10 @@ func (r *Redigo) ExpireAt(_ context.Context, key string, timestamp int64) (bool,
 }
 
 // Incr increments the integer value of a key by 1.
-func (r *Redigo) Incr(_ context.Context, key string) (int64, error) {
+func (r *Redigo) Incr(ctx context.Context, key string) (int64, error) {
 	const op errorx.Op = "cache/Redigo.Incr"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -264,10 +694,10 @@ func (r *Redigo) Incr(_ context.Context, key string) (int64, error) {
 }
 
 // Decr decrements the integer value of a key by 1.
-func (r *Redigo) Decr(_ context.Context, key string) (int64, error) {
+func (r *Redigo) Decr(ctx context.Context, key string) (int64, error) {
 	const op errorx.Op = "cache/Redigo.Decr"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -281,11 +711,29 @@ func (r *Redigo) Decr(_ context.Context, key string) (int64, error) {
 	return data, nil
 }
 
-// TTL gets the time to live of a key / expiry time.
-func (r *Redigo) TTL(_ context.Context, key string) (int64, error) {
+// IncrBy increments the integer value of a key by the given amount.
+func (r *Redigo) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	const op errorx.Op = "cache/Redigo.IncrBy"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "INCRBY"
+	data, err := redis.Int64(con.Do(commandName, key, by))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+func (r *Redigo) TTL(ctx context.Context, key string) (int64, error) {
 	const op errorx.Op = "cache/Redigo.TTL"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -300,28 +748,32 @@ func (r *Redigo) TTL(_ context.Context, key string) (int64, error) {
 }
 
 // HGet gets the value of a hash field.
-func (r *Redigo) HGet(_ context.Context, key, field string) ([]byte, error) {
+// Missing key or field is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) HGet(ctx context.Context, key, field string) ([]byte, error) {
 	const op errorx.Op = "cache/Redigo.HGet"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
 
 	const commandName = "HGET"
 	data, err := redis.Bytes(con.Do(commandName, key, field))
-	if err != nil && err != redis.ErrNil {
-		return data, errorx.E(err, op, errorx.CodeGateway)
+	if err == redis.ErrNil {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
 	}
 
 	return data, nil
 }
 
 // HExists determines if a hash field exists.
-func (r *Redigo) HExists(_ context.Context, key, field string) (bool, error) {
+func (r *Redigo) HExists(ctx context.Context, key, field string) (bool, error) {
 	const op errorx.Op = "cache/Redigo.HExists"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -337,12 +789,12 @@ func (r *Redigo) HExists(_ context.Context, key, field string) (bool, error) {
 
 // HGetAll gets all the fields and values in a hash.
 func (r *Redigo) HGetAll(
-	_ context.Context,
+	ctx context.Context,
 	key string,
 ) (map[string]string, error) {
 	const op errorx.Op = "cache/Redigo.HGetAll"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -358,12 +810,12 @@ func (r *Redigo) HGetAll(
 
 // HSet sets the string value of a hash field.
 func (r *Redigo) HSet(
-	_ context.Context,
+	ctx context.Context,
 	key, field, value string,
 ) (bool, error) {
 	const op errorx.Op = "cache/Redigo.HSet"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -384,10 +836,10 @@ func (r *Redigo) HSet(
 }
 
 // HKeys gets all the fields in a hash.
-func (r *Redigo) HKeys(_ context.Context, key string) ([]string, error) {
+func (r *Redigo) HKeys(ctx context.Context, key string) ([]string, error) {
 	const op errorx.Op = "cache/Redigo.HKeys"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -402,10 +854,10 @@ func (r *Redigo) HKeys(_ context.Context, key string) ([]string, error) {
 }
 
 // HDel deletes a hash field.
-func (r *Redigo) HDel(_ context.Context, key string, fields ...string) (int64, error) {
+func (r *Redigo) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
 	const op errorx.Op = "cache/Redigo.HDel"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -427,10 +879,15 @@ func (r *Redigo) HDel(_ context.Context, key string, fields ...string) (int64, e
 }
 
 // Del deletes a key.
-func (r *Redigo) Del(_ context.Context, key ...interface{}) (int64, error) {
+// Keys are grouped by the hash slot in cluster mode, since DEL can't span multiple slots.
+func (r *Redigo) Del(ctx context.Context, key ...interface{}) (int64, error) {
 	const op errorx.Op = "cache/Redigo.Del"
 
-	con := r.client.Get()
+	if r.cluster != nil {
+		return r.delBySlot(ctx, key...)
+	}
+
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -444,47 +901,105 @@ func (r *Redigo) Del(_ context.Context, key ...interface{}) (int64, error) {
 	return data, nil
 }
 
+// delBySlot deletes the keys grouped by the hash slot, since DEL can't span multiple slots in cluster mode.
+func (r *Redigo) delBySlot(ctx context.Context, key ...interface{}) (int64, error) {
+	const op errorx.Op = "cache/Redigo.Del"
+
+	slots := make(map[int][]interface{})
+	for _, v := range key {
+		stdKey := fmt.Sprint(v)
+		slots[Slot(stdKey)] = append(slots[Slot(stdKey)], stdKey)
+	}
+
+	p := r.Pipeline()
+	for _, keys := range slots {
+		p.Do("DEL", keys...)
+	}
+
+	cmds, err := p.Exec(ctx)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	var res int64
+	for _, cmd := range cmds {
+		data, err := cmd.Int64()
+		if err != nil {
+			return res, errorx.E(err, op)
+		}
+		res += data
+	}
+
+	return res, nil
+}
+
 // IncrByEx increments redis key by adding expired.
 func (r *Redigo) IncrByEx(
-	_ context.Context,
+	ctx context.Context,
 	key string,
 	by int64,
 	expires int64,
 ) (int64, error) {
 	const op errorx.Op = "cache/Redigo.IncrByEx"
 
-	con := r.client.Get()
+	res, err := r.RunScript(ctx, ScriptIncrByEx, []string{key}, by, expires)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	data, err := replyInt64(res)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (r *Redigo) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.LPush"
+
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
 
-	script := `
-		local result = redis.call("INCRBY", KEYS[1], ARGV[1])
-		if result then
-			redis.call("EXPIRE", KEYS[1], ARGV[2])
-			return result
-		end
-		return result
-	`
+	const commandName = "LPUSH"
+	data, err := redis.Int64(con.Do(commandName, redis.Args{}.Add(key).AddFlat(values)...))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (r *Redigo) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.RPush"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
 
-	const commandName = "EVAL"
-	result, err := redis.Int64(con.Do(commandName, script, 1, key, by, expires))
+	const commandName = "RPUSH"
+	data, err := redis.Int64(con.Do(commandName, redis.Args{}.Add(key).AddFlat(values)...))
 	if err != nil {
 		return 0, errorx.E(err, op, errorx.CodeGateway)
 	}
 
-	return result, nil
+	return data, nil
 }
 
 // LRange gets array range that we set using LPush between index Start and Stop.
 func (r *Redigo) LRange(
-	_ context.Context,
+	ctx context.Context,
 	key string,
 	start, stop int64,
 ) ([][]byte, error) {
 	const op errorx.Op = "cache/Redigo.LRange"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -503,10 +1018,10 @@ func (r *Redigo) LRange(
 }
 
 // Trim array value that we set using LPush between index start and stop.
-func (r *Redigo) LTrim(_ context.Context, key string, start, stop int64) error {
+func (r *Redigo) LTrim(ctx context.Context, key string, start, stop int64) error {
 	const op errorx.Op = "cache/Redigo.LTrim"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -524,12 +1039,30 @@ func (r *Redigo) LTrim(_ context.Context, key string, start, stop int64) error {
 	return nil
 }
 
+// LLen gets the length of a list.
+func (r *Redigo) LLen(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.LLen"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "LLEN"
+	data, err := redis.Int64(con.Do(commandName, key))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
 // SAdd add the specified members to the set stored at key.
 // It returns false if key and value combination exists.
-func (r *Redigo) SAdd(_ context.Context, key string, value ...string) (bool, error) {
+func (r *Redigo) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
 	const op errorx.Op = "cache/Redigo.SAdd"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
@@ -550,22 +1083,1086 @@ func (r *Redigo) SAdd(_ context.Context, key string, value ...string) (bool, err
 	return data > 0, nil
 }
 
-// Publish sends message to a topic and returns numbers of subscriber that receives the message.
-func (r *Redigo) Publish(_ context.Context, topic, message string) (int, error) {
-	const op errorx.Op = "cache/Redigo.Publish"
+// SRem removes the members from a set and returns the number of removed members.
+func (r *Redigo) SRem(ctx context.Context, key string, value ...string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.SRem"
 
-	con := r.client.Get()
+	con := r.conn(ctx)
 	defer func() {
 		_ = con.Close()
 	}()
 
-	const commandName = "PUBLISH"
-	res, err := redis.Int(con.Do(commandName, topic, message))
+	const commandName = "SREM"
+	data, err := redis.Int64(con.Do(commandName, redis.Args{}.Add(key).AddFlat(value)...))
 	if err != nil {
 		return 0, errorx.E(err, op, errorx.CodeGateway)
 	}
 
-	return res, nil
+	return data, nil
+}
+
+// SMembers gets all the members of a set.
+func (r *Redigo) SMembers(ctx context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/Redigo.SMembers"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "SMEMBERS"
+	data, err := redis.Strings(con.Do(commandName, key))
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// SIsMember determines if a value is a member of a set.
+func (r *Redigo) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	const op errorx.Op = "cache/Redigo.SIsMember"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "SISMEMBER"
+	data, err := redis.Int64(con.Do(commandName, key, value))
+	if err != nil {
+		return false, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data == 1, nil
+}
+
+// SCard gets the number of members of a set.
+func (r *Redigo) SCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.SCard"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "SCARD"
+	data, err := redis.Int64(con.Do(commandName, key))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (r *Redigo) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	const op errorx.Op = "cache/Redigo.ZAdd"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	args := redis.Args{}.Add(key)
+	for _, v := range members {
+		args = args.Add(v.Score, v.Member)
+	}
+
+	const commandName = "ZADD"
+	data, err := redis.Int64(con.Do(commandName, args...))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (r *Redigo) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	const op errorx.Op = "cache/Redigo.ZIncrBy"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZINCRBY"
+	data, err := redis.Float64(con.Do(commandName, key, increment, member))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (r *Redigo) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/Redigo.ZRange"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZRANGE"
+	data, err := redis.Strings(con.Do(commandName, key, start, stop, "WITHSCORES"))
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res, err := toZMembers(data)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (r *Redigo) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/Redigo.ZRevRange"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZREVRANGE"
+	data, err := redis.Strings(con.Do(commandName, key, start, stop, "WITHSCORES"))
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res, err := toZMembers(data)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (r *Redigo) ZRangeByScore(
+	ctx context.Context,
+	key, min, max string,
+	offset, count int64,
+) ([]ZMember, error) {
+	const op errorx.Op = "cache/Redigo.ZRangeByScore"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	args := redis.Args{}.Add(key, min, max, "WITHSCORES")
+	if count, ok := zrangeLimit(offset, count); ok {
+		args = args.Add("LIMIT", offset, count)
+	}
+
+	const commandName = "ZRANGEBYSCORE"
+	data, err := redis.Strings(con.Do(commandName, args...))
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res, err := toZMembers(data)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) ZRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.ZRank"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZRANK"
+	data, err := redis.Int64(con.Do(commandName, key, member))
+	if err == redis.ErrNil {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.ZRevRank"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZREVRANK"
+	data, err := redis.Int64(con.Do(commandName, key, member))
+	if err == redis.ErrNil {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZScore gets the score of a sorted set member.
+// Missing key or member is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) ZScore(ctx context.Context, key, member string) (float64, error) {
+	const op errorx.Op = "cache/Redigo.ZScore"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZSCORE"
+	data, err := redis.Float64(con.Do(commandName, key, member))
+	if err == redis.ErrNil {
+		return 0, errorx.E("member not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZCard gets the number of members of a sorted set.
+func (r *Redigo) ZCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.ZCard"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZCARD"
+	data, err := redis.Int64(con.Do(commandName, key))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (r *Redigo) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.ZRem"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZREM"
+	data, err := redis.Int64(con.Do(commandName, redis.Args{}.Add(key).AddFlat(members)...))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (r *Redigo) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.ZRemRangeByScore"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "ZREMRANGEBYSCORE"
+	data, err := redis.Int64(con.Do(commandName, key, min, max))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return data, nil
+}
+
+// MGet gets the values of the keys in a single MGET command, or one per hash slot in cluster mode.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) MGet(ctx context.Context, keys ...string) ([]BatchResult, error) {
+	const op errorx.Op = "cache/Redigo.MGet"
+
+	res, err := batchMGet(ctx, r.Pipeline(), keys, r.slot)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// MSetEX sets the values of the keys with their timeout in seconds in a single round trip.
+func (r *Redigo) MSetEX(ctx context.Context, items ...BatchItem) ([]BatchResult, error) {
+	const op errorx.Op = "cache/Redigo.MSetEX"
+
+	res, err := batchSetEX(ctx, r.Pipeline(), items)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// MDel deletes the keys in a single round trip.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) MDel(ctx context.Context, keys ...string) ([]BatchResult, error) {
+	const op errorx.Op = "cache/Redigo.MDel"
+
+	res, err := batchDel(ctx, r.Pipeline(), keys)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// Pipeline returns a pipeline to queue arbitrary commands.
+// The commands are sent through a single connection in a single round trip.
+// In cluster mode, the commands are grouped by the master owning the slot of their first key,
+// and the groups are sent concurrently, in a single round trip per master.
+// Multi-key commands must only use keys of the same slot.
+func (r *Redigo) Pipeline() *Pipeline {
+	return newPipeline(r.execPipeline)
+}
+
+// execPipeline sends the commands of every node through a single connection and fills their replies.
+func (r *Redigo) execPipeline(ctx context.Context, cmds []*PipelineCmd) error {
+	const op errorx.Op = "cache/Redigo.execPipeline"
+
+	groups := groupPipelineCmds(cmds, r.pipelineGroup)
+	if len(groups) == 1 {
+		if err := r.execPipelineNode(ctx, groups[0]); err != nil {
+			return errorx.E(err, op, errorx.CodeGateway)
+		}
+		return nil
+	}
+
+	errs := make(chan error, len(groups))
+	for _, group := range groups {
+		go func(group []*PipelineCmd) {
+			errs <- r.execPipelineNode(ctx, group)
+		}(group)
+	}
+
+	var res error
+	for range groups {
+		if err := <-errs; err != nil && res == nil {
+			res = err
+		}
+	}
+	if res != nil {
+		return errorx.E(res, op, errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// execPipelineNode sends the commands of a node through a single connection and fills their replies.
+// In cluster mode, the commands redirected to other node, e.g. after a resharding, are sent again one by one.
+func (r *Redigo) execPipelineNode(ctx context.Context, cmds []*PipelineCmd) error {
+	const op errorx.Op = "cache/Redigo.execPipelineNode"
+
+	con := r.conn(ctx, pipelineCmdKeys(cmds[0])...)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	for _, cmd := range cmds {
+		commandName, _ := cmd.args[0].(string)
+		if err := con.Send(commandName, cmd.args[1:]...); err != nil {
+			return err
+		}
+	}
+
+	if err := con.Flush(); err != nil {
+		return err
+	}
+
+	for _, cmd := range cmds {
+		cmd.val, cmd.err = con.Receive()
+		if r.cluster != nil && redisc.ParseRedir(cmd.err) != nil {
+			cmd.val, cmd.err = r.execPipelineCmd(ctx, cmd)
+		}
+		if cmd.err != nil {
+			cmd.err = errorx.E(cmd.err, op, errorx.CodeGateway)
+		}
+	}
+
+	return nil
+}
+
+// execPipelineCmd sends a single command, following the redirections of the cluster.
+func (r *Redigo) execPipelineCmd(ctx context.Context, cmd *PipelineCmd) (interface{}, error) {
+	con := r.conn(ctx, pipelineCmdKeys(cmd)...)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	commandName, _ := cmd.args[0].(string)
+	return con.Do(commandName, cmd.args[1:]...)
+}
+
+// pipelineGroup returns the group of the pipelined commands of a key.
+// It is the address of the master owning the slot of the key in cluster mode,
+// or the slot itself when its master isn't known yet.
+// All the commands share a single group when connected to a single instance.
+func (r *Redigo) pipelineGroup(key string) string {
+	if r.layout == nil {
+		return ""
+	}
+
+	slot := Slot(key)
+	if master := r.layout.master(slot); master != "" {
+		return master
+	}
+
+	return fmt.Sprintf("slot:%d", slot)
+}
+
+// groupPipelineCmds groups the commands by the group of their first key, in the order of their first command.
+func groupPipelineCmds(cmds []*PipelineCmd, group func(key string) string) [][]*PipelineCmd {
+	var (
+		res    [][]*PipelineCmd
+		groups = make(map[string]int)
+	)
+	for _, cmd := range cmds {
+		name := ""
+		if keys := pipelineCmdKeys(cmd); len(keys) > 0 {
+			name = group(keys[0])
+		}
+
+		i, ok := groups[name]
+		if !ok {
+			i = len(res)
+			groups[name] = i
+			res = append(res, nil)
+		}
+		res[i] = append(res[i], cmd)
+	}
+
+	return res
+}
+
+// pipelineCmdKeys returns the first key of a command, used to route it in cluster mode.
+func pipelineCmdKeys(cmd *PipelineCmd) []string {
+	if len(cmd.args) < 2 {
+		return nil
+	}
+
+	return []string{fmt.Sprint(cmd.args[1])}
+}
+
+// redigoLayout is the master of every slot of a cluster, kept up to date by the cluster refreshes.
+type redigoLayout struct {
+	mu      sync.RWMutex
+	masters [redisc.HashSlots]string
+}
+
+// refresh updates the masters from the new mapping of the cluster, the first address of every slot.
+func (l *redigoLayout) refresh(_, mapping [redisc.HashSlots][]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for slot, nodes := range mapping {
+		l.masters[slot] = ""
+		if len(nodes) > 0 {
+			l.masters[slot] = nodes[0]
+		}
+	}
+}
+
+// master returns the address of the master owning the slot, or an empty string when it isn't known.
+func (l *redigoLayout) master(slot int) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.masters[slot]
+}
+
+// RunScript runs the script with EVALSHA, and loads the script when redis replies NOSCRIPT.
+// All the keys must share the same hash slot, see HashTag.
+func (r *Redigo) RunScript(
+	ctx context.Context,
+	script *Script,
+	keys []string,
+	args ...interface{},
+) (interface{}, error) {
+	const op errorx.Op = "cache/Redigo.RunScript"
+
+	if err := script.validate(keys); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	con := r.conn(ctx, keys...)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "EVALSHA"
+	evalArgs := append([]interface{}{script.Hash()}, script.args(keys, args)...)
+	res, err := con.Do(commandName, evalArgs...)
+	if isNoScript(err) {
+		if _, err := con.Do("SCRIPT", "LOAD", script.Source()); err != nil {
+			return nil, errorx.E(err, op, errorx.CodeGateway)
+		}
+		res, err = con.Do(commandName, evalArgs...)
+	}
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// LoadScripts loads the scripts into the script cache, of every master node in cluster mode,
+// ahead of their first run.
+func (r *Redigo) LoadScripts(ctx context.Context, scripts ...*Script) error {
+	const op errorx.Op = "cache/Redigo.LoadScripts"
+
+	const commandName = "SCRIPT"
+	err := r.eachMaster(ctx, func(con redis.Conn) error {
+		for _, v := range scripts {
+			if _, err := con.Do(commandName, "LOAD", v.Source()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// XAdd appends a message to a stream and returns the generated id.
+// The stream is trimmed to approximately maxLen messages, zero value means the stream isn't trimmed.
+func (r *Redigo) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]string) (string, error) {
+	const op errorx.Op = "cache/Redigo.XAdd"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	args := redis.Args{}.Add(stream)
+	if maxLen > 0 {
+		args = args.Add("MAXLEN", "~", maxLen)
+	}
+	args = append(args.Add("*"), streamValues(values)...)
+
+	const commandName = "XADD"
+	res, err := redis.String(con.Do(commandName, args...))
+	if err != nil {
+		return "", errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// XGroupCreate creates a consumer group reading the stream from the start id, e.g. "0" or "$".
+// The stream is created if it doesn't exist, and the existing group is left as is.
+func (r *Redigo) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	const op errorx.Op = "cache/Redigo.XGroupCreate"
+
+	con := r.conn(ctx, stream)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "XGROUP"
+	_, err := con.Do(commandName, "CREATE", stream, group, start, "MKSTREAM")
+	if err != nil && !isBusyGroup(err) {
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// XReadGroup reads up to count new messages for a consumer of a group,
+// waiting up to block for the messages to arrive, zero value doesn't wait.
+// The messages are pending until they are acknowledged.
+func (r *Redigo) XReadGroup(
+	ctx context.Context,
+	stream, group, consumer string,
+	count int64,
+	block time.Duration,
+) ([]StreamMessage, error) {
+	const op errorx.Op = "cache/Redigo.XReadGroup"
+
+	con := r.conn(ctx, stream)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	args := redis.Args{}.Add("GROUP", group, consumer, "COUNT", count)
+	if block > 0 {
+		args = args.Add("BLOCK", block.Milliseconds())
+	}
+	args = args.Add("STREAMS", stream, ">")
+
+	const commandName = "XREADGROUP"
+	timeout := r.readTimeout
+	if timeout > 0 && block > 0 {
+		timeout += block
+	}
+	reply, err := redis.DoWithTimeout(con, timeout, commandName, args...)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res, err := replyStreams(reply)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// XAck acknowledges the messages and returns the number of acknowledged messages.
+func (r *Redigo) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	const op errorx.Op = "cache/Redigo.XAck"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "XACK"
+	res, err := redis.Int64(con.Do(commandName, redis.Args{}.Add(stream, group).AddFlat(ids)...))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// XPending returns up to count pending messages of a group, the oldest first.
+func (r *Redigo) XPending(ctx context.Context, stream, group string, count int64) ([]PendingMessage, error) {
+	const op errorx.Op = "cache/Redigo.XPending"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "XPENDING"
+	reply, err := con.Do(commandName, stream, group, "-", "+", count)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res, err := replyPendingMessages(reply)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// XClaim transfers the pending messages idle for at least minIdle to a consumer,
+// and returns the claimed messages.
+// The message deleted from the stream is returned with nil values.
+func (r *Redigo) XClaim(
+	ctx context.Context,
+	stream, group, consumer string,
+	minIdle time.Duration,
+	ids ...string,
+) ([]StreamMessage, error) {
+	const op errorx.Op = "cache/Redigo.XClaim"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "XCLAIM"
+	args := redis.Args{}.Add(stream, group, consumer, minIdle.Milliseconds()).AddFlat(ids)
+	reply, err := con.Do(commandName, args...)
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	res, err := replyStreamMessages(reply)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// Dump returns the serialized value of a key with its remaining ttl,
+// zero ttl means the key has no expiry.
+// Missing key is reported as an error with errorx.CodeNotFound.
+func (r *Redigo) Dump(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	const op errorx.Op = "cache/Redigo.Dump"
+
+	con := r.conn(ctx, key)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	_ = con.Send("DUMP", key)
+	_ = con.Send("PTTL", key)
+	if err := con.Flush(); err != nil {
+		return nil, 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	value, err := redis.Bytes(con.Receive())
+	if err == redis.ErrNil {
+		return nil, 0, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+	if err != nil {
+		return nil, 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	// The negative replies of PTTL mean the key has no expiry or has just expired.
+	ttl, err := redis.Int64(con.Receive())
+	if err != nil {
+		return nil, 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	return value, time.Duration(ttl) * time.Millisecond, nil
+}
+
+// Restore creates a key from its serialized value with the ttl, zero ttl means no expiry.
+// Existing key is reported as an error with errorx.CodeConflict, unless it is replaced.
+func (r *Redigo) Restore(ctx context.Context, key string, ttl time.Duration, value []byte, replace bool) error {
+	const op errorx.Op = "cache/Redigo.Restore"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	args := redis.Args{}.Add(key, ttl.Milliseconds(), value)
+	if replace {
+		args = args.Add("REPLACE")
+	}
+
+	const commandName = "RESTORE"
+	if _, err := con.Do(commandName, args...); err != nil {
+		if isBusyKey(err) {
+			return errorx.E(err, op, errorx.CodeConflict)
+		}
+		return errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return nil
+}
+
+// ScanKeys iterates the keys matching the pattern with SCAN, count keys at a time,
+// and calls fn with every batch of keys until fn returns an error.
+// Every master is scanned in turn in cluster mode.
+func (r *Redigo) ScanKeys(ctx context.Context, match string, count int64, fn func(keys []string) error) error {
+	const op errorx.Op = "cache/Redigo.ScanKeys"
+
+	const commandName = "SCAN"
+	err := r.eachMaster(ctx, func(con redis.Conn) error {
+		cursor := int64(0)
+		for {
+			if err := ctx.Err(); err != nil {
+				return errorx.E(err, errorx.CodeGateway)
+			}
+
+			values, err := redis.Values(con.Do(commandName, scanArgs([]interface{}{cursor}, match, count)...))
+			if err != nil {
+				return errorx.E(err, errorx.CodeGateway)
+			}
+
+			var keys []string
+			if _, err := redis.Scan(values, &cursor, &keys); err != nil {
+				return errorx.E(err, errorx.CodeGateway)
+			}
+
+			if len(keys) > 0 {
+				if err := fn(keys); err != nil {
+					return err
+				}
+			}
+			if cursor == 0 {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// HScan iterates the fields of a hash matching the pattern with HSCAN, count fields at a time,
+// and calls fn with every batch of fields and their values until fn returns an error.
+func (r *Redigo) HScan(ctx context.Context, key, match string, count int64, fn func(fields map[string]string) error) error {
+	const op errorx.Op = "cache/Redigo.HScan"
+
+	const commandName = "HSCAN"
+	err := r.scan(ctx, commandName, key, match, count, func(values []string) error {
+		fields := make(map[string]string, len(values)/2)
+		for i := 0; i+1 < len(values); i += 2 {
+			fields[values[i]] = values[i+1]
+		}
+
+		return fn(fields)
+	})
+	if err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// SScan iterates the members of a set matching the pattern with SSCAN, count members at a time,
+// and calls fn with every batch of members until fn returns an error.
+func (r *Redigo) SScan(ctx context.Context, key, match string, count int64, fn func(members []string) error) error {
+	const op errorx.Op = "cache/Redigo.SScan"
+
+	const commandName = "SSCAN"
+	if err := r.scan(ctx, commandName, key, match, count, fn); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// scan iterates the elements of a key with the scan command, e.g. HSCAN,
+// and calls fn with every non-empty batch of elements until fn returns an error.
+func (r *Redigo) scan(ctx context.Context, commandName, key, match string, count int64, fn func(values []string) error) error {
+	con := r.conn(ctx, key)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	cursor := int64(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return errorx.E(err, errorx.CodeGateway)
+		}
+
+		values, err := redis.Values(con.Do(commandName, scanArgs([]interface{}{key, cursor}, match, count)...))
+		if err != nil {
+			return errorx.E(err, errorx.CodeGateway)
+		}
+
+		var elements []string
+		if _, err := redis.Scan(values, &cursor, &elements); err != nil {
+			return errorx.E(err, errorx.CodeGateway)
+		}
+
+		if len(elements) > 0 {
+			if err := fn(elements); err != nil {
+				return err
+			}
+		}
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// scanArgs appends the options of a scan command to args.
+// Empty match and non-positive count are left out, so redis uses its defaults as go-redis does.
+func scanArgs(args []interface{}, match string, count int64) []interface{} {
+	if match != "" {
+		args = append(args, "MATCH", match)
+	}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+
+	return args
+}
+
+// Publish sends message to a topic and returns numbers of subscriber that receives the message.
+func (r *Redigo) Publish(ctx context.Context, topic, message string) (int, error) {
+	const op errorx.Op = "cache/Redigo.Publish"
+
+	con := r.conn(ctx)
+	defer func() {
+		_ = con.Close()
+	}()
+
+	const commandName = "PUBLISH"
+	res, err := redis.Int(con.Do(commandName, topic, message))
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeGateway)
+	}
+
+	return res, nil
+}
+
+// Subscribe subscribes to the channels and dispatches the received messages to the handler.
+// It blocks until the context is done, the broken subscription is reconnected with backoff.
+// The subscription holds one connection from the pool until it ends.
+func (r *Redigo) Subscribe(ctx context.Context, handler MessageHandler, channels ...string) error {
+	const op errorx.Op = "cache/Redigo.Subscribe"
+
+	if len(channels) == 0 {
+		return errorx.E("missing channels", op, errorx.CodeInvalid)
+	}
+
+	sub := &subscription{
+		op:      op,
+		names:   channels,
+		handler: handler,
+		backoff: r.backoff,
+		session: r.subscribeSession(channels, nil),
+	}
+
+	return sub.run(ctx)
+}
+
+// PSubscribe subscribes to the channels matching the patterns,
+// and dispatches the received messages to the handler.
+// It blocks until the context is done, the broken subscription is reconnected with backoff.
+// The subscription holds one connection from the pool until it ends.
+func (r *Redigo) PSubscribe(ctx context.Context, handler MessageHandler, patterns ...string) error {
+	const op errorx.Op = "cache/Redigo.PSubscribe"
+
+	if len(patterns) == 0 {
+		return errorx.E("missing patterns", op, errorx.CodeInvalid)
+	}
+
+	sub := &subscription{
+		op:      op,
+		names:   patterns,
+		handler: handler,
+		backoff: r.backoff,
+		session: r.subscribeSession(nil, patterns),
+	}
+
+	return sub.run(ctx)
+}
+
+// redigoSubscribeHealthCheck is the interval between the pings of a subscription connection.
+// The connection is broken when nothing is received for twice the interval,
+// so a half-open connection is detected and the subscription reconnects.
+var redigoSubscribeHealthCheck = 30 * time.Second
+
+// subscribeSession returns a session subscribing to the channels and patterns on a pooled connection.
+func (r *Redigo) subscribeSession(channels, patterns []string) subscribeSession {
+	return func(ctx context.Context, ready func(), dispatch func(ctx context.Context, msg *Message)) error {
+		con := redis.PubSubConn{Conn: r.get(ctx)}
+		defer func() {
+			_ = con.Close()
+		}()
+
+		if len(channels) > 0 {
+			if err := con.Subscribe(toInterfaces(channels)...); err != nil {
+				return errorx.E(err, errorx.CodeGateway)
+			}
+		}
+		if len(patterns) > 0 {
+			if err := con.PSubscribe(toInterfaces(patterns)...); err != nil {
+				return errorx.E(err, errorx.CodeGateway)
+			}
+		}
+
+		interval := redigoSubscribeHealthCheck
+		done := make(chan error, 1)
+		go func() {
+			for {
+				switch v := con.ReceiveWithTimeout(2 * interval).(type) {
+				case redis.Message:
+					dispatch(ctx, &Message{Channel: v.Channel, Pattern: v.Pattern, Data: v.Data})
+				case redis.Subscription:
+					switch {
+					case v.Kind == "subscribe" || v.Kind == "psubscribe":
+						ready()
+					case v.Count == 0:
+						// All channels and patterns have been unsubscribed.
+						done <- nil
+						return
+					}
+				case error:
+					done <- errorx.E(v, errorx.CodeGateway)
+					return
+				}
+			}
+		}()
+
+		// The commands are only sent from this goroutine, while the replies are received by the other one.
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// Unsubscribe and wait until the receiver stops,
+				// the connection is closed if redis doesn't reply in time.
+				if len(channels) > 0 {
+					_ = con.Unsubscribe()
+				}
+				if len(patterns) > 0 {
+					_ = con.PUnsubscribe()
+				}
+				timer := time.NewTimer(time.Second)
+				defer timer.Stop()
+				select {
+				case <-done:
+				case <-timer.C:
+					_ = con.Close()
+					<-done
+				}
+				return nil
+			case err := <-done:
+				return err
+			case <-ticker.C:
+				// The pong keeps the receiver reading, a broken connection times it out.
+				if err := con.Ping(""); err != nil {
+					_ = con.Close()
+					<-done
+					return errorx.E(err, errorx.CodeGateway)
+				}
+			}
+		}
+	}
+}
+
+// PoolStats returns a snapshot of the stats of the connection pool, of all the nodes in cluster mode.
+func (r *Redigo) PoolStats() PoolStats {
+	var pools []redis.PoolStats
+	if r.cluster != nil {
+		for _, v := range r.cluster.Stats() {
+			pools = append(pools, v)
+		}
+	} else if pool, ok := r.client.(*redis.Pool); ok {
+		pools = append(pools, pool.Stats())
+	}
+
+	var res PoolStats
+	for _, v := range pools {
+		res.OpenConnections += v.ActiveCount
+		res.Idle += v.IdleCount
+		res.WaitCount += v.WaitCount
+		res.WaitDuration += v.WaitDuration
+	}
+	res.InUse = res.OpenConnections - res.Idle
+
+	return res
 }
 
 // Close closes the client, releasing any open resources.
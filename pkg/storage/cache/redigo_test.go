@@ -0,0 +1,435 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mna/redisc"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// roleConn is a connection replying the ROLE command.
+type roleConn struct {
+	redis.Conn
+	role interface{}
+	err  error
+}
+
+func (c roleConn) Do(string, ...interface{}) (interface{}, error) {
+	return c.role, c.err
+}
+
+func TestNewRedigoClusterInstance(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *RedisConfiguration
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Missing address",
+			config:   &RedisConfiguration{},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:     "Sentinel",
+			config:   &RedisConfiguration{Addresses: []string{"localhost:26379"}, MasterName: "master"},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:     "Database",
+			config:   &RedisConfiguration{Addresses: []string{"localhost:6379"}, DB: 1},
+			wantCode: errorx.CodeConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRedigoClusterInstance(tt.config)
+			assert.Nil(t, got)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+		})
+	}
+}
+
+// timeoutConn is a connection recording the timeout of the commands.
+type timeoutConn struct {
+	redis.Conn
+	reply interface{}
+	err   error
+	// wait waits for the timeout before replying.
+	wait    bool
+	calls   int
+	timeout time.Duration
+}
+
+func (c *timeoutConn) DoWithTimeout(timeout time.Duration, _ string, _ ...interface{}) (interface{}, error) {
+	c.calls++
+	c.timeout = timeout
+	if c.wait {
+		time.Sleep(timeout)
+	}
+	return c.reply, c.err
+}
+
+func (c *timeoutConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return c.DoWithTimeout(timeout, "")
+}
+
+func (c *timeoutConn) Close() error {
+	return nil
+}
+
+// timeoutPool is a pool of a single connection.
+type timeoutPool struct {
+	conn redis.Conn
+}
+
+func (p timeoutPool) Get() redis.Conn {
+	return p.conn
+}
+
+func (p timeoutPool) GetContext(context.Context) (redis.Conn, error) {
+	return p.conn, nil
+}
+
+func (p timeoutPool) Close() error {
+	return nil
+}
+
+// timeoutError is the error of a network operation timing out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestRedigoConn_Do(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	later, cancelLater := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLater()
+	soon, cancelSoon := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelSoon()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		readTimeout time.Duration
+		conn        *timeoutConn
+		wantErr     error
+		wantCalls   int
+		wantTimeout func(t *testing.T, timeout time.Duration)
+	}{
+		{
+			name:      "Cancelled context",
+			ctx:       cancelled,
+			conn:      &timeoutConn{reply: "OK"},
+			wantErr:   context.Canceled,
+			wantCalls: 0,
+		},
+		{
+			name:      "Expired deadline",
+			ctx:       expired,
+			conn:      &timeoutConn{reply: "OK"},
+			wantErr:   context.DeadlineExceeded,
+			wantCalls: 0,
+		},
+		{
+			name:        "Without deadline",
+			ctx:         context.Background(),
+			readTimeout: time.Second,
+			conn:        &timeoutConn{reply: "OK"},
+			wantCalls:   1,
+			wantTimeout: func(t *testing.T, timeout time.Duration) {
+				assert.Equal(t, time.Second, timeout)
+			},
+		},
+		{
+			name:        "Read timeout before the deadline",
+			ctx:         later,
+			readTimeout: time.Second,
+			conn:        &timeoutConn{reply: "OK"},
+			wantCalls:   1,
+			wantTimeout: func(t *testing.T, timeout time.Duration) {
+				assert.Equal(t, time.Second, timeout)
+			},
+		},
+		{
+			name:      "Deadline without read timeout",
+			ctx:       later,
+			conn:      &timeoutConn{reply: "OK"},
+			wantCalls: 1,
+			wantTimeout: func(t *testing.T, timeout time.Duration) {
+				assert.True(t, timeout > 59*time.Minute && timeout <= time.Hour)
+			},
+		},
+		{
+			name:        "Timed out at the deadline",
+			ctx:         soon,
+			readTimeout: time.Second,
+			conn:        &timeoutConn{err: timeoutError{}, wait: true},
+			wantErr:     context.DeadlineExceeded,
+			wantCalls:   1,
+			wantTimeout: func(t *testing.T, timeout time.Duration) {
+				assert.True(t, timeout <= 10*time.Millisecond)
+			},
+		},
+		{
+			name:      "Redis error",
+			ctx:       context.Background(),
+			conn:      &timeoutConn{err: redis.Error("ERR wrong number of arguments")},
+			wantErr:   redis.Error("ERR wrong number of arguments"),
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			con := &redigoConn{Conn: tt.conn, ctx: tt.ctx, readTimeout: tt.readTimeout}
+			reply, err := con.Do("GET", "key")
+			assert.Equal(t, tt.wantCalls, tt.conn.calls)
+			if tt.wantTimeout != nil {
+				tt.wantTimeout(t, tt.conn.timeout)
+			}
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "OK", reply)
+		})
+	}
+}
+
+func TestRedigoConn_ReceiveWithTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The replies already received are returned once the context is done, without waiting for the others.
+	conn := &timeoutConn{reply: "OK"}
+	con := &redigoConn{Conn: conn, ctx: ctx, readTimeout: time.Second}
+	reply, err := con.Receive()
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+	assert.Equal(t, time.Nanosecond, conn.timeout)
+
+	conn = &timeoutConn{err: timeoutError{}}
+	con = &redigoConn{Conn: conn, ctx: ctx, readTimeout: time.Second}
+	_, err = con.Receive()
+	assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+}
+
+func TestRedigo_Context(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	conn := &timeoutConn{reply: []byte("value")}
+	r := &Redigo{client: timeoutPool{conn: conn}}
+
+	_, err := r.Get(ctx, "key")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.True(t, errorx.Is(errorx.CodeGateway, err))
+	assert.Equal(t, 0, conn.calls)
+
+	got, err := r.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+}
+
+func TestRedigoTestOnBorrowMaster(t *testing.T) {
+	idle := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		conn    redis.Conn
+		t       time.Time
+		wantErr bool
+	}{
+		{
+			name:    "Recently used",
+			conn:    roleConn{err: errorx.E("connection reset")},
+			t:       time.Now(),
+			wantErr: false,
+		},
+		{
+			name:    "Master",
+			conn:    roleConn{role: []interface{}{[]byte("master"), int64(0), []interface{}{}}},
+			t:       idle,
+			wantErr: false,
+		},
+		{
+			name:    "Demoted master",
+			conn:    roleConn{role: []interface{}{[]byte("slave"), []byte("10.0.0.2"), int64(6379)}},
+			t:       idle,
+			wantErr: true,
+		},
+		{
+			name:    "Connection error",
+			conn:    roleConn{err: errorx.E("connection reset")},
+			t:       idle,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := redigoTestOnBorrowMaster(tt.conn, tt.t)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestScanArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		match string
+		count int64
+		want  []interface{}
+	}{
+		{name: "Default options", match: "", count: 0, want: []interface{}{"key", int64(0)}},
+		{name: "Match", match: "a*", count: -1, want: []interface{}{"key", int64(0), "MATCH", "a*"}},
+		{name: "Count", match: "", count: 5, want: []interface{}{"key", int64(0), "COUNT", int64(5)}},
+		{
+			name:  "Match and count",
+			match: "a*",
+			count: 5,
+			want:  []interface{}{"key", int64(0), "MATCH", "a*", "COUNT", int64(5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scanArgs([]interface{}{"key", int64(0)}, tt.match, tt.count))
+		})
+	}
+}
+
+func TestRedigo_pipelineGroup(t *testing.T) {
+	a, b := "{a}key", "{b}key"
+
+	var mapping [redisc.HashSlots][]string
+	mapping[Slot(a)] = []string{"10.0.0.1:6379", "10.0.0.3:6379"}
+	layout := &redigoLayout{}
+	layout.refresh(mapping, mapping)
+
+	single := &Redigo{}
+	assert.Equal(t, "", single.pipelineGroup(a))
+
+	cluster := &Redigo{layout: layout}
+	assert.Equal(t, "10.0.0.1:6379", cluster.pipelineGroup(a))
+	assert.Equal(t, fmt.Sprintf("slot:%d", Slot(b)), cluster.pipelineGroup(b))
+}
+
+func TestGroupPipelineCmds(t *testing.T) {
+	p := newPipeline(nil)
+	cmds := []*PipelineCmd{
+		p.Do("SET", "a1", "1"),
+		p.Do("SET", "b1", "1"),
+		p.Do("GET", "a2"),
+		p.Do("PING"),
+		p.Do("GET", "b2"),
+	}
+
+	nodes := map[string]string{"a1": "node-a", "a2": "node-a", "b1": "node-b", "b2": "node-b"}
+	got := groupPipelineCmds(cmds, func(key string) string {
+		return nodes[key]
+	})
+	assert.Equal(t, [][]*PipelineCmd{
+		{cmds[0], cmds[2]},
+		{cmds[1], cmds[4]},
+		{cmds[3]},
+	}, got)
+}
+
+// halfOpenConn is a subscription connection that stops receiving anything after the subscription.
+type halfOpenConn struct {
+	redis.Conn
+	mu       sync.Mutex
+	commands []string
+	replied  bool
+}
+
+func (c *halfOpenConn) Send(commandName string, _ ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commands = append(c.commands, commandName)
+	return nil
+}
+
+func (c *halfOpenConn) Flush() error {
+	return nil
+}
+
+func (c *halfOpenConn) DoWithTimeout(time.Duration, string, ...interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func (c *halfOpenConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	c.mu.Lock()
+	replied := c.replied
+	c.replied = true
+	c.mu.Unlock()
+
+	if !replied {
+		return []interface{}{[]byte("subscribe"), []byte("channel"), int64(1)}, nil
+	}
+
+	time.Sleep(timeout)
+	return nil, timeoutError{}
+}
+
+func (c *halfOpenConn) Close() error {
+	return nil
+}
+
+func (c *halfOpenConn) sent(commandName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, v := range c.commands {
+		if v == commandName {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRedigo_subscribeSession_HalfOpen(t *testing.T) {
+	interval := redigoSubscribeHealthCheck
+	redigoSubscribeHealthCheck = 20 * time.Millisecond
+	defer func() {
+		redigoSubscribeHealthCheck = interval
+	}()
+
+	conn := &halfOpenConn{}
+	r := &Redigo{client: timeoutPool{conn: conn}}
+
+	ready := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- r.subscribeSession([]string{"channel"}, nil)(context.Background(), func() {
+			ready <- struct{}{}
+		}, func(context.Context, *Message) {})
+	}()
+
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		t.Fatal("subscription isn't ready")
+	}
+
+	select {
+	case err := <-done:
+		// The session ends, so the subscription reconnects.
+		assert.True(t, errorx.Is(errorx.CodeGateway, err), "got %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("half-open connection isn't detected")
+	}
+	assert.True(t, conn.sent("PING"))
+}
@@ -0,0 +1,526 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync/atomic"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// MigrationMode selects the instances serving the reads and the writes of a RedisMigrator.
+// A migration usually goes through:
+//  1. MigrationModeShadowRead while Copy copies the existing keys, until the comparisons stop mismatching.
+//  2. MigrationModeDualWrite, the origin stays up to date so the migration can still be rolled back.
+//  3. MigrationModeCutover, then the origin can be dropped.
+type MigrationMode int32
+
+// Migration modes.
+const (
+	// MigrationModeFallback reads from the destination then the origin, creates to the destination,
+	// and updates both instances.
+	MigrationModeFallback MigrationMode = iota
+	// MigrationModeShadowRead reads and writes the origin, and writes the destination too.
+	// The destination reads are compared with the origin ones in the background.
+	MigrationModeShadowRead
+	// MigrationModeDualWrite reads from the destination then the origin, and writes both instances.
+	MigrationModeDualWrite
+	// MigrationModeCutover reads and writes the destination only.
+	MigrationModeCutover
+)
+
+// String returns the name of the mode.
+func (m MigrationMode) String() string {
+	switch m {
+	case MigrationModeFallback:
+		return "fallback"
+	case MigrationModeShadowRead:
+		return "shadow-read"
+	case MigrationModeDualWrite:
+		return "dual-write"
+	case MigrationModeCutover:
+		return "cutover"
+	default:
+		return "unknown"
+	}
+}
+
+// RedisMigratorConfig configuration.
+type RedisMigratorConfig struct {
+	// Mode is the initial migration mode.
+	Mode MigrationMode
+	// Backfill copies the keys read from the origin to the destination in the background,
+	// with their remaining ttl. Both clients must implement DumperItf.
+	Backfill bool
+	// Concurrency is the maximum number of background backfills and comparisons,
+	// the ones beyond it are dropped.
+	Concurrency int
+}
+
+// DefaultRedisMigratorConfig is the default configuration of the redis migrator.
+var DefaultRedisMigratorConfig = RedisMigratorConfig{
+	Mode:        MigrationModeFallback,
+	Backfill:    false,
+	Concurrency: 16,
+}
+
+// RedisMigratorStats are the counters of a redis migrator.
+// The origin is safe to drop once the destination serves all the reads:
+// Mismatches stops growing in the shadow-read mode, and OriginHits stops growing in the other modes.
+type RedisMigratorStats struct {
+	// DestinationHits is the number of reads found in the destination.
+	DestinationHits int64
+	// OriginHits is the number of reads missing from the destination and found in the origin.
+	OriginHits int64
+	// Backfills is the number of keys copied to the destination after a read.
+	Backfills int64
+	// BackfillErrors is the number of failed backfills.
+	BackfillErrors int64
+	// Comparisons is the number of shadow reads compared with the origin.
+	Comparisons int64
+	// Mismatches is the number of shadow reads different from the origin.
+	Mismatches int64
+	// ShadowErrors is the number of failed shadow reads.
+	ShadowErrors int64
+	// WriteErrors is the number of failed writes to the instance not returning the result.
+	WriteErrors int64
+	// Dropped is the number of backfills and comparisons dropped because of the concurrency limit.
+	Dropped int64
+	// Copied is the number of keys copied by Copy.
+	Copied int64
+	// CopySkipped is the number of keys skipped by Copy, because they already exist in the destination
+	// or have expired.
+	CopySkipped int64
+	// CopyErrors is the number of keys failed to be copied by Copy.
+	CopyErrors int64
+}
+
+// Stats returns a snapshot of the counters.
+func (r *RedisMigrator) Stats() RedisMigratorStats {
+	return RedisMigratorStats{
+		DestinationHits: atomic.LoadInt64(&r.stats.DestinationHits),
+		OriginHits:      atomic.LoadInt64(&r.stats.OriginHits),
+		Backfills:       atomic.LoadInt64(&r.stats.Backfills),
+		BackfillErrors:  atomic.LoadInt64(&r.stats.BackfillErrors),
+		Comparisons:     atomic.LoadInt64(&r.stats.Comparisons),
+		Mismatches:      atomic.LoadInt64(&r.stats.Mismatches),
+		ShadowErrors:    atomic.LoadInt64(&r.stats.ShadowErrors),
+		WriteErrors:     atomic.LoadInt64(&r.stats.WriteErrors),
+		Dropped:         atomic.LoadInt64(&r.stats.Dropped),
+		Copied:          atomic.LoadInt64(&r.stats.Copied),
+		CopySkipped:     atomic.LoadInt64(&r.stats.CopySkipped),
+		CopyErrors:      atomic.LoadInt64(&r.stats.CopyErrors),
+	}
+}
+
+// CopyConfig configuration.
+type CopyConfig struct {
+	// Match is the pattern of the copied keys.
+	Match string
+	// Count is the number of keys scanned at a time.
+	Count int64
+	// Progress is called after every batch of keys.
+	Progress func(progress CopyProgress)
+}
+
+// DefaultCopyConfig is the default configuration of Copy.
+var DefaultCopyConfig = CopyConfig{
+	Match:    "*",
+	Count:    100,
+	Progress: nil,
+}
+
+// CopyProgress is the progress of a copy.
+type CopyProgress struct {
+	Scanned int64
+	Copied  int64
+	Skipped int64
+	Failed  int64
+}
+
+// Copy copies the origin keys to the destination with their remaining ttl, scanning the origin with SCAN.
+// It runs until all the keys are scanned or the context is done, and is meant to be run in the background.
+// The origin client must implement ScannerItf, and both clients must implement DumperItf.
+// In the shadow-read mode the origin keys replace the destination ones,
+// otherwise the existing destination keys are newer and are skipped.
+// The failed keys are logged and counted, without stopping the copy.
+func (r *RedisMigrator) Copy(ctx context.Context, config CopyConfig) (CopyProgress, error) {
+	const op errorx.Op = "cache/RedisMigrator.Copy"
+
+	var progress CopyProgress
+
+	scanner, ok := r.origin.(ScannerItf)
+	if !ok {
+		return progress, errorx.E("copy requires the origin client to implement ScannerItf", op, errorx.CodeConfig)
+	}
+	if _, _, ok := r.dumpers(); !ok {
+		return progress, errorx.E("copy requires both clients to implement DumperItf", op, errorx.CodeConfig)
+	}
+
+	// Defaults
+	if config.Match == "" {
+		config.Match = DefaultCopyConfig.Match
+	}
+	if config.Count <= 0 {
+		config.Count = DefaultCopyConfig.Count
+	}
+
+	err := scanner.ScanKeys(ctx, config.Match, config.Count, func(keys []string) error {
+		for _, key := range keys {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			progress.Scanned++
+			copied, err := r.copyKey(ctx, key)
+			switch {
+			case err != nil:
+				progress.Failed++
+				atomic.AddInt64(&r.stats.CopyErrors, 1)
+				logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" key failed")
+			case copied:
+				progress.Copied++
+				atomic.AddInt64(&r.stats.Copied, 1)
+			default:
+				progress.Skipped++
+				atomic.AddInt64(&r.stats.CopySkipped, 1)
+			}
+		}
+
+		if config.Progress != nil {
+			config.Progress(progress)
+		}
+		return nil
+	})
+	if err != nil {
+		return progress, errorx.E(err, op)
+	}
+
+	return progress, nil
+}
+
+// read reads from the instances of the current mode, and returns the result found by fn.
+// Missing result in both instances is returned as the origin one,
+// whether it is a zero value or an error with errorx.CodeNotFound.
+func (r *RedisMigrator) read(
+	ctx context.Context,
+	op errorx.Op,
+	key string,
+	found func(res interface{}) bool,
+	fn func(client RedisItf) (interface{}, error),
+) (interface{}, error) {
+	switch r.Mode() {
+	case MigrationModeCutover:
+		res, err := fn(r.destination)
+		if err != nil {
+			return res, errorx.E(err, op)
+		}
+
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
+		return res, nil
+
+	case MigrationModeShadowRead:
+		res, err := fn(r.origin)
+		if err != nil && !errorx.Is(errorx.CodeNotFound, err) {
+			return res, errorx.E(err, op)
+		}
+
+		r.compare(ctx, op, key, err == nil && found(res), res, found, fn)
+		if err != nil {
+			return res, errorx.E(err, op)
+		}
+
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+		return res, nil
+	}
+
+	// Read from new client.
+	res, err := fn(r.destination)
+	if err == nil && found(res) {
+		atomic.AddInt64(&r.stats.DestinationHits, 1)
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
+		return res, nil
+	}
+
+	// Read from old client.
+	res, err = fn(r.origin)
+	if err != nil {
+		return res, errorx.E(err, op)
+	}
+	if found(res) {
+		atomic.AddInt64(&r.stats.OriginHits, 1)
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+		if r.config.Backfill {
+			r.async(ctx, func(ctx context.Context) {
+				r.backfill(ctx, op, key)
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// compare reads the destination in the background, and counts the mismatches with the origin result.
+// The mismatched key is backfilled from the origin.
+func (r *RedisMigrator) compare(
+	ctx context.Context,
+	op errorx.Op,
+	key string,
+	originFound bool,
+	origin interface{},
+	found func(res interface{}) bool,
+	fn func(client RedisItf) (interface{}, error),
+) {
+	r.async(ctx, func(ctx context.Context) {
+		res, err := fn(r.destination)
+		if err != nil && !errorx.Is(errorx.CodeNotFound, err) {
+			atomic.AddInt64(&r.stats.ShadowErrors, 1)
+			logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key, tags.Client: "destination"}),
+				string(op)+" shadow read failed")
+			return
+		}
+
+		atomic.AddInt64(&r.stats.Comparisons, 1)
+		destinationFound := err == nil && found(res)
+		if originFound == destinationFound && (!originFound || sameResult(origin, res)) {
+			return
+		}
+
+		atomic.AddInt64(&r.stats.Mismatches, 1)
+		logx.DBG(ctx, logx.KV{tags.Key: key}, string(op)+" shadow read mismatch")
+		if originFound && r.config.Backfill {
+			r.backfill(ctx, op, key)
+		}
+	})
+}
+
+// write writes to the instances of the current mode, and returns the result of the authoritative instance.
+// The creates are only written to the destination in the fallback mode.
+// In the shadow-read mode, the failure of the destination is logged and counted.
+// In the other modes, the reads fall back to the origin, so its failure is returned before the destination is written,
+// otherwise a key deleted from the destination only would be read again from the origin.
+func (r *RedisMigrator) write(
+	ctx context.Context,
+	op errorx.Op,
+	key interface{},
+	create bool,
+	fn func(client RedisItf) (interface{}, error),
+) (interface{}, error) {
+	mode := r.Mode()
+	if mode == MigrationModeShadowRead {
+		res, err := fn(r.origin)
+		if err != nil {
+			return res, errorx.E(err, op)
+		}
+
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+		r.mirror(ctx, op, key, "destination", fn)
+		return res, nil
+	}
+
+	// Update to old client.
+	if mode == MigrationModeDualWrite || (mode == MigrationModeFallback && !create) {
+		if _, err := fn(r.origin); err != nil {
+			return nil, errorx.E(err, op, errorx.Fields{tags.Key: key, tags.Client: "origin"})
+		}
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+	}
+
+	// Update to new client.
+	res, err := fn(r.destination)
+	if err != nil {
+		return res, errorx.E(err, op)
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
+	return res, nil
+}
+
+// count updates a counter on the instances of the current mode, and returns the destination result.
+// Until the cutover, the destination counter may not be migrated yet and would start from zero,
+// so the missing destination counter is copied from the origin before it is updated.
+// It requires both clients to implement DumperItf in the fallback and dual-write modes.
+func (r *RedisMigrator) count(
+	ctx context.Context,
+	op errorx.Op,
+	key string,
+	fn func(client RedisItf) (interface{}, error),
+) (interface{}, error) {
+	mode := r.Mode()
+	if mode != MigrationModeFallback && mode != MigrationModeDualWrite {
+		return r.write(ctx, op, key, false, fn)
+	}
+
+	if _, _, ok := r.dumpers(); !ok {
+		return nil, errorx.E("counters require both clients to implement DumperItf before the cutover", op,
+			errorx.CodeConfig, errorx.Fields{tags.Key: key})
+	}
+
+	exists, err := r.destination.Exists(ctx, key)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+	if !exists {
+		if _, err := r.copyKey(ctx, key); err != nil {
+			return nil, errorx.E(err, op)
+		}
+	}
+
+	return r.write(ctx, op, key, false, fn)
+}
+
+// mirror writes to the instance not returning the result, the failure is logged and counted.
+func (r *RedisMigrator) mirror(
+	ctx context.Context,
+	op errorx.Op,
+	key interface{},
+	name string,
+	fn func(client RedisItf) (interface{}, error),
+) {
+	client := r.origin
+	if name == "destination" {
+		client = r.destination
+	}
+
+	if _, err := fn(client); err != nil {
+		atomic.AddInt64(&r.stats.WriteErrors, 1)
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key, tags.Client: name}), string(op)+" write failed")
+		return
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: name}, string(op)+" success")
+}
+
+// backfill copies a key from the origin to the destination, the failure is logged and counted.
+func (r *RedisMigrator) backfill(ctx context.Context, op errorx.Op, key string) {
+	copied, err := r.copyKey(ctx, key)
+	if err != nil {
+		atomic.AddInt64(&r.stats.BackfillErrors, 1)
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: key}), string(op)+" backfill failed")
+		return
+	}
+	if copied {
+		atomic.AddInt64(&r.stats.Backfills, 1)
+		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" backfill success")
+	}
+}
+
+// copyKey copies a key from the origin to the destination with its remaining ttl,
+// and reports whether the key is copied.
+// The expired origin key and the existing destination key are skipped,
+// except in the shadow-read mode where the origin key replaces the destination one.
+func (r *RedisMigrator) copyKey(ctx context.Context, key string) (bool, error) {
+	origin, destination, ok := r.dumpers()
+	if !ok {
+		return false, errorx.E("clients don't implement DumperItf", errorx.CodeConfig)
+	}
+
+	value, ttl, err := origin.Dump(ctx, key)
+	if errorx.Is(errorx.CodeNotFound, err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = destination.Restore(ctx, key, ttl, value, r.Mode() == MigrationModeShadowRead)
+	if errorx.Is(errorx.CodeConflict, err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// dumpers returns the clients as DumperItf, and reports whether both clients implement it.
+func (r *RedisMigrator) dumpers() (origin, destination DumperItf, ok bool) {
+	origin, ok = r.origin.(DumperItf)
+	if !ok {
+		return nil, nil, false
+	}
+
+	destination, ok = r.destination.(DumperItf)
+	return origin, destination, ok
+}
+
+// async runs fn in a new goroutine holding a slot, or drops it when all the slots are busy.
+// The context of fn keeps the request id without the deadline of the request.
+func (r *RedisMigrator) async(ctx context.Context, fn func(ctx context.Context)) {
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		atomic.AddInt64(&r.stats.Dropped, 1)
+		return
+	}
+
+	actx := logx.SetRequestID(context.Background(), logx.GetRequestID(ctx))
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() { <-r.slots }()
+
+		fn(actx)
+	}()
+}
+
+// foundAlways reports any successful result as found,
+// the commands returning an error with errorx.CodeNotFound on a missing key.
+func foundAlways(interface{}) bool {
+	return true
+}
+
+// foundValue reports whether the result is found,
+// the commands returning a zero or empty value on a missing key.
+func foundValue(res interface{}) bool {
+	switch v := res.(type) {
+	case bool:
+		return v
+	case int64:
+		return v > 0
+	case [][]byte:
+		return hasValue(v)
+	case []string:
+		return len(v) > 0
+	case map[string]string:
+		return len(v) > 0
+	case []ZMember:
+		return len(v) > 0
+	default:
+		return res != nil
+	}
+}
+
+// hasValue reports whether any of the values is found.
+func hasValue(values [][]byte) bool {
+	for _, v := range values {
+		if v != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameResult reports whether the results of both instances are equal.
+// The unordered results, as the hash keys and the set members, are compared sorted.
+func sameResult(origin, destination interface{}) bool {
+	x, ok := origin.([]string)
+	if !ok {
+		return reflect.DeepEqual(origin, destination)
+	}
+
+	y, ok := destination.([]string)
+	if !ok || len(x) != len(y) {
+		return false
+	}
+
+	x, y = append([]string(nil), x...), append([]string(nil), y...)
+	sort.Strings(x)
+	sort.Strings(y)
+	return reflect.DeepEqual(x, y)
+}
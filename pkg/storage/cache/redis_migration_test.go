@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// migrationClient is a client implementing all the interfaces used by the migrator.
+type migrationClient struct {
+	*MockRedisItf
+	*MockDumperItf
+	*MockScannerItf
+}
+
+func newMigrationClient(ctrl *gomock.Controller) (*migrationClient, *MockRedisItf, *MockDumperItf, *MockScannerItf) {
+	redis, dumper, scanner := NewMockRedisItf(ctrl), NewMockDumperItf(ctrl), NewMockScannerItf(ctrl)
+	return &migrationClient{MockRedisItf: redis, MockDumperItf: dumper, MockScannerItf: scanner}, redis, dumper, scanner
+}
+
+func TestNewRedisMigratorInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client, _, _, _ := newMigrationClient(ctrl)
+
+	_, err := NewRedisMigratorInstance(NewMockRedisItf(ctrl), client, RedisMigratorConfig{Backfill: true})
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+
+	got, err := NewRedisMigratorInstance(client, client, RedisMigratorConfig{Mode: MigrationModeDualWrite, Backfill: true})
+	assert.NoError(t, err)
+	assert.Equal(t, MigrationModeDualWrite, got.Mode())
+	assert.Equal(t, DefaultRedisMigratorConfig.Concurrency, cap(got.slots))
+
+	got.SetMode(MigrationModeCutover)
+	assert.Equal(t, MigrationModeCutover, got.Mode())
+	assert.Equal(t, "cutover", got.Mode().String())
+}
+
+func TestRedisMigrator_read(t *testing.T) {
+	notFound := errorx.E("not found", errorx.CodeNotFound)
+
+	tests := []struct {
+		name      string
+		mode      MigrationMode
+		mock      func(origin, destination *MockRedisItf, originDumper, destinationDumper *MockDumperItf)
+		want      []byte
+		wantCode  errorx.Code
+		wantStats RedisMigratorStats
+	}{
+		{
+			name: "Fallback found in destination",
+			mode: MigrationModeFallback,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				destination.EXPECT().Get(gomock.Any(), "key").Return([]byte("new"), nil)
+			},
+			want:      []byte("new"),
+			wantStats: RedisMigratorStats{DestinationHits: 1},
+		},
+		{
+			name: "Dual-write found in origin is backfilled",
+			mode: MigrationModeDualWrite,
+			mock: func(origin, destination *MockRedisItf, originDumper, destinationDumper *MockDumperItf) {
+				destination.EXPECT().Get(gomock.Any(), "key").Return(nil, notFound)
+				origin.EXPECT().Get(gomock.Any(), "key").Return([]byte("old"), nil)
+				originDumper.EXPECT().Dump(gomock.Any(), "key").Return([]byte("dump"), time.Minute, nil)
+				destinationDumper.EXPECT().Restore(gomock.Any(), "key", time.Minute, []byte("dump"), false).Return(nil)
+			},
+			want:      []byte("old"),
+			wantStats: RedisMigratorStats{OriginHits: 1, Backfills: 1},
+		},
+		{
+			name: "Backfill skips the key written meanwhile",
+			mode: MigrationModeFallback,
+			mock: func(origin, destination *MockRedisItf, originDumper, destinationDumper *MockDumperItf) {
+				destination.EXPECT().Get(gomock.Any(), "key").Return(nil, notFound)
+				origin.EXPECT().Get(gomock.Any(), "key").Return([]byte("old"), nil)
+				originDumper.EXPECT().Dump(gomock.Any(), "key").Return([]byte("dump"), time.Duration(0), nil)
+				destinationDumper.EXPECT().Restore(gomock.Any(), "key", time.Duration(0), []byte("dump"), false).
+					Return(errorx.E("busy key", errorx.CodeConflict))
+			},
+			want:      []byte("old"),
+			wantStats: RedisMigratorStats{OriginHits: 1},
+		},
+		{
+			name: "Backfill error",
+			mode: MigrationModeFallback,
+			mock: func(origin, destination *MockRedisItf, originDumper, _ *MockDumperItf) {
+				destination.EXPECT().Get(gomock.Any(), "key").Return(nil, notFound)
+				origin.EXPECT().Get(gomock.Any(), "key").Return([]byte("old"), nil)
+				originDumper.EXPECT().Dump(gomock.Any(), "key").
+					Return(nil, time.Duration(0), errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:      []byte("old"),
+			wantStats: RedisMigratorStats{OriginHits: 1, BackfillErrors: 1},
+		},
+		{
+			name: "Shadow read match",
+			mode: MigrationModeShadowRead,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				origin.EXPECT().Get(gomock.Any(), "key").Return([]byte("old"), nil)
+				destination.EXPECT().Get(gomock.Any(), "key").Return([]byte("old"), nil)
+			},
+			want:      []byte("old"),
+			wantStats: RedisMigratorStats{Comparisons: 1},
+		},
+		{
+			name: "Shadow read mismatch is backfilled with replace",
+			mode: MigrationModeShadowRead,
+			mock: func(origin, destination *MockRedisItf, originDumper, destinationDumper *MockDumperItf) {
+				origin.EXPECT().Get(gomock.Any(), "key").Return([]byte("old"), nil)
+				destination.EXPECT().Get(gomock.Any(), "key").Return([]byte("stale"), nil)
+				originDumper.EXPECT().Dump(gomock.Any(), "key").Return([]byte("dump"), time.Second, nil)
+				destinationDumper.EXPECT().Restore(gomock.Any(), "key", time.Second, []byte("dump"), true).Return(nil)
+			},
+			want:      []byte("old"),
+			wantStats: RedisMigratorStats{Comparisons: 1, Mismatches: 1, Backfills: 1},
+		},
+		{
+			name: "Shadow read missing in both",
+			mode: MigrationModeShadowRead,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				origin.EXPECT().Get(gomock.Any(), "key").Return(nil, notFound)
+				destination.EXPECT().Get(gomock.Any(), "key").Return(nil, notFound)
+			},
+			want:      nil,
+			wantCode:  errorx.CodeNotFound,
+			wantStats: RedisMigratorStats{Comparisons: 1},
+		},
+		{
+			name: "Shadow read error",
+			mode: MigrationModeShadowRead,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				origin.EXPECT().Get(gomock.Any(), "key").Return([]byte("old"), nil)
+				destination.EXPECT().Get(gomock.Any(), "key").Return(nil, errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:      []byte("old"),
+			wantStats: RedisMigratorStats{ShadowErrors: 1},
+		},
+		{
+			name: "Cutover",
+			mode: MigrationModeCutover,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				destination.EXPECT().Get(gomock.Any(), "key").Return(nil, notFound)
+			},
+			want:     nil,
+			wantCode: errorx.CodeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			origin, originRedis, originDumper, _ := newMigrationClient(ctrl)
+			destination, destinationRedis, destinationDumper, _ := newMigrationClient(ctrl)
+			tt.mock(originRedis, destinationRedis, originDumper, destinationDumper)
+
+			migrator, err := NewRedisMigratorInstance(origin, destination, RedisMigratorConfig{Mode: tt.mode, Backfill: true})
+			assert.NoError(t, err)
+
+			got, err := migrator.Get(context.Background(), "key")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+
+			migrator.wg.Wait()
+			assert.Equal(t, tt.wantStats, migrator.Stats())
+		})
+	}
+}
+
+func TestRedisMigrator_write(t *testing.T) {
+	redisErr := errorx.E("redis error", errorx.CodeGateway)
+
+	tests := []struct {
+		name string
+		mode MigrationMode
+		mock func(origin, destination *MockRedisItf)
+		// del deletes the field instead of setting it, to update the existing keys.
+		del       bool
+		want      bool
+		wantCode  errorx.Code
+		wantStats RedisMigratorStats
+	}{
+		{
+			name: "Fallback creates to destination",
+			mode: MigrationModeFallback,
+			mock: func(origin, destination *MockRedisItf) {
+				destination.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "Fallback updates both",
+			mode: MigrationModeFallback,
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().HDel(gomock.Any(), "key", "field").Return(int64(1), nil)
+				destination.EXPECT().HDel(gomock.Any(), "key", "field").Return(int64(0), nil)
+			},
+			del:  true,
+			want: false,
+		},
+		{
+			name: "Fallback update origin error",
+			mode: MigrationModeFallback,
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().HDel(gomock.Any(), "key", "field").Return(int64(0), redisErr)
+			},
+			del:      true,
+			want:     false,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Dual-write returns destination",
+			mode: MigrationModeDualWrite,
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(false, nil)
+				destination.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "Dual-write origin error",
+			mode: MigrationModeDualWrite,
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(false, redisErr)
+			},
+			want:     false,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Shadow read returns origin",
+			mode: MigrationModeShadowRead,
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(false, nil)
+				destination.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(true, nil)
+			},
+			want: false,
+		},
+		{
+			name: "Shadow read origin error",
+			mode: MigrationModeShadowRead,
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(false, redisErr)
+			},
+			want:     false,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Cutover",
+			mode: MigrationModeCutover,
+			mock: func(origin, destination *MockRedisItf) {
+				destination.EXPECT().HSet(gomock.Any(), "key", "field", "value").Return(true, nil)
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			origin, destination := NewMockRedisItf(ctrl), NewMockRedisItf(ctrl)
+			tt.mock(origin, destination)
+
+			migrator := &RedisMigrator{origin: origin, destination: destination}
+			migrator.SetMode(tt.mode)
+
+			var (
+				got bool
+				err error
+			)
+			if tt.del {
+				var n int64
+				n, err = migrator.HDel(context.Background(), "key", "field")
+				got = n > 0
+			} else {
+				got, err = migrator.HSet(context.Background(), "key", "field", "value")
+			}
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantStats, migrator.Stats())
+		})
+	}
+}
+
+func TestRedisMigrator_Copy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	origin, _, originDumper, originScanner := newMigrationClient(ctrl)
+	destination, _, destinationDumper, _ := newMigrationClient(ctrl)
+
+	originScanner.EXPECT().ScanKeys(gomock.Any(), "user:*", int64(2), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int64, fn func(keys []string) error) error {
+			if err := fn([]string{"user:1", "user:2"}); err != nil {
+				return err
+			}
+			return fn([]string{"user:3", "user:4"})
+		})
+
+	// user:1 is copied, user:2 exists in destination, user:3 has expired, user:4 fails.
+	originDumper.EXPECT().Dump(gomock.Any(), "user:1").Return([]byte("1"), time.Duration(0), nil)
+	originDumper.EXPECT().Dump(gomock.Any(), "user:2").Return([]byte("2"), time.Minute, nil)
+	originDumper.EXPECT().Dump(gomock.Any(), "user:3").
+		Return(nil, time.Duration(0), errorx.E("not found", errorx.CodeNotFound))
+	originDumper.EXPECT().Dump(gomock.Any(), "user:4").Return([]byte("4"), time.Duration(0), nil)
+	destinationDumper.EXPECT().Restore(gomock.Any(), "user:1", time.Duration(0), []byte("1"), false).Return(nil)
+	destinationDumper.EXPECT().Restore(gomock.Any(), "user:2", time.Minute, []byte("2"), false).
+		Return(errorx.E("busy key", errorx.CodeConflict))
+	destinationDumper.EXPECT().Restore(gomock.Any(), "user:4", time.Duration(0), []byte("4"), false).
+		Return(errorx.E("redis error", errorx.CodeGateway))
+
+	var reports []CopyProgress
+	migrator := &RedisMigrator{origin: origin, destination: destination}
+	got, err := migrator.Copy(context.Background(), CopyConfig{
+		Match: "user:*",
+		Count: 2,
+		Progress: func(progress CopyProgress) {
+			reports = append(reports, progress)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, CopyProgress{Scanned: 4, Copied: 1, Skipped: 2, Failed: 1}, got)
+	assert.Equal(t, []CopyProgress{{Scanned: 2, Copied: 1, Skipped: 1}, got}, reports)
+	assert.Equal(t, RedisMigratorStats{Copied: 1, CopySkipped: 2, CopyErrors: 1}, migrator.Stats())
+
+	// The origin must be scannable.
+	_, err = (&RedisMigrator{origin: NewMockRedisItf(ctrl), destination: destination}).Copy(context.Background(), DefaultCopyConfig)
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+}
+
+func TestSameResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		origin      interface{}
+		destination interface{}
+		want        bool
+	}{
+		{name: "Equal bytes", origin: []byte("a"), destination: []byte("a"), want: true},
+		{name: "Different bytes", origin: []byte("a"), destination: []byte("b"), want: false},
+		{name: "Unordered members", origin: []string{"a", "b"}, destination: []string{"b", "a"}, want: true},
+		{name: "Different members", origin: []string{"a", "b"}, destination: []string{"a", "c"}, want: false},
+		{name: "Missing member", origin: []string{"a", "b"}, destination: []string{"a"}, want: false},
+		{name: "Equal hash", origin: map[string]string{"a": "1"}, destination: map[string]string{"a": "1"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sameResult(tt.origin, tt.destination))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"github.com/peractio/gdk/pkg/errorx/v2"
 	"github.com/peractio/gdk/pkg/logx"
@@ -16,210 +18,737 @@ var (
 )
 
 // RedisMigrator is a redis client to migrate from one instance to another.
-// All the create commands will be executed to the new instance.
-// All the update and delete commands will be executed on both instances.
+// The instance serving the commands depends on the migration mode, see MigrationMode.
+// In the default fallback mode, all the create commands will be executed to the new instance.
+// All the update and delete commands will be executed on both instances, the old one first,
+// and fail when any of them fails.
 // While the read commands will read from the new instance first,
 // and if the result is not found, it will attempt to read from the old one.
+// The counters are copied to the new instance before they are first updated there,
+// so they require both clients to implement DumperItf until the cutover.
 type RedisMigrator struct {
+	// stats is the first field to be 64-bit aligned for the atomic operations.
+	stats RedisMigratorStats
+	mode  int32
+
 	origin      RedisItf
 	destination RedisItf
+	config      RedisMigratorConfig
+
+	// slots limits the number of background backfills and comparisons.
+	slots chan struct{}
+	wg    sync.WaitGroup
 }
 
-// NewRedisMigrator return a redis migrator client.
+// NewRedisMigrator returns the redis migrator client shared by the package, with the default configuration.
+// The client is created on the first call, the later calls return it whatever their clients.
 func NewRedisMigrator(origin, destination RedisItf) (*RedisMigrator, error) {
+	return NewRedisMigratorWithConfig(origin, destination, DefaultRedisMigratorConfig)
+}
+
+// NewRedisMigratorWithConfig returns the redis migrator client shared by the package.
+// The client is created on the first call, the later calls return it whatever their clients and configuration.
+// Use NewRedisMigratorInstance to hold several clients.
+func NewRedisMigratorWithConfig(
+	origin, destination RedisItf,
+	config RedisMigratorConfig,
+) (*RedisMigrator, error) {
 	onceNewRedisMigrator.Do(func() {
-		onceNewRedisMigratorRes = &RedisMigrator{
-			origin:      origin,
-			destination: destination,
-		}
+		onceNewRedisMigratorRes, onceNewRedisMigratorErr = NewRedisMigratorInstance(origin, destination, config)
 	})
 
 	return onceNewRedisMigratorRes, onceNewRedisMigratorErr
 }
 
+// NewRedisMigratorInstance returns a new redis migrator client.
+func NewRedisMigratorInstance(origin, destination RedisItf, config RedisMigratorConfig) (*RedisMigrator, error) {
+	const op errorx.Op = "cache/NewRedisMigrator"
+
+	if config.Backfill {
+		if _, ok := origin.(DumperItf); !ok {
+			return nil, errorx.E("backfill requires the origin client to implement DumperItf", op, errorx.CodeConfig)
+		}
+		if _, ok := destination.(DumperItf); !ok {
+			return nil, errorx.E("backfill requires the destination client to implement DumperItf", op, errorx.CodeConfig)
+		}
+	}
+
+	// Defaults
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultRedisMigratorConfig.Concurrency
+	}
+
+	return &RedisMigrator{
+		mode:        int32(config.Mode),
+		origin:      origin,
+		destination: destination,
+		config:      config,
+		slots:       make(chan struct{}, config.Concurrency),
+	}, nil
+}
+
+// Mode returns the current migration mode.
+func (r *RedisMigrator) Mode() MigrationMode {
+	return MigrationMode(atomic.LoadInt32(&r.mode))
+}
+
+// SetMode switches the migration mode, so the migration can move forward or be rolled back without a restart.
+func (r *RedisMigrator) SetMode(mode MigrationMode) {
+	atomic.StoreInt32(&r.mode, int32(mode))
+}
+
 // Get gets the value from redis in []byte form.
 func (r *RedisMigrator) Get(ctx context.Context, key string) ([]byte, error) {
 	const op errorx.Op = "cache/RedisMigrator.Get"
 
-	// Read from new client.
-	res, err := r.destination.Get(ctx, key)
-	if err == nil && res != nil {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
-		return res, nil
-	}
-
-	// Read from old client.
-	res, err = r.origin.Get(ctx, key)
+	res, err := r.read(ctx, op, key, foundAlways, func(client RedisItf) (interface{}, error) {
+		return client.Get(ctx, key)
+	})
 	if err != nil {
-		return nil, errorx.E(err, op)
+		return nil, err
 	}
-	if res != nil {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
-	}
-	return res, nil
+
+	return res.([]byte), nil
+}
+
+// SimpleSet sets value to key in redis without any additional options.
+// Key doesn't have a TTL.
+func (r *RedisMigrator) SimpleSet(ctx context.Context, key, value string) error {
+	const op errorx.Op = "cache/RedisMigrator.SimpleSet"
+
+	_, err := r.write(ctx, op, key, true, func(client RedisItf) (interface{}, error) {
+		return nil, client.SimpleSet(ctx, key, value)
+	})
+
+	return err
 }
 
 // SetEX sets the value to a key with timeout in seconds.
-func (r *RedisMigrator) SetEX(
+func (r *RedisMigrator) SetEX(ctx context.Context, key string, seconds int64, value string) error {
+	const op errorx.Op = "cache/RedisMigrator.SetEX"
+
+	_, err := r.write(ctx, op, key, true, func(client RedisItf) (interface{}, error) {
+		return nil, client.SetEX(ctx, key, seconds, value)
+	})
+
+	return err
+}
+
+// SetNX sets a value to a key with specified timeouts.
+// SetNX returns false if the key exists in either instance,
+// except in the shadow-read and cutover modes where a single instance owns the key.
+func (r *RedisMigrator) SetNX(
 	ctx context.Context,
 	key string,
 	seconds int64,
 	value string,
-) error {
-	const op errorx.Op = "cache/RedisMigrator.SetEX"
+) (bool, error) {
+	const op errorx.Op = "cache/RedisMigrator.SetNX"
+
+	mode := r.Mode()
+	switch mode {
+	case MigrationModeCutover:
+		res, err := r.destination.SetNX(ctx, key, seconds, value)
+		if err != nil {
+			return false, errorx.E(err, op)
+		}
+		return res, nil
+
+	case MigrationModeShadowRead:
+		// Origin owns the key, the destination copy is overwritten.
+		res, err := r.origin.SetNX(ctx, key, seconds, value)
+		if err != nil {
+			return false, errorx.E(err, op)
+		}
+		if res {
+			r.mirror(ctx, op, key, "destination", func(client RedisItf) (interface{}, error) {
+				return nil, client.SetEX(ctx, key, seconds, value)
+			})
+		}
+		return res, nil
+	}
+
+	// Read from old client, the key may not be migrated yet.
+	exists, err := r.origin.Exists(ctx, key)
+	if err != nil {
+		return false, errorx.E(err, op)
+	}
+	if exists {
+		return false, nil
+	}
 
 	// Create to new client.
-	err := r.destination.SetEX(ctx, key, seconds, value)
+	res, err := r.destination.SetNX(ctx, key, seconds, value)
 	if err != nil {
-		return errorx.E(err, op)
+		return false, errorx.E(err, op)
+	}
+	if res && mode == MigrationModeDualWrite {
+		r.mirror(ctx, op, key, "origin", func(client RedisItf) (interface{}, error) {
+			return nil, client.SetEX(ctx, key, seconds, value)
+		})
 	}
 
 	logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
-	return nil
+	return res, nil
 }
 
 // Exists checks whether the key exists in redis.
 func (r *RedisMigrator) Exists(ctx context.Context, key string) (bool, error) {
 	const op errorx.Op = "cache/RedisMigrator.Exists"
 
-	// Read from new client.
-	res, err := r.destination.Exists(ctx, key)
-	if err == nil && res {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
-		return true, nil
-	}
-
-	// Read from old client.
-	res, err = r.origin.Exists(ctx, key)
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.Exists(ctx, key)
+	})
 	if err != nil {
-		return false, errorx.E(err, op)
-	}
-	if res {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+		return false, err
 	}
-	return res, nil
+
+	return res.(bool), nil
 }
 
 // Expire sets the ttl of a key to specified value in seconds.
 func (r *RedisMigrator) Expire(ctx context.Context, key string, seconds int64) (bool, error) {
 	const op errorx.Op = "cache/RedisMigrator.Expire"
 
-	// Update to old client.
-	res, err := r.origin.Expire(ctx, key, seconds)
-	if err == nil && res {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.Expire(ctx, key, seconds)
+	})
+	if err != nil {
+		return false, err
 	}
 
-	// Update to new client.
-	res, err = r.destination.Expire(ctx, key, seconds)
-	if err == nil && res {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
+	return res.(bool), nil
+}
+
+// ExpireAt sets the ttl of a key to a certain timestamp.
+func (r *RedisMigrator) ExpireAt(ctx context.Context, key string, timestamp int64) (bool, error) {
+	const op errorx.Op = "cache/RedisMigrator.ExpireAt"
+
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.ExpireAt(ctx, key, timestamp)
+	})
+	if err != nil {
+		return false, err
 	}
 
-	return res, err
+	return res.(bool), nil
 }
 
-// TTL gets the time to live of a key / expiry time.
-func (r *RedisMigrator) TTL(ctx context.Context, key string) (int64, error) {
-	const op errorx.Op = "cache/RedisMigrator.TTL"
+// Incr increments the integer value of a key by 1.
+func (r *RedisMigrator) Incr(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.Incr"
 
-	// Read from new client.
-	res, err := r.destination.TTL(ctx, key)
-	if err == nil && res > 0 {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
-		return res, nil
+	res, err := r.count(ctx, op, key, func(client RedisItf) (interface{}, error) {
+		return client.Incr(ctx, key)
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	// Read from old client.
-	res, err = r.origin.TTL(ctx, key)
+	return res.(int64), nil
+}
+
+// Decr decrements the integer value of a key by 1.
+func (r *RedisMigrator) Decr(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.Decr"
+
+	res, err := r.count(ctx, op, key, func(client RedisItf) (interface{}, error) {
+		return client.Decr(ctx, key)
+	})
 	if err != nil {
-		return 0, errorx.E(err, op)
+		return 0, err
 	}
-	if res > 0 {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+
+	return res.(int64), nil
+}
+
+// IncrBy increments the integer value of a key by the given amount.
+func (r *RedisMigrator) IncrBy(ctx context.Context, key string, by int64) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.IncrBy"
+
+	res, err := r.count(ctx, op, key, func(client RedisItf) (interface{}, error) {
+		return client.IncrBy(ctx, key, by)
+	})
+	if err != nil {
+		return 0, err
 	}
-	return res, nil
+
+	return res.(int64), nil
+}
+
+// IncrByEx increments redis key by adding expired.
+func (r *RedisMigrator) IncrByEx(ctx context.Context, key string, by int64, expires int64) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.IncrByEx"
+
+	res, err := r.count(ctx, op, key, func(client RedisItf) (interface{}, error) {
+		return client.IncrByEx(ctx, key, by, expires)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// TTL gets the time to live of a key / expiry time in seconds.
+func (r *RedisMigrator) TTL(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.TTL"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.TTL(ctx, key)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
 }
 
 // HGet gets the value of a hash field.
 func (r *RedisMigrator) HGet(ctx context.Context, key, field string) ([]byte, error) {
 	const op errorx.Op = "cache/RedisMigrator.HGet"
 
-	// Read from new client.
-	res, err := r.destination.HGet(ctx, key, field)
-	if err == nil && res != nil {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
-		return res, nil
+	res, err := r.read(ctx, op, key, foundAlways, func(client RedisItf) (interface{}, error) {
+		return client.HGet(ctx, key, field)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Read from old client.
-	res, err = r.origin.HGet(ctx, key, field)
+	return res.([]byte), nil
+}
+
+// HMGet gets a value of multiple fields from hash key.
+// Missing field is returned as a nil value.
+func (r *RedisMigrator) HMGet(ctx context.Context, key string, fields ...string) ([][]byte, error) {
+	const op errorx.Op = "cache/RedisMigrator.HMGet"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.HMGet(ctx, key, fields...)
+	})
 	if err != nil {
-		return nil, errorx.E(err, op)
-	}
-	if res != nil {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+		return nil, err
 	}
-	return res, nil
+
+	return res.([][]byte), nil
 }
 
-// HExists determines if a hash field exists.
-func (r *RedisMigrator) HExists(ctx context.Context, key, field string) (bool, error) {
-	const op errorx.Op = "cache/RedisMigrator.HGet"
+// HGetAll gets all the fields and values in a hash.
+func (r *RedisMigrator) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	const op errorx.Op = "cache/RedisMigrator.HGetAll"
 
-	// Read from new client.
-	res, err := r.destination.HExists(ctx, key, field)
-	if err == nil && res {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
-		return true, nil
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.HGetAll(ctx, key)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Read from old client.
-	res, err = r.origin.HExists(ctx, key, field)
+	return res.(map[string]string), nil
+}
+
+// HKeys gets all the fields in a hash.
+func (r *RedisMigrator) HKeys(ctx context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/RedisMigrator.HKeys"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.HKeys(ctx, key)
+	})
 	if err != nil {
-		return false, errorx.E(err, op)
+		return nil, err
 	}
-	if res {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+
+	return res.([]string), nil
+}
+
+// HExists determines if a hash field exists.
+func (r *RedisMigrator) HExists(ctx context.Context, key, field string) (bool, error) {
+	const op errorx.Op = "cache/RedisMigrator.HExists"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.HExists(ctx, key, field)
+	})
+	if err != nil {
+		return false, err
 	}
-	return res, nil
+
+	return res.(bool), nil
 }
 
 // HSet sets the string value of a hash field.
 func (r *RedisMigrator) HSet(ctx context.Context, key, field, value string) (bool, error) {
 	const op errorx.Op = "cache/RedisMigrator.HSet"
 
-	// Create to new client.
-	res, err := r.destination.HSet(ctx, key, field, value)
+	res, err := r.write(ctx, op, key, true, func(client RedisItf) (interface{}, error) {
+		return client.HSet(ctx, key, field, value)
+	})
 	if err != nil {
-		return false, errorx.E(err, op)
+		return false, err
 	}
 
-	logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
-	return res, nil
+	return res.(bool), nil
+}
+
+// HDel deletes a hash field.
+func (r *RedisMigrator) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.HDel"
+
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.HDel(ctx, key, fields...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
 }
 
 // Del deletes a key.
 func (r *RedisMigrator) Del(ctx context.Context, key ...interface{}) (int64, error) {
 	const op errorx.Op = "cache/RedisMigrator.Del"
 
-	// Update to old client.
-	res, err := r.origin.Del(ctx, key...)
-	if err == nil && res > 0 {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "origin"}, string(op)+" success")
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.Del(ctx, key...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// LPush prepends the values to a list and returns the length of the list.
+func (r *RedisMigrator) LPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.LPush"
+
+	res, err := r.write(ctx, op, key, true, func(client RedisItf) (interface{}, error) {
+		return client.LPush(ctx, key, values...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// RPush appends the values to a list and returns the length of the list.
+func (r *RedisMigrator) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.RPush"
+
+	res, err := r.write(ctx, op, key, true, func(client RedisItf) (interface{}, error) {
+		return client.RPush(ctx, key, values...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// LRange gets array range that we set using LPush between index Start and Stop.
+func (r *RedisMigrator) LRange(ctx context.Context, key string, start, stop int64) ([][]byte, error) {
+	const op errorx.Op = "cache/RedisMigrator.LRange"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.LRange(ctx, key, start, stop)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([][]byte), nil
+}
+
+// LTrim trims array value that we set using LPush between index start and stop.
+func (r *RedisMigrator) LTrim(ctx context.Context, key string, start, stop int64) error {
+	const op errorx.Op = "cache/RedisMigrator.LTrim"
+
+	_, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return nil, client.LTrim(ctx, key, start, stop)
+	})
+
+	return err
+}
+
+// LLen gets the length of a list.
+func (r *RedisMigrator) LLen(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.LLen"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.LLen(ctx, key)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// SAdd add the specified members to the set stored at key.
+// It returns false if key and value combination exists.
+func (r *RedisMigrator) SAdd(ctx context.Context, key string, value ...string) (bool, error) {
+	const op errorx.Op = "cache/RedisMigrator.SAdd"
+
+	res, err := r.write(ctx, op, key, true, func(client RedisItf) (interface{}, error) {
+		return client.SAdd(ctx, key, value...)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return res.(bool), nil
+}
+
+// SRem removes the members from a set and returns the number of removed members.
+func (r *RedisMigrator) SRem(ctx context.Context, key string, value ...string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.SRem"
+
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.SRem(ctx, key, value...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// SMembers gets all the members of a set.
+func (r *RedisMigrator) SMembers(ctx context.Context, key string) ([]string, error) {
+	const op errorx.Op = "cache/RedisMigrator.SMembers"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.SMembers(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]string), nil
+}
+
+// SIsMember determines if a value is a member of a set.
+func (r *RedisMigrator) SIsMember(ctx context.Context, key, value string) (bool, error) {
+	const op errorx.Op = "cache/RedisMigrator.SIsMember"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.SIsMember(ctx, key, value)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return res.(bool), nil
+}
+
+// SCard gets the number of members of a set.
+func (r *RedisMigrator) SCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.SCard"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.SCard(ctx, key)
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	// Update to new client.
-	res, err = r.destination.Del(ctx, key...)
-	if err == nil && res > 0 {
-		logx.DBG(ctx, logx.KV{tags.Key: key, tags.Client: "destination"}, string(op)+" success")
+	return res.(int64), nil
+}
+
+// ZAdd adds the members with their scores to a sorted set, or updates the score of existing members.
+// It returns the number of added members.
+func (r *RedisMigrator) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZAdd"
+
+	res, err := r.write(ctx, op, key, true, func(client RedisItf) (interface{}, error) {
+		return client.ZAdd(ctx, key, members...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// ZIncrBy increments the score of a sorted set member and returns the new score.
+func (r *RedisMigrator) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZIncrBy"
+
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.ZIncrBy(ctx, key, increment, member)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(float64), nil
+}
+
+// ZRange gets the members of a sorted set between index start and stop, ordered from the lowest score.
+func (r *RedisMigrator) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZRange"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.ZRange(ctx, key, start, stop)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]ZMember), nil
+}
+
+// ZRevRange gets the members of a sorted set between index start and stop, ordered from the highest score.
+func (r *RedisMigrator) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZRevRange"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.ZRevRange(ctx, key, start, stop)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]ZMember), nil
+}
+
+// ZRangeByScore gets the members of a sorted set with a score between min and max,
+// ordered from the lowest score.
+// Zero offset and count mean no limit, a zero or negative count means all the members after the offset.
+func (r *RedisMigrator) ZRangeByScore(
+	ctx context.Context,
+	key, min, max string,
+	offset, count int64,
+) ([]ZMember, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZRangeByScore"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.ZRangeByScore(ctx, key, min, max, offset, count)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]ZMember), nil
+}
+
+// ZRank gets the index of a sorted set member, ordered from the lowest score.
+func (r *RedisMigrator) ZRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZRank"
+
+	res, err := r.read(ctx, op, key, foundAlways, func(client RedisItf) (interface{}, error) {
+		return client.ZRank(ctx, key, member)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// ZRevRank gets the index of a sorted set member, ordered from the highest score.
+func (r *RedisMigrator) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZRevRank"
+
+	res, err := r.read(ctx, op, key, foundAlways, func(client RedisItf) (interface{}, error) {
+		return client.ZRevRank(ctx, key, member)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// ZScore gets the score of a sorted set member.
+func (r *RedisMigrator) ZScore(ctx context.Context, key, member string) (float64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZScore"
+
+	res, err := r.read(ctx, op, key, foundAlways, func(client RedisItf) (interface{}, error) {
+		return client.ZScore(ctx, key, member)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(float64), nil
+}
+
+// ZCard gets the number of members of a sorted set.
+func (r *RedisMigrator) ZCard(ctx context.Context, key string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZCard"
+
+	res, err := r.read(ctx, op, key, foundValue, func(client RedisItf) (interface{}, error) {
+		return client.ZCard(ctx, key)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// ZRem removes the members from a sorted set and returns the number of removed members.
+func (r *RedisMigrator) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZRem"
+
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.ZRem(ctx, key, members...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// ZRemRangeByScore removes the members of a sorted set with a score between min and max,
+// and returns the number of removed members.
+func (r *RedisMigrator) ZRemRangeByScore(ctx context.Context, key, min, max string) (int64, error) {
+	const op errorx.Op = "cache/RedisMigrator.ZRemRangeByScore"
+
+	res, err := r.write(ctx, op, key, false, func(client RedisItf) (interface{}, error) {
+		return client.ZRemRangeByScore(ctx, key, min, max)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.(int64), nil
+}
+
+// Publish sends message to a topic on both instances,
+// and returns the total numbers of subscriber that receives the message.
+// In the cutover mode, the message is only sent to the new instance.
+func (r *RedisMigrator) Publish(ctx context.Context, topic, message string) (int, error) {
+	const op errorx.Op = "cache/RedisMigrator.Publish"
+
+	if r.Mode() == MigrationModeCutover {
+		res, err := r.destination.Publish(ctx, topic, message)
+		if err != nil {
+			return 0, errorx.E(err, op)
+		}
+		return res, nil
+	}
+
+	// Subscribers may listen to either instance during the migration.
+	origin, err := r.origin.Publish(ctx, topic, message)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	destination, err := r.destination.Publish(ctx, topic, message)
+	if err != nil {
+		return origin, errorx.E(err, op)
 	}
 
-	return res, err
+	logx.DBG(ctx, logx.KV{tags.Channel: topic}, string(op)+" success")
+	return origin + destination, nil
 }
 
-// Close closes the client, releasing any open resources.
+// Close waits for the background backfills and comparisons, then closes the client, releasing any open resources.
 func (r *RedisMigrator) Close() {
+	r.wg.Wait()
 	r.origin.Close()
 	r.destination.Close()
 }
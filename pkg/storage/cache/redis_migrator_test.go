@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisMigrator_HGetAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(origin, destination *MockRedisItf)
+		want     map[string]string
+		wantCode errorx.Code
+	}{
+		{
+			name: "Found in destination",
+			mock: func(origin, destination *MockRedisItf) {
+				destination.EXPECT().HGetAll(gomock.Any(), "key").Return(map[string]string{"a": "1"}, nil)
+			},
+			want:     map[string]string{"a": "1"},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Found in origin",
+			mock: func(origin, destination *MockRedisItf) {
+				destination.EXPECT().HGetAll(gomock.Any(), "key").Return(map[string]string{}, nil)
+				origin.EXPECT().HGetAll(gomock.Any(), "key").Return(map[string]string{"a": "2"}, nil)
+			},
+			want:     map[string]string{"a": "2"},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Origin error",
+			mock: func(origin, destination *MockRedisItf) {
+				destination.EXPECT().HGetAll(gomock.Any(), "key").Return(nil, errorx.E("redis error", errorx.CodeGateway))
+				origin.EXPECT().HGetAll(gomock.Any(), "key").Return(nil, errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:     nil,
+			wantCode: errorx.CodeGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			origin, destination := NewMockRedisItf(ctrl), NewMockRedisItf(ctrl)
+			tt.mock(origin, destination)
+
+			migrator := &RedisMigrator{origin: origin, destination: destination}
+			got, err := migrator.HGetAll(context.Background(), "key")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedisMigrator_HMGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	origin, destination := NewMockRedisItf(ctrl), NewMockRedisItf(ctrl)
+	destination.EXPECT().HMGet(gomock.Any(), "key", "a", "b").Return([][]byte{nil, nil}, nil)
+	origin.EXPECT().HMGet(gomock.Any(), "key", "a", "b").Return([][]byte{[]byte("1"), nil}, nil)
+
+	migrator := &RedisMigrator{origin: origin, destination: destination}
+	got, err := migrator.HMGet(context.Background(), "key", "a", "b")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("1"), nil}, got)
+}
+
+func TestRedisMigrator_SetNX(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(origin, destination *MockRedisItf)
+		want     bool
+		wantCode errorx.Code
+	}{
+		{
+			name: "Exists in origin",
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().Exists(gomock.Any(), "key").Return(true, nil)
+			},
+			want:     false,
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Origin error",
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().Exists(gomock.Any(), "key").Return(false, errorx.E("redis error", errorx.CodeGateway))
+			},
+			want:     false,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Set to destination",
+			mock: func(origin, destination *MockRedisItf) {
+				origin.EXPECT().Exists(gomock.Any(), "key").Return(false, nil)
+				destination.EXPECT().SetNX(gomock.Any(), "key", int64(10), "value").Return(true, nil)
+			},
+			want:     true,
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			origin, destination := NewMockRedisItf(ctrl), NewMockRedisItf(ctrl)
+			tt.mock(origin, destination)
+
+			migrator := &RedisMigrator{origin: origin, destination: destination}
+			got, err := migrator.SetNX(context.Background(), "key", 10, "value")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedisMigrator_Incr(t *testing.T) {
+	redisErr := errorx.E("redis error", errorx.CodeGateway)
+
+	tests := []struct {
+		name     string
+		mode     MigrationMode
+		dumpers  bool
+		mock     func(origin, destination *MockRedisItf, originDumper, destinationDumper *MockDumperItf)
+		want     int64
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Fallback without dumpers",
+			mode:     MigrationModeFallback,
+			mock:     func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:     "Dual-write without dumpers",
+			mode:     MigrationModeDualWrite,
+			mock:     func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name:    "Fallback copies the counter before incrementing it",
+			mode:    MigrationModeFallback,
+			dumpers: true,
+			mock: func(origin, destination *MockRedisItf, originDumper, destinationDumper *MockDumperItf) {
+				destination.EXPECT().Exists(gomock.Any(), "key").Return(false, nil)
+				originDumper.EXPECT().Dump(gomock.Any(), "key").Return([]byte("dump"), time.Minute, nil)
+				destinationDumper.EXPECT().Restore(gomock.Any(), "key", time.Minute, []byte("dump"), false).Return(nil)
+				origin.EXPECT().Incr(gomock.Any(), "key").Return(int64(5), nil)
+				destination.EXPECT().Incr(gomock.Any(), "key").Return(int64(5), nil)
+			},
+			want: 5,
+		},
+		{
+			name:    "Dual-write with migrated counter",
+			mode:    MigrationModeDualWrite,
+			dumpers: true,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				destination.EXPECT().Exists(gomock.Any(), "key").Return(true, nil)
+				origin.EXPECT().Incr(gomock.Any(), "key").Return(int64(2), nil)
+				destination.EXPECT().Incr(gomock.Any(), "key").Return(int64(2), nil)
+			},
+			want: 2,
+		},
+		{
+			name:    "Dual-write origin error",
+			mode:    MigrationModeDualWrite,
+			dumpers: true,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				destination.EXPECT().Exists(gomock.Any(), "key").Return(true, nil)
+				origin.EXPECT().Incr(gomock.Any(), "key").Return(int64(0), redisErr)
+			},
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Cutover",
+			mode: MigrationModeCutover,
+			mock: func(origin, destination *MockRedisItf, _, _ *MockDumperItf) {
+				destination.EXPECT().Incr(gomock.Any(), "key").Return(int64(2), nil)
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var origin, destination RedisItf
+			originClient, originRedis, originDumper, _ := newMigrationClient(ctrl)
+			destinationClient, destinationRedis, destinationDumper, _ := newMigrationClient(ctrl)
+			origin, destination = originRedis, destinationRedis
+			if tt.dumpers {
+				origin, destination = originClient, destinationClient
+			}
+			tt.mock(originRedis, destinationRedis, originDumper, destinationDumper)
+
+			migrator := &RedisMigrator{origin: origin, destination: destination}
+			migrator.SetMode(tt.mode)
+
+			got, err := migrator.Incr(context.Background(), "key")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedisMigrator_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	origin, destination := NewMockRedisItf(ctrl), NewMockRedisItf(ctrl)
+	origin.EXPECT().Publish(gomock.Any(), "topic", "message").Return(1, nil)
+	destination.EXPECT().Publish(gomock.Any(), "topic", "message").Return(2, nil)
+
+	migrator := &RedisMigrator{origin: origin, destination: destination}
+	got, err := migrator.Publish(context.Background(), "topic", "message")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, got)
+}
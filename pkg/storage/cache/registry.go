@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// Registry holds named clients shared by a service, as a session cache and a rate limit cache
+// on different instances. A client is created on the first use of its name,
+// the later uses with the same kind of client and the same configuration return the same client.
+// Failed creations are not kept, so they are attempted again on the next use.
+type Registry struct {
+	mu      sync.Mutex
+	clients map[string]registryEntry
+	// names is the creation order of the clients, closed in the reverse order.
+	names []string
+}
+
+// registryEntry is a client of the registry, with the kind and the configuration it was created with.
+type registryEntry struct {
+	kind   string
+	config interface{}
+	client interface{}
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		clients: make(map[string]registryEntry),
+	}
+}
+
+// Redigo returns the redigo client of the name.
+// The name used by another kind of client or another configuration is reported as an error with errorx.CodeConflict.
+func (r *Registry) Redigo(name string, config *RedisConfiguration) (*Redigo, error) {
+	const op errorx.Op = "cache/Registry.Redigo"
+
+	client, err := r.load(name, "redigo", config, func() (interface{}, error) {
+		// The constructor sets the defaults of the configuration, so it is given a copy to keep it comparable.
+		copied := *config
+		return NewRedigoInstance(&copied)
+	})
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res, ok := client.(*Redigo)
+	if !ok {
+		return nil, errorx.E(fmt.Errorf("client %s is a %T", name, client), op, errorx.CodeConflict)
+	}
+
+	return res, nil
+}
+
+// RedigoCluster returns the redigo cluster client of the name.
+// The name used by another kind of client or another configuration is reported as an error with errorx.CodeConflict.
+func (r *Registry) RedigoCluster(name string, config *RedisConfiguration) (*Redigo, error) {
+	const op errorx.Op = "cache/Registry.RedigoCluster"
+
+	client, err := r.load(name, "redigo cluster", config, func() (interface{}, error) {
+		// The constructor sets the defaults of the configuration, so it is given a copy to keep it comparable.
+		copied := *config
+		return NewRedigoClusterInstance(&copied)
+	})
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res, ok := client.(*Redigo)
+	if !ok {
+		return nil, errorx.E(fmt.Errorf("client %s is a %T", name, client), op, errorx.CodeConflict)
+	}
+
+	return res, nil
+}
+
+// GoRedisV8 returns the go-redis client of the name.
+// The name used by another kind of client or another configuration is reported as an error with errorx.CodeConflict.
+func (r *Registry) GoRedisV8(name string, config *RedisConfiguration) (*GoRedisV8, error) {
+	const op errorx.Op = "cache/Registry.GoRedisV8"
+
+	client, err := r.load(name, "go-redis v8", config, func() (interface{}, error) {
+		return NewGoRedisV8Instance(config)
+	})
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res, ok := client.(*GoRedisV8)
+	if !ok {
+		return nil, errorx.E(fmt.Errorf("client %s is a %T", name, client), op, errorx.CodeConflict)
+	}
+
+	return res, nil
+}
+
+// GoRedisClusterV8 returns the go-redis cluster client of the name.
+// The name used by another kind of client or another configuration is reported as an error with errorx.CodeConflict.
+func (r *Registry) GoRedisClusterV8(name string, config *RedisConfiguration) (*GoRedisClusterV8, error) {
+	const op errorx.Op = "cache/Registry.GoRedisClusterV8"
+
+	client, err := r.load(name, "go-redis cluster v8", config, func() (interface{}, error) {
+		return NewGoRedisClusterV8Instance(config)
+	})
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res, ok := client.(*GoRedisClusterV8)
+	if !ok {
+		return nil, errorx.E(fmt.Errorf("client %s is a %T", name, client), op, errorx.CodeConflict)
+	}
+
+	return res, nil
+}
+
+// Ristretto returns the ristretto client of the name.
+// The name used by another kind of client or another configuration is reported as an error with errorx.CodeConflict.
+func (r *Registry) Ristretto(name string, config *RistrettoConfiguration) (*Ristretto, error) {
+	const op errorx.Op = "cache/Registry.Ristretto"
+
+	client, err := r.load(name, "ristretto", config, func() (interface{}, error) {
+		return NewRistrettoInstance(config)
+	})
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	res, ok := client.(*Ristretto)
+	if !ok {
+		return nil, errorx.E(fmt.Errorf("client %s is a %T", name, client), op, errorx.CodeConflict)
+	}
+
+	return res, nil
+}
+
+// Close closes all the clients in the reverse order of their creation, and empties the registry.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.names) - 1; i >= 0; i-- {
+		if client, ok := r.clients[r.names[i]].client.(interface{ Close() }); ok {
+			client.Close()
+		}
+	}
+
+	r.clients = make(map[string]registryEntry)
+	r.names = nil
+}
+
+// load returns the client of the name, created by create if it doesn't exist yet.
+// The existing client of another kind or another configuration is reported as an error with errorx.CodeConflict.
+func (r *Registry) load(name, kind string, config interface{}, create func() (interface{}, error)) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The configuration is copied, so it can't be changed behind the registry.
+	value := reflect.ValueOf(config)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		config = value.Elem().Interface()
+	}
+
+	if entry, ok := r.clients[name]; ok {
+		if entry.kind != kind {
+			return nil, errorx.E(fmt.Errorf("client %s is a %s client", name, entry.kind), errorx.CodeConflict)
+		}
+		if !reflect.DeepEqual(entry.config, config) {
+			return nil, errorx.E(fmt.Errorf("client %s has another configuration", name), errorx.CodeConflict)
+		}
+		return entry.client, nil
+	}
+
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.clients == nil {
+		r.clients = make(map[string]registryEntry)
+	}
+	r.clients[name] = registryEntry{kind: kind, config: config, client: client}
+	r.names = append(r.names, name)
+	return client, nil
+}
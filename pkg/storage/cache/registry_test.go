@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	config := &RistrettoConfiguration{NumCounters: 1000, MaxCost: 100, BufferItems: 64}
+	registry := NewRegistry()
+	defer registry.Close()
+
+	session, err := registry.Ristretto("session", config)
+	assert.NoError(t, err)
+
+	// The same name returns the same client with the same configuration.
+	got, err := registry.Ristretto("session", &RistrettoConfiguration{NumCounters: 1000, MaxCost: 100, BufferItems: 64})
+	assert.NoError(t, err)
+	assert.Same(t, session, got)
+
+	// The same name with another configuration is a conflict.
+	_, err = registry.Ristretto("session", &RistrettoConfiguration{NumCounters: 1000, MaxCost: 200, BufferItems: 64})
+	assert.Equal(t, errorx.CodeConflict, errorx.GetCode(err))
+
+	// Another name returns another client.
+	rateLimit, err := registry.Ristretto("rate-limit", config)
+	assert.NoError(t, err)
+	assert.NotSame(t, session, rateLimit)
+
+	// The failed creation isn't kept.
+	_, err = registry.Ristretto("invalid", &RistrettoConfiguration{})
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+	got, err = registry.Ristretto("invalid", config)
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+
+	// The name is used by another kind of client.
+	_, err = registry.Redigo("session", &RedisConfiguration{})
+	assert.Equal(t, errorx.CodeConflict, errorx.GetCode(err))
+	_, err = registry.GoRedisClusterV8("session", &RedisConfiguration{})
+	assert.Equal(t, errorx.CodeConflict, errorx.GetCode(err))
+
+	registry.Close()
+	got, err = registry.Ristretto("session", config)
+	assert.NoError(t, err)
+	assert.NotSame(t, session, got)
+}
+
+func TestRegistry_Redis(t *testing.T) {
+	redis, err := miniredis.Run()
+	require.NoError(t, err)
+	defer redis.Close()
+
+	config := &RedisConfiguration{Addresses: []string{redis.Addr()}}
+	registry := NewRegistry()
+	defer registry.Close()
+
+	standalone, err := registry.Redigo("session", config)
+	require.NoError(t, err)
+
+	// The defaults set by the constructor don't change the configuration of the name.
+	got, err := registry.Redigo("session", config)
+	assert.NoError(t, err)
+	assert.Same(t, standalone, got)
+	assert.Equal(t, int64(0), config.OpenConnectionLimit)
+
+	// The standalone client isn't returned as a cluster client of the same type.
+	_, err = registry.RedigoCluster("session", config)
+	assert.Equal(t, errorx.CodeConflict, errorx.GetCode(err))
+
+	_, err = registry.GoRedisV8("cache", config)
+	require.NoError(t, err)
+	_, err = registry.GoRedisClusterV8("cache", config)
+	assert.Equal(t, errorx.CodeConflict, errorx.GetCode(err))
+}
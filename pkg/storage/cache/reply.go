@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// replyBytes converts a reply of both redigo and go-redis into []byte form.
+// Nil reply is reported as an error with errorx.CodeNotFound.
+func replyBytes(reply interface{}) ([]byte, error) {
+	const op errorx.Op = "cache.replyBytes"
+
+	switch v := reply.(type) {
+	case nil:
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case int64:
+		return []byte(strconv.FormatInt(v, 10)), nil
+	default:
+		return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+	}
+}
+
+// replyInt64 converts an integer reply of both redigo and go-redis into int64 form.
+// Nil reply is reported as an error with errorx.CodeNotFound.
+func replyInt64(reply interface{}) (int64, error) {
+	const op errorx.Op = "cache.replyInt64"
+
+	if v, ok := reply.(int64); ok {
+		return v, nil
+	}
+
+	res, err := replyBytes(reply)
+	if err != nil {
+		return 0, errorx.E(err, op)
+	}
+
+	v, err := strconv.ParseInt(string(res), 10, 64)
+	if err != nil {
+		return 0, errorx.E(err, op, errorx.CodeInvalid)
+	}
+
+	return v, nil
+}
+
+// replyInt64s converts an array reply of integers into []int64 form.
+func replyInt64s(reply interface{}) ([]int64, error) {
+	const op errorx.Op = "cache.replyInt64s"
+
+	values, ok := reply.([]interface{})
+	if !ok {
+		return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+	}
+
+	res := make([]int64, len(values))
+	for i, v := range values {
+		n, err := replyInt64(v)
+		if err != nil {
+			return nil, errorx.E(err, op)
+		}
+		res[i] = n
+	}
+
+	return res, nil
+}
+
+// replyStrings converts an array reply of both redigo and go-redis into []string form.
+func replyStrings(reply interface{}) ([]string, error) {
+	const op errorx.Op = "cache.replyStrings"
+
+	values, ok := reply.([]interface{})
+	if !ok {
+		return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+	}
+
+	res := make([]string, len(values))
+	for i, v := range values {
+		b, err := replyBytes(v)
+		if err != nil {
+			return nil, errorx.E(err, op)
+		}
+		res[i] = string(b)
+	}
+
+	return res, nil
+}
+
+// isBusyKey reports whether the error is caused by a key that already exists.
+func isBusyKey(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYKEY")
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplyInt64s(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    interface{}
+		want     []int64
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Not an array",
+			reply:    int64(1),
+			want:     nil,
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Nil element",
+			reply:    []interface{}{int64(1), nil},
+			want:     nil,
+			wantCode: errorx.CodeNotFound,
+		},
+		{
+			name:     "Success",
+			reply:    []interface{}{int64(1), []byte("2"), "3"},
+			want:     []int64{1, 2, 3},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replyInt64s(tt.reply)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplyStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    interface{}
+		want     []string
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Not an array",
+			reply:    "key",
+			want:     nil,
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Nil element",
+			reply:    []interface{}{"key", nil},
+			want:     nil,
+			wantCode: errorx.CodeNotFound,
+		},
+		{
+			name:     "Success",
+			reply:    []interface{}{[]byte("a"), "b"},
+			want:     []string{"a", "b"},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:     "Empty",
+			reply:    []interface{}{},
+			want:     []string{},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replyStrings(tt.reply)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
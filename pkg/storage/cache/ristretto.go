@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
@@ -13,36 +14,73 @@ var (
 	onceNewRistretto    resync.Once
 	onceNewRistrettoRes *Ristretto
 	onceNewRistrettoErr error
-	onceRistrettoClose  resync.Once
 )
 
 // Ristretto returns a in-process storage client using ristretto library.
 type Ristretto struct {
 	cache *ristretto.Cache
+	// cost is the cost of the items added by Set and SetEX, zero means the cost is computed by the cost function.
+	cost      int64
+	closeOnce sync.Once
 }
 
-// NewRistretto return a redis client.
+// NewRistretto returns the in-process storage client shared by the package.
+// The client is created on the first call, the later calls return it whatever their configuration.
+// Use NewRistrettoInstance or a Registry to hold several clients.
 func NewRistretto(config *RistrettoConfiguration) (*Ristretto, error) {
 	onceNewRistretto.Do(func() {
-		const op errorx.Op = "cache.NewRistretto"
-
-		cache, err := ristretto.NewCache(&ristretto.Config{
-			NumCounters: config.NumCounters,
-			MaxCost:     config.MaxCost,
-			BufferItems: config.BufferItems,
-			Metrics:     config.Metrics,
-		})
-		if err != nil {
-			onceNewRedigoErr = errorx.E(err, op)
-			return
+		onceNewRistrettoRes, onceNewRistrettoErr = NewRistrettoInstance(config)
+	})
+
+	return onceNewRistrettoRes, onceNewRistrettoErr
+}
+
+// NewRistrettoInstance returns a new in-process storage client.
+func NewRistrettoInstance(config *RistrettoConfiguration) (*Ristretto, error) {
+	const op errorx.Op = "cache.NewRistretto"
+
+	cache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters:        config.NumCounters,
+		MaxCost:            config.MaxCost,
+		BufferItems:        config.BufferItems,
+		Metrics:            config.Metrics,
+		Cost:               config.Cost,
+		IgnoreInternalCost: !config.InternalCost,
+	})
+	if err != nil {
+		return nil, errorx.E(err, op, errorx.CodeConfig)
+	}
+
+	cost := int64(1)
+	if config.Cost != nil {
+		cost = 0
+	}
+
+	return &Ristretto{
+		cache: cache,
+		cost:  cost,
+	}, nil
+}
+
+// EncodedSizeCost returns a cost function computing the size of the values encoded by the codec,
+// so MaxCost is the maximum size of the cache in bytes.
+// The strings and byte slices cost their length, and the values failing to be encoded cost 1.
+func EncodedSizeCost(codec Codec) func(value interface{}) int64 {
+	return func(value interface{}) int64 {
+		switch v := value.(type) {
+		case []byte:
+			return int64(len(v))
+		case string:
+			return int64(len(v))
 		}
 
-		onceNewRistrettoRes = &Ristretto{
-			cache: cache,
+		data, err := codec.Marshal(value)
+		if err != nil || len(data) == 0 {
+			return 1
 		}
-	})
 
-	return onceNewRistrettoRes, onceNewRistrettoErr
+		return int64(len(data))
+	}
 }
 
 // Get returns the value (if any) and a boolean representing whether the value was found or not.
@@ -55,13 +93,26 @@ func (r *Ristretto) Get(_ context.Context, key string) (res interface{}, exists
 	return res, true
 }
 
+// Lookup works like Get but reports the missing key as an error with errorx.CodeNotFound.
+func (r *Ristretto) Lookup(ctx context.Context, key string) (interface{}, error) {
+	const op errorx.Op = "cache/Ristretto.Lookup"
+
+	res, exists := r.Get(ctx, key)
+	if !exists {
+		return nil, errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+
+	return res, nil
+}
+
 // Set attempts to add the key-value item to the cache.
 // If it returns false, then the Set was dropped and the key-value item isn't added to the cache.
 // If it returns true, there's still a chance it could be dropped by the policy
 // if its determined that the key-value item isn't worth keeping,
 // but otherwise the item will be added and other items will be evicted in order to make room.
+// The cost of the item is 1, or computed by the cost function of the configuration.
 func (r *Ristretto) Set(_ context.Context, key string, value interface{}) bool {
-	return r.cache.Set(key, value, 1)
+	return r.cache.Set(key, value, r.cost)
 }
 
 // SetEX works like Set but adds a key-value pair to the cache
@@ -74,7 +125,36 @@ func (r *Ristretto) SetEX(
 	value interface{},
 	ttl time.Duration,
 ) bool {
-	return r.cache.SetWithTTL(key, value, 1, ttl)
+	return r.cache.SetWithTTL(key, value, r.cost, ttl)
+}
+
+// SetWithCost works like Set but adds the item with the given cost, e.g. its size in bytes.
+// The items are evicted once the sum of their costs reaches MaxCost.
+func (r *Ristretto) SetWithCost(_ context.Context, key string, value interface{}, cost int64) bool {
+	return r.cache.Set(key, value, cost)
+}
+
+// SetEXWithCost works like SetEX but adds the item with the given cost.
+func (r *Ristretto) SetEXWithCost(
+	_ context.Context,
+	key string,
+	value interface{},
+	cost int64,
+	ttl time.Duration,
+) bool {
+	return r.cache.SetWithTTL(key, value, cost, ttl)
+}
+
+// GetTTL returns the remaining time to live of a key, zero when the key never expires,
+// and a boolean representing whether the key was found or not.
+func (r *Ristretto) GetTTL(_ context.Context, key string) (time.Duration, bool) {
+	return r.cache.GetTTL(key)
+}
+
+// Wait blocks until the pending sets are applied, so they are visible to Get.
+// The sets are buffered and applied asynchronously, it is mostly useful in tests.
+func (r *Ristretto) Wait() {
+	r.cache.Wait()
 }
 
 // Del deletes the key-value item from the cache if it exists.
@@ -87,9 +167,32 @@ func (r *Ristretto) Clear(_ context.Context) {
 	r.cache.Clear()
 }
 
+// Metrics returns a snapshot of the metrics of the cache, nil when the metrics are disabled in the configuration.
+func (r *Ristretto) Metrics() *RistrettoMetrics {
+	metrics := r.cache.Metrics
+	if metrics == nil {
+		return nil
+	}
+
+	return &RistrettoMetrics{
+		Hits:         metrics.Hits(),
+		Misses:       metrics.Misses(),
+		KeysAdded:    metrics.KeysAdded(),
+		KeysUpdated:  metrics.KeysUpdated(),
+		KeysEvicted:  metrics.KeysEvicted(),
+		CostAdded:    metrics.CostAdded(),
+		CostEvicted:  metrics.CostEvicted(),
+		SetsDropped:  metrics.SetsDropped(),
+		SetsRejected: metrics.SetsRejected(),
+		GetsDropped:  metrics.GetsDropped(),
+		GetsKept:     metrics.GetsKept(),
+		Ratio:        metrics.Ratio(),
+	}
+}
+
 // Close stops all goroutines and closes all channels.
 func (r *Ristretto) Close() {
-	onceRistrettoClose.Do(func() {
+	r.closeOnce.Do(func() {
 		r.cache.Clear()
 		r.cache.Close()
 	})
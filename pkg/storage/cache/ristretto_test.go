@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/jsonx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRistretto(t *testing.T) {
+	onceNewRistretto.Reset()
+	defer onceNewRistretto.Reset()
+
+	// The error of the shared client is returned by every call.
+	got, err := NewRistretto(&RistrettoConfiguration{})
+	assert.Nil(t, got)
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+
+	got, err = NewRistretto(&RistrettoConfiguration{NumCounters: 1000, MaxCost: 100, BufferItems: 64})
+	assert.Nil(t, got)
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+}
+
+func TestNewRistrettoInstance(t *testing.T) {
+	config := &RistrettoConfiguration{NumCounters: 1000, MaxCost: 100, BufferItems: 64}
+
+	first, err := NewRistrettoInstance(config)
+	assert.NoError(t, err)
+	defer first.Close()
+
+	second, err := NewRistrettoInstance(config)
+	assert.NoError(t, err)
+	defer second.Close()
+
+	assert.NotSame(t, first, second)
+
+	// Closing a client doesn't prevent closing the others.
+	first.Close()
+	first.Close()
+	assert.NotPanics(t, second.Close)
+}
+
+func TestRistretto_Metrics(t *testing.T) {
+	client, err := NewRistrettoInstance(&RistrettoConfiguration{
+		NumCounters: 1e3,
+		MaxCost:     1 << 20,
+		BufferItems: 64,
+		Metrics:     false,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	assert.Nil(t, client.Metrics())
+}
+
+func TestRistretto_Cost(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        RistrettoConfiguration
+		set           func(ctx context.Context, client *Ristretto) bool
+		wantExists    bool
+		wantCostAdded uint64
+	}{
+		{
+			name:   "Default cost",
+			config: RistrettoConfiguration{},
+			set: func(ctx context.Context, client *Ristretto) bool {
+				return client.Set(ctx, "key", strings.Repeat("a", 500))
+			},
+			wantExists:    true,
+			wantCostAdded: 1,
+		},
+		{
+			name:   "Given cost",
+			config: RistrettoConfiguration{},
+			set: func(ctx context.Context, client *Ristretto) bool {
+				return client.SetEXWithCost(ctx, "key", "value", 50, time.Minute)
+			},
+			wantExists:    true,
+			wantCostAdded: 50,
+		},
+		{
+			name:   "Cost exceeding max cost",
+			config: RistrettoConfiguration{},
+			set: func(ctx context.Context, client *Ristretto) bool {
+				return client.SetWithCost(ctx, "key", "value", 500)
+			},
+			wantExists:    false,
+			wantCostAdded: 0,
+		},
+		{
+			name:   "Encoded size",
+			config: RistrettoConfiguration{Cost: EncodedSizeCost(NewJSONCodec(jsonx.New()))},
+			set: func(ctx context.Context, client *Ristretto) bool {
+				return client.SetEX(ctx, "key", struct{ A int }{A: 1}, time.Minute)
+			},
+			wantExists:    true,
+			wantCostAdded: 7,
+		},
+		{
+			name:   "Encoded size exceeding max cost",
+			config: RistrettoConfiguration{Cost: EncodedSizeCost(NewJSONCodec(jsonx.New()))},
+			set: func(ctx context.Context, client *Ristretto) bool {
+				return client.Set(ctx, "key", strings.Repeat("a", 500))
+			},
+			wantExists:    false,
+			wantCostAdded: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.NumCounters, config.MaxCost, config.BufferItems, config.Metrics = 1e3, 100, 64, true
+
+			client, err := NewRistrettoInstance(&config)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer client.Close()
+
+			ctx := context.Background()
+			tt.set(ctx, client)
+			client.Wait()
+
+			_, exists := client.Get(ctx, "key")
+			assert.Equal(t, tt.wantExists, exists)
+			assert.Equal(t, tt.wantCostAdded, client.Metrics().CostAdded)
+		})
+	}
+}
+
+func TestRistretto_GetTTL(t *testing.T) {
+	client, err := NewRistrettoInstance(&RistrettoConfiguration{NumCounters: 1e3, MaxCost: 100, BufferItems: 64})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	client.Set(ctx, "forever", "value")
+	client.SetEX(ctx, "expiring", "value", time.Minute)
+	client.Wait()
+
+	ttl, exists := client.GetTTL(ctx, "forever")
+	assert.True(t, exists)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	ttl, exists = client.GetTTL(ctx, "expiring")
+	assert.True(t, exists)
+	assert.True(t, ttl > 59*time.Second && ttl <= time.Minute, ttl)
+
+	_, exists = client.GetTTL(ctx, "missing")
+	assert.False(t, exists)
+}
+
+func TestEncodedSizeCost(t *testing.T) {
+	cost := EncodedSizeCost(NewJSONCodec(jsonx.New()))
+
+	assert.Equal(t, int64(5), cost([]byte("value")))
+	assert.Equal(t, int64(5), cost("value"))
+	assert.Equal(t, int64(12), cost(struct{ Name string }{Name: "a"}))
+	// The values failing to be encoded cost 1.
+	assert.Equal(t, int64(1), cost(make(chan int)))
+}
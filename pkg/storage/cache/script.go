@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"crypto/sha1" // nolint:gosec
+	"encoding/hex"
+	"strings"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// Script is a Lua script invoked with EVALSHA.
+// The script is loaded with SCRIPT LOAD when redis replies NOSCRIPT,
+// so it is only sent once per server.
+type Script struct {
+	keyCount int
+	src      string
+	hash     string
+}
+
+// NewScript returns a script with the number of keys it accepts.
+// A negative key count means the number of keys is not validated.
+func NewScript(keyCount int, src string) *Script {
+	h := sha1.New() // nolint:gosec
+	_, _ = h.Write([]byte(src))
+
+	return &Script{
+		keyCount: keyCount,
+		src:      src,
+		hash:     hex.EncodeToString(h.Sum(nil)),
+	}
+}
+
+// Hash returns the SHA1 digest of the script used by EVALSHA.
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Source returns the Lua source of the script.
+func (s *Script) Source() string {
+	return s.src
+}
+
+// validate checks the number of keys, and makes sure all the keys share the same hash slot.
+// Scripts can only access the keys of a single slot in cluster mode,
+// so related keys should share a hash tag, e.g. "{user:1}:count" and "{user:1}:log".
+func (s *Script) validate(keys []string) error {
+	const op errorx.Op = "cache/Script.validate"
+
+	if s.keyCount >= 0 && len(keys) != s.keyCount {
+		return errorx.E("invalid number of script keys", op, errorx.CodeInvalid)
+	}
+
+	for _, key := range keys {
+		if Slot(key) != Slot(keys[0]) {
+			return errorx.E("script keys must share the same hash tag", op, errorx.CodeInvalid)
+		}
+	}
+
+	return nil
+}
+
+// args returns the arguments of EVALSHA and EVAL after the script.
+func (s *Script) args(keys []string, args []interface{}) []interface{} {
+	res := make([]interface{}, 0, 1+len(keys)+len(args))
+	res = append(res, len(keys))
+	for _, key := range keys {
+		res = append(res, key)
+	}
+
+	return append(res, args...)
+}
+
+// isNoScript reports whether the error is caused by a script missing from the script cache.
+func isNoScript(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT")
+}
+
+// Scripts shipped with the package.
+// Durations are in milliseconds and the current time is given by the caller,
+// so the scripts don't depend on the redis clock.
+var (
+	// ScriptIncrByEx increments KEYS[1] by ARGV[1] and sets its ttl to ARGV[2] seconds.
+	// It returns the incremented value.
+	ScriptIncrByEx = NewScript(1, `
+		local result = redis.call("INCRBY", KEYS[1], ARGV[1])
+		redis.call("EXPIRE", KEYS[1], ARGV[2])
+		return result
+	`)
+
+	// ScriptLockAcquire sets KEYS[1] to the owner token ARGV[1] with ttl ARGV[2] if the lock is free,
+	// and increments the fencing counter KEYS[2].
+	// It returns the fencing token, or 0 if the lock is held.
+	ScriptLockAcquire = NewScript(2, `
+		if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+			return redis.call("INCR", KEYS[2])
+		end
+		return 0
+	`)
+
+	// ScriptLockRelease deletes KEYS[1] if it is still owned by the token ARGV[1].
+	// It returns 1 if the lock is released, or 0 if the lock is held by other owner.
+	ScriptLockRelease = NewScript(1, `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		end
+		return 0
+	`)
+
+	// ScriptLockRenew sets the ttl of KEYS[1] to ARGV[2] if it is still owned by the token ARGV[1].
+	// It returns 1 if the lock is renewed, or 0 if the lock is held by other owner.
+	ScriptLockRenew = NewScript(1, `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		end
+		return 0
+	`)
+
+	// ScriptTagKey adds the key ARGV[1] to the tag set KEYS[1] and sets its ttl to ARGV[2] seconds.
+	// It returns the number of added members.
+	ScriptTagKey = NewScript(1, `
+		local added = redis.call("SADD", KEYS[1], ARGV[1])
+		redis.call("EXPIRE", KEYS[1], ARGV[2])
+		return added
+	`)
+
+	// ScriptInvalidateTag deletes the members of the set KEYS[1] and the set itself.
+	// It returns the deleted members.
+	ScriptInvalidateTag = NewScript(1, `
+		local keys = redis.call("SMEMBERS", KEYS[1])
+		for i = 1, #keys, 1000 do
+			redis.call("DEL", unpack(keys, i, math.min(i + 999, #keys)))
+		end
+		redis.call("DEL", KEYS[1])
+		return keys
+	`)
+
+	// The rate limit scripts return {allowed, remaining, reset after}.
+	// Reset after is the duration to wait before retrying when the request is not allowed,
+	// or the duration until the limit is fully reset otherwise.
+
+	// ScriptRateLimitFixedWindow counts a request in the window KEYS[1],
+	// allowing ARGV[1] requests every ARGV[2] window.
+	ScriptRateLimitFixedWindow = NewScript(1, `
+		local limit = tonumber(ARGV[1])
+		local count = redis.call("INCR", KEYS[1])
+		if count == 1 then
+			redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		end
+		local ttl = redis.call("PTTL", KEYS[1])
+		if count > limit then
+			return {0, 0, ttl}
+		end
+		return {1, limit - count, ttl}
+	`)
+
+	// ScriptRateLimitSlidingLog logs a request ARGV[4] at ARGV[3] in the sorted set KEYS[1],
+	// allowing ARGV[1] requests in any ARGV[2] window.
+	ScriptRateLimitSlidingLog = NewScript(1, `
+		local limit = tonumber(ARGV[1])
+		local window = tonumber(ARGV[2])
+		local now = tonumber(ARGV[3])
+		redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+		local count = redis.call("ZCARD", KEYS[1])
+		if count >= limit then
+			local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+			return {0, 0, tonumber(oldest[2]) + window - now}
+		end
+		redis.call("ZADD", KEYS[1], now, ARGV[4])
+		redis.call("PEXPIRE", KEYS[1], window)
+		return {1, limit - count - 1, window}
+	`)
+
+	// ScriptRateLimitTokenBucket takes ARGV[4] tokens at ARGV[3] from the bucket KEYS[1],
+	// holding up to ARGV[1] tokens and refilled with ARGV[2] tokens every second.
+	ScriptRateLimitTokenBucket = NewScript(1, `
+		local capacity = tonumber(ARGV[1])
+		local rate = tonumber(ARGV[2])
+		local now = tonumber(ARGV[3])
+		local requested = tonumber(ARGV[4])
+		local bucket = redis.call("HMGET", KEYS[1], "tokens", "timestamp")
+		local tokens = tonumber(bucket[1]) or capacity
+		local timestamp = tonumber(bucket[2]) or now
+		local elapsed = math.max(0, now - timestamp)
+		tokens = math.min(capacity, tokens + elapsed * rate / 1000)
+		local allowed = 0
+		local reset = 0
+		if tokens >= requested then
+			allowed = 1
+			tokens = tokens - requested
+			reset = math.ceil((capacity - tokens) * 1000 / rate)
+		else
+			reset = math.ceil((requested - tokens) * 1000 / rate)
+		end
+		redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "timestamp", now)
+		redis.call("PEXPIRE", KEYS[1], math.ceil(capacity * 1000 / rate))
+		return {allowed, math.floor(tokens), reset}
+	`)
+)
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScript(t *testing.T) {
+	script := NewScript(1, "return 1")
+	assert.Equal(t, "e0e1f9fabfc9d4800c877a703b823ac0578ff8db", script.Hash())
+	assert.Equal(t, "return 1", script.Source())
+}
+
+func TestScript_validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   *Script
+		keys     []string
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Invalid number of keys",
+			script:   NewScript(2, ""),
+			keys:     []string{"a"},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Keys in different slots",
+			script:   NewScript(2, ""),
+			keys:     []string{"user:1:lock", "user:1:fence"},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Keys sharing a hash tag",
+			script:   NewScript(2, ""),
+			keys:     []string{"{user:1}:lock", "{user:1}:fence"},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name:     "Any number of keys",
+			script:   NewScript(-1, ""),
+			keys:     nil,
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, errorx.GetCode(tt.script.validate(tt.keys)))
+		})
+	}
+}
+
+func TestScript_args(t *testing.T) {
+	got := ScriptIncrByEx.args([]string{"key"}, []interface{}{int64(1), int64(10)})
+	assert.Equal(t, []interface{}{1, "key", int64(1), int64(10)}, got)
+}
+
+func TestIsNoScript(t *testing.T) {
+	assert.True(t, isNoScript(errorx.E("NOSCRIPT No matching script. Please use EVAL.")))
+	assert.False(t, isNoScript(errorx.E("ERR unknown command")))
+	assert.False(t, isNoScript(nil))
+}
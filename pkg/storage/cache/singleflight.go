@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// call is an in-flight or completed group call.
+type call struct {
+	wg  sync.WaitGroup
+	val []byte
+	err error
+}
+
+// group collapses concurrent calls with the same key into a single call.
+type group struct {
+	mu sync.Mutex
+	m  map[string]*call
+}
+
+// do executes fn once for all concurrent callers of the same key.
+// Shared reports whether the result is given to multiple callers.
+func (g *group) do(key string, fn func() ([]byte, error)) (res []byte, shared bool, err error) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+	if c, ok := g.m[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, true, c.err
+	}
+
+	// The error is kept when fn panics.
+	c := &call{err: errorx.E("call ended unexpectedly", errorx.CodeInternal)}
+	c.wg.Add(1)
+	g.m[key] = c
+	g.mu.Unlock()
+
+	// Always release the waiting callers, even when fn panics.
+	defer func() {
+		c.wg.Done()
+
+		g.mu.Lock()
+		delete(g.m, key)
+		g.mu.Unlock()
+	}()
+
+	c.val, c.err = fn()
+	return c.val, false, c.err
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"hash/fnv"
+)
+
+// countMinSketch estimates the number of occurrences of the keys in a fixed memory,
+// the estimates are never lower than the real counts, and overestimate them by about
+// e / width of the total count with a probability of 1 - exp(-depth).
+type countMinSketch struct {
+	width  uint32
+	counts [][]uint32
+}
+
+// newCountMinSketch returns a sketch of depth rows of width counters.
+func newCountMinSketch(width, depth int) *countMinSketch {
+	counts := make([][]uint32, depth)
+	for i := range counts {
+		counts[i] = make([]uint32, width)
+	}
+
+	return &countMinSketch{
+		width:  uint32(width),
+		counts: counts,
+	}
+}
+
+// add counts an occurrence of the key and returns its estimated count.
+// Only the smallest counters are incremented, which reduces the overestimation of the other keys.
+func (s *countMinSketch) add(key string) uint32 {
+	indexes := s.indexes(key)
+
+	count := s.least(indexes) + 1
+	for i, index := range indexes {
+		if s.counts[i][index] < count {
+			s.counts[i][index] = count
+		}
+	}
+
+	return count
+}
+
+// estimate returns the estimated count of the key.
+func (s *countMinSketch) estimate(key string) uint32 {
+	return s.least(s.indexes(key))
+}
+
+// least returns the smallest of the counters.
+func (s *countMinSketch) least(indexes []uint32) uint32 {
+	res := s.counts[0][indexes[0]]
+	for i, index := range indexes[1:] {
+		if v := s.counts[i+1][index]; v < res {
+			res = v
+		}
+	}
+
+	return res
+}
+
+// reset zeroes the counters.
+func (s *countMinSketch) reset() {
+	for _, row := range s.counts {
+		for i := range row {
+			row[i] = 0
+		}
+	}
+}
+
+// indexes returns the counter of the key in every row, derived from a 64-bit hash by double hashing.
+func (s *countMinSketch) indexes(key string) []uint32 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	sum := h.Sum64()
+
+	h1, h2 := uint32(sum), uint32(sum>>32)|1
+	res := make([]uint32, len(s.counts))
+	for i := range res {
+		res[i] = (h1 + uint32(i)*h2) % s.width
+	}
+
+	return res
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	sketch := newCountMinSketch(1024, 4)
+
+	for i := 0; i < 100; i++ {
+		sketch.add("hot")
+	}
+	for i := 0; i < 1000; i++ {
+		sketch.add(strconv.Itoa(i))
+	}
+
+	// The estimates are never lower than the counts.
+	assert.True(t, sketch.estimate("hot") >= 100)
+	assert.True(t, sketch.estimate("hot") < 110)
+	assert.True(t, sketch.estimate("1") >= 1)
+	assert.True(t, sketch.estimate("1") < 10)
+	assert.True(t, sketch.estimate("missing") < 10)
+
+	sketch.reset()
+	assert.Equal(t, uint32(0), sketch.estimate("hot"))
+	assert.Equal(t, uint32(1), sketch.add("hot"))
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"strings"
+)
+
+// SlotCount is the number of hash slots of a redis cluster.
+const SlotCount = 16384
+
+// HashTag returns the part of the key that is hashed to find the cluster slot.
+// It is the content of the first non empty "{...}" section, or the whole key otherwise.
+// Keys sharing the same hash tag are stored in the same slot,
+// so they can be used together in multi-key commands and scripts.
+func HashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+
+	return key[start+1 : start+1+end]
+}
+
+// Slot returns the cluster hash slot of the key.
+func Slot(key string) int {
+	return int(crc16(HashTag(key))) % SlotCount
+}
+
+// crc16 returns the CRC16-CCITT (XMODEM) checksum used by redis cluster.
+func crc16(data string) uint16 {
+	var crc uint16
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
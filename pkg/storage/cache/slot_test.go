@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashTag(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "Without hash tag", key: "user:1000", want: "user:1000"},
+		{name: "With hash tag", key: "{user1000}.following", want: "user1000"},
+		{name: "Empty hash tag", key: "foo{}{bar}", want: "foo{}{bar}"},
+		{name: "Nested brace", key: "foo{{bar}}zap", want: "{bar"},
+		{name: "First hash tag only", key: "foo{bar}{zap}", want: "bar"},
+		{name: "Unclosed brace", key: "foo{bar", want: "foo{bar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HashTag(tt.key))
+		})
+	}
+}
+
+func TestSlot(t *testing.T) {
+	assert.Equal(t, 12182, Slot("foo"))
+	assert.Equal(t, 12739, Slot("123456789"))
+	assert.Equal(t, Slot("{user1000}.following"), Slot("{user1000}.followers"))
+	assert.Equal(t, Slot("user1000"), Slot("{user1000}.following"))
+}
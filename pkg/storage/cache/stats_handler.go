@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// Labels of the prometheus metrics.
+const (
+	promLabelCommand = "command"
+	promLabelCode    = "code"
+	promLabelBound   = "le"
+)
+
+// promLabelEscaper escapes the label values of the prometheus text format.
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// StatsHandler returns a handler writing the stats of the instrumented clients in the prometheus text format,
+// the clients are identified by the client label.
+func StatsHandler(clients ...StatsReporterItf) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+		stats := make([]CacheStats, 0, len(clients))
+		for _, client := range clients {
+			stats = append(stats, client.Stats())
+		}
+
+		res.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = writePrometheus(res, stats)
+	})
+}
+
+// writePrometheus writes the stats in the prometheus text format.
+func writePrometheus(w io.Writer, stats []CacheStats) error {
+	var (
+		calls    = &promFamily{name: "cache_commands_total", kind: "counter", help: "Number of commands."}
+		hits     = &promFamily{name: "cache_command_hits_total", kind: "counter", help: "Number of lookups finding the key."}
+		misses   = &promFamily{name: "cache_command_misses_total", kind: "counter", help: "Number of lookups missing the key."}
+		errs     = &promFamily{name: "cache_command_errors_total", kind: "counter", help: "Number of failed commands by error code."}
+		duration = &promFamily{name: "cache_command_duration_seconds", kind: "histogram", help: "Latency of the commands."}
+		families = []*promFamily{calls, hits, misses, errs, duration}
+	)
+	for _, v := range stats {
+		commands := make([]string, 0, len(v.Commands))
+		for command := range v.Commands {
+			commands = append(commands, command)
+		}
+		sort.Strings(commands)
+
+		for _, command := range commands {
+			cmd := v.Commands[command]
+			labels := []string{tags.Client, v.Client, promLabelCommand, command}
+			calls.add("", float64(cmd.Calls), labels...)
+			hits.add("", float64(cmd.Hits), labels...)
+			misses.add("", float64(cmd.Misses), labels...)
+
+			codes := make([]string, 0, len(cmd.Errors))
+			for code := range cmd.Errors {
+				codes = append(codes, string(code))
+			}
+			sort.Strings(codes)
+			for _, code := range codes {
+				label := code
+				if label == "" {
+					label = "unknown"
+				}
+				errs.add("", float64(cmd.Errors[errorx.Code(code)]), append(labels, promLabelCode, label)...)
+			}
+
+			for i, bound := range v.Buckets {
+				if i < len(cmd.LatencyBuckets) {
+					bucketLabels := append(labels, promLabelBound, promFloat(bound.Seconds()))
+					duration.add("_bucket", float64(cmd.LatencyBuckets[i]), bucketLabels...)
+				}
+			}
+			duration.add("_bucket", float64(cmd.Calls), append(labels, promLabelBound, "+Inf")...)
+			duration.add("_sum", cmd.Latency.Seconds(), labels...)
+			duration.add("_count", float64(cmd.Calls), labels...)
+		}
+	}
+
+	families = append(families, poolFamilies(stats)...)
+	families = append(families, ristrettoFamilies(stats)...)
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		family.write(&buf)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// poolFamilies returns the metrics of the connection pools.
+func poolFamilies(stats []CacheStats) []*promFamily {
+	var (
+		open = &promFamily{
+			name: "cache_pool_" + tags.OpenConnections, kind: "gauge", help: "Number of connections in the pool.",
+		}
+		inUse = &promFamily{
+			name: "cache_pool_" + tags.InUse, kind: "gauge", help: "Number of connections in use.",
+		}
+		idle = &promFamily{
+			name: "cache_pool_" + tags.Idle, kind: "gauge", help: "Number of idle connections.",
+		}
+		waitCount = &promFamily{
+			name: "cache_pool_" + tags.WaitCount + "_total", kind: "counter", help: "Number of waits for a connection.",
+		}
+		waitDuration = &promFamily{
+			name: "cache_pool_" + tags.WaitDuration + "_seconds_total", kind: "counter", help: "Time spent waiting for a connection.",
+		}
+		timeouts = &promFamily{
+			name: "cache_pool_timeouts_total", kind: "counter", help: "Number of waits for a connection timing out.",
+		}
+	)
+	for _, v := range stats {
+		if v.Pool == nil {
+			continue
+		}
+
+		open.add("", float64(v.Pool.OpenConnections), tags.Client, v.Client)
+		inUse.add("", float64(v.Pool.InUse), tags.Client, v.Client)
+		idle.add("", float64(v.Pool.Idle), tags.Client, v.Client)
+		waitCount.add("", float64(v.Pool.WaitCount), tags.Client, v.Client)
+		waitDuration.add("", v.Pool.WaitDuration.Seconds(), tags.Client, v.Client)
+		timeouts.add("", float64(v.Pool.Timeouts), tags.Client, v.Client)
+	}
+
+	return []*promFamily{open, inUse, idle, waitCount, waitDuration, timeouts}
+}
+
+// ristrettoFamilies returns the metrics of the in-process caches.
+func ristrettoFamilies(stats []CacheStats) []*promFamily {
+	counters := []struct {
+		family *promFamily
+		value  func(m *RistrettoMetrics) uint64
+	}{
+		{newPromCounter("hits", "Number of lookups finding the key."), func(m *RistrettoMetrics) uint64 { return m.Hits }},
+		{newPromCounter("misses", "Number of lookups missing the key."), func(m *RistrettoMetrics) uint64 { return m.Misses }},
+		{newPromCounter("keys_added", "Number of added keys."), func(m *RistrettoMetrics) uint64 { return m.KeysAdded }},
+		{newPromCounter("keys_updated", "Number of updated keys."), func(m *RistrettoMetrics) uint64 { return m.KeysUpdated }},
+		{newPromCounter("keys_evicted", "Number of evicted keys."), func(m *RistrettoMetrics) uint64 { return m.KeysEvicted }},
+		{newPromCounter("cost_added", "Cost of the added keys."), func(m *RistrettoMetrics) uint64 { return m.CostAdded }},
+		{newPromCounter("cost_evicted", "Cost of the evicted keys."), func(m *RistrettoMetrics) uint64 { return m.CostEvicted }},
+		{newPromCounter("sets_dropped", "Number of dropped sets."), func(m *RistrettoMetrics) uint64 { return m.SetsDropped }},
+		{newPromCounter("sets_rejected", "Number of sets rejected by the policy."), func(m *RistrettoMetrics) uint64 { return m.SetsRejected }},
+		{newPromCounter("gets_dropped", "Number of dropped gets."), func(m *RistrettoMetrics) uint64 { return m.GetsDropped }},
+		{newPromCounter("gets_kept", "Number of kept gets."), func(m *RistrettoMetrics) uint64 { return m.GetsKept }},
+	}
+	ratio := &promFamily{name: "cache_ristretto_hit_ratio", kind: "gauge", help: "Ratio of the hits to the lookups."}
+
+	families := make([]*promFamily, 0, len(counters)+1)
+	for _, v := range stats {
+		if v.Ristretto == nil {
+			continue
+		}
+
+		for _, counter := range counters {
+			counter.family.add("", float64(counter.value(v.Ristretto)), tags.Client, v.Client)
+		}
+		ratio.add("", v.Ristretto.Ratio, tags.Client, v.Client)
+	}
+	for _, counter := range counters {
+		families = append(families, counter.family)
+	}
+
+	return append(families, ratio)
+}
+
+// promFamily is a prometheus metric with its samples.
+type promFamily struct {
+	name    string
+	kind    string
+	help    string
+	samples []promSample
+}
+
+// promSample is a sample of a metric, the suffix is appended to the name of the metric, e.g. "_bucket".
+type promSample struct {
+	suffix string
+	labels []string
+	value  float64
+}
+
+// newPromCounter returns the counter of an in-process cache metric.
+func newPromCounter(name, help string) *promFamily {
+	return &promFamily{name: "cache_ristretto_" + name + "_total", kind: "counter", help: help}
+}
+
+// add adds a sample with the labels, a list of label names and values.
+func (f *promFamily) add(suffix string, value float64, labels ...string) {
+	f.samples = append(f.samples, promSample{
+		suffix: suffix,
+		labels: append([]string(nil), labels...),
+		value:  value,
+	})
+}
+
+// write writes the metric, unless it has no samples.
+func (f *promFamily) write(buf *bytes.Buffer) {
+	if len(f.samples) == 0 {
+		return
+	}
+
+	buf.WriteString("# HELP " + f.name + " " + f.help + "\n")
+	buf.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+	for _, v := range f.samples {
+		buf.WriteString(f.name + v.suffix)
+		if len(v.labels) > 0 {
+			buf.WriteString("{")
+			for i := 0; i+1 < len(v.labels); i += 2 {
+				if i > 0 {
+					buf.WriteString(",")
+				}
+				buf.WriteString(v.labels[i] + `="` + promLabelEscaper.Replace(v.labels[i+1]) + `"`)
+			}
+			buf.WriteString("}")
+		}
+		buf.WriteString(" " + promFloat(v.value) + "\n")
+	}
+}
+
+// promFloat formats a value of the prometheus text format.
+func promFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
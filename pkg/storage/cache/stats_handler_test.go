@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redis := NewMockStatsReporterItf(ctrl)
+	redis.EXPECT().Stats().Return(CacheStats{
+		Client: `session "main"`,
+		Commands: map[string]CommandStats{
+			"Get": {
+				Calls:          3,
+				Hits:           1,
+				Misses:         1,
+				Errors:         map[errorx.Code]uint64{errorx.CodeGateway: 1},
+				Latency:        1500 * time.Millisecond,
+				LatencyBuckets: []uint64{2, 3},
+			},
+		},
+		Buckets: []time.Duration{time.Millisecond, time.Second},
+		Pool:    &PoolStats{OpenConnections: 3, InUse: 1, Idle: 2, WaitCount: 4, WaitDuration: 2 * time.Second},
+	})
+	local := NewMockStatsReporterItf(ctrl)
+	local.EXPECT().Stats().Return(CacheStats{
+		Client:    "local",
+		Commands:  map[string]CommandStats{},
+		Ristretto: &RistrettoMetrics{Hits: 3, Misses: 1, Ratio: 0.75},
+	})
+
+	rec := httptest.NewRecorder()
+	StatsHandler(redis, local).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE cache_commands_total counter\n",
+		`cache_commands_total{client="session \"main\"",command="Get"} 3` + "\n",
+		`cache_command_hits_total{client="session \"main\"",command="Get"} 1` + "\n",
+		`cache_command_misses_total{client="session \"main\"",command="Get"} 1` + "\n",
+		`cache_command_errors_total{client="session \"main\"",command="Get",code="gateway"} 1` + "\n",
+		"# TYPE cache_command_duration_seconds histogram\n",
+		`cache_command_duration_seconds_bucket{client="session \"main\"",command="Get",le="0.001"} 2` + "\n",
+		`cache_command_duration_seconds_bucket{client="session \"main\"",command="Get",le="1"} 3` + "\n",
+		`cache_command_duration_seconds_bucket{client="session \"main\"",command="Get",le="+Inf"} 3` + "\n",
+		`cache_command_duration_seconds_sum{client="session \"main\"",command="Get"} 1.5` + "\n",
+		`cache_command_duration_seconds_count{client="session \"main\"",command="Get"} 3` + "\n",
+		`cache_pool_open_connections{client="session \"main\""} 3` + "\n",
+		`cache_pool_in_use{client="session \"main\""} 1` + "\n",
+		`cache_pool_idle{client="session \"main\""} 2` + "\n",
+		`cache_pool_wait_count_total{client="session \"main\""} 4` + "\n",
+		`cache_pool_wait_duration_seconds_total{client="session \"main\""} 2` + "\n",
+		`cache_ristretto_hits_total{client="local"} 3` + "\n",
+		`cache_ristretto_misses_total{client="local"} 1` + "\n",
+		`cache_ristretto_hit_ratio{client="local"} 0.75` + "\n",
+	} {
+		assert.Contains(t, body, want)
+	}
+	assert.Equal(t, 1, strings.Count(body, "# TYPE cache_commands_total"))
+	assert.NotContains(t, body, `cache_pool_idle{client="local"}`)
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// Store is a byte storage used by Client to keep the encoded values.
+// Missing key is reported as an error with errorx.CodeNotFound.
+type Store interface {
+	// Get gets the value of a key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set sets the value of a key that will expire after the ttl has passed.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Del deletes a key.
+	Del(ctx context.Context, key string) error
+}
+
+// RedisStore is a store backed by a redis client.
+type RedisStore struct {
+	redis RedisItf
+}
+
+// NewRedisStore returns a store backed by a redis client.
+func NewRedisStore(redis RedisItf) *RedisStore {
+	return &RedisStore{
+		redis: redis,
+	}
+}
+
+// Get gets the value of a key.
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	const op errorx.Op = "cache/RedisStore.Get"
+
+	res, err := s.redis.Get(ctx, key)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return res, nil
+}
+
+// Set sets the value of a key that will expire after the ttl has passed.
+// Redis ttl is in seconds, any remaining fraction of a second is rounded up.
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	const op errorx.Op = "cache/RedisStore.Set"
+
+	if ttl <= 0 {
+		return errorx.E("ttl must be greater than zero", op, errorx.CodeInvalid)
+	}
+
+	if err := s.redis.SetEX(ctx, key, toSeconds(ttl), string(value)); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// Del deletes a key.
+func (s *RedisStore) Del(ctx context.Context, key string) error {
+	const op errorx.Op = "cache/RedisStore.Del"
+
+	if _, err := s.redis.Del(ctx, key); err != nil {
+		return errorx.E(err, op)
+	}
+
+	return nil
+}
+
+// RistrettoStore is a store backed by an in-process ristretto client.
+type RistrettoStore struct {
+	local RistrettoItf
+}
+
+// NewRistrettoStore returns a store backed by an in-process ristretto client.
+func NewRistrettoStore(local RistrettoItf) *RistrettoStore {
+	return &RistrettoStore{
+		local: local,
+	}
+}
+
+// Get gets the value of a key.
+func (s *RistrettoStore) Get(ctx context.Context, key string) ([]byte, error) {
+	const op errorx.Op = "cache/RistrettoStore.Get"
+
+	res, err := s.local.Lookup(ctx, key)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	value, ok := res.([]byte)
+	if !ok {
+		return nil, errorx.E("value is not stored by the store", op, errorx.CodeInvalid)
+	}
+
+	return value, nil
+}
+
+// Set sets the value of a key that will expire after the ttl has passed.
+// A zero ttl means the value never expires.
+func (s *RistrettoStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	const op errorx.Op = "cache/RistrettoStore.Set"
+
+	if ttl < 0 {
+		return errorx.E("ttl must not be negative", op, errorx.CodeInvalid)
+	}
+
+	if !s.local.SetEX(ctx, key, value, ttl) {
+		return errorx.E("value is dropped by the cache policy", op, errorx.CodeConflict)
+	}
+
+	return nil
+}
+
+// Del deletes a key.
+func (s *RistrettoStore) Del(ctx context.Context, key string) error {
+	s.local.Del(ctx, key)
+	return nil
+}
+
+// toSeconds converts the duration into redis seconds rounded up.
+func toSeconds(ttl time.Duration) int64 {
+	seconds := int64(ttl / time.Second)
+	if ttl%time.Second != 0 {
+		seconds++
+	}
+
+	return seconds
+}
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// StreamMessage is an entry of a redis stream.
+type StreamMessage struct {
+	ID     string
+	Values map[string]string
+}
+
+// PendingMessage is a stream entry delivered to a consumer of a group, but not acknowledged yet.
+type PendingMessage struct {
+	ID       string
+	Consumer string
+	// Idle is the time passed since the message was last delivered.
+	Idle time.Duration
+	// Deliveries is the number of times the message has been delivered.
+	Deliveries int64
+}
+
+// isBusyGroup reports whether the error is caused by a consumer group that already exists.
+func isBusyGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
+
+// streamValues converts the values into the field-value arguments of XADD, sorted by field.
+func streamValues(values map[string]string) []interface{} {
+	fields := make([]string, 0, len(values))
+	for k := range values {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	res := make([]interface{}, 0, len(values)*2)
+	for _, k := range fields {
+		res = append(res, k, values[k])
+	}
+	return res
+}
+
+// replyStreamMessages converts a redigo reply of XRANGE or XCLAIM into []StreamMessage form.
+// The entry deleted from the stream has nil values.
+func replyStreamMessages(reply interface{}) ([]StreamMessage, error) {
+	const op errorx.Op = "cache.replyStreamMessages"
+
+	entries, ok := reply.([]interface{})
+	if !ok {
+		return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+	}
+
+	res := make([]StreamMessage, 0, len(entries))
+	for _, v := range entries {
+		entry, ok := v.([]interface{})
+		if !ok || len(entry) != 2 {
+			return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+		}
+
+		id, err := replyBytes(entry[0])
+		if err != nil {
+			return nil, errorx.E(err, op, errorx.CodeInvalid)
+		}
+
+		msg := StreamMessage{ID: string(id)}
+		if entry[1] != nil {
+			fields, ok := entry[1].([]interface{})
+			if !ok || len(fields)%2 != 0 {
+				return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+			}
+
+			msg.Values = make(map[string]string, len(fields)/2)
+			for i := 0; i < len(fields); i += 2 {
+				field, err := replyBytes(fields[i])
+				if err != nil {
+					return nil, errorx.E(err, op, errorx.CodeInvalid)
+				}
+				value, err := replyBytes(fields[i+1])
+				if err != nil {
+					return nil, errorx.E(err, op, errorx.CodeInvalid)
+				}
+				msg.Values[string(field)] = string(value)
+			}
+		}
+
+		res = append(res, msg)
+	}
+
+	return res, nil
+}
+
+// replyStreams converts a redigo reply of XREAD or XREADGROUP of a single stream into []StreamMessage form.
+// Nil reply of a blocking read without new entry is returned as an empty result.
+func replyStreams(reply interface{}) ([]StreamMessage, error) {
+	const op errorx.Op = "cache.replyStreams"
+
+	if reply == nil {
+		return []StreamMessage{}, nil
+	}
+
+	streams, ok := reply.([]interface{})
+	if !ok {
+		return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+	}
+
+	res := []StreamMessage{}
+	for _, v := range streams {
+		stream, ok := v.([]interface{})
+		if !ok || len(stream) != 2 {
+			return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+		}
+
+		messages, err := replyStreamMessages(stream[1])
+		if err != nil {
+			return nil, errorx.E(err, op)
+		}
+		res = append(res, messages...)
+	}
+
+	return res, nil
+}
+
+// replyPendingMessages converts a redigo reply of the extended XPENDING into []PendingMessage form.
+func replyPendingMessages(reply interface{}) ([]PendingMessage, error) {
+	const op errorx.Op = "cache.replyPendingMessages"
+
+	entries, ok := reply.([]interface{})
+	if !ok {
+		return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+	}
+
+	res := make([]PendingMessage, len(entries))
+	for i, v := range entries {
+		entry, ok := v.([]interface{})
+		if !ok || len(entry) != 4 {
+			return nil, errorx.E("unexpected reply type", op, errorx.CodeInvalid)
+		}
+
+		id, err := replyBytes(entry[0])
+		if err != nil {
+			return nil, errorx.E(err, op, errorx.CodeInvalid)
+		}
+		consumer, err := replyBytes(entry[1])
+		if err != nil {
+			return nil, errorx.E(err, op, errorx.CodeInvalid)
+		}
+		idle, err := replyInt64(entry[2])
+		if err != nil {
+			return nil, errorx.E(err, op)
+		}
+		deliveries, err := replyInt64(entry[3])
+		if err != nil {
+			return nil, errorx.E(err, op)
+		}
+
+		res[i] = PendingMessage{
+			ID:         string(id),
+			Consumer:   string(consumer),
+			Idle:       time.Duration(idle) * time.Millisecond,
+			Deliveries: deliveries,
+		}
+	}
+
+	return res, nil
+}
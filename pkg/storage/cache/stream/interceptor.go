@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/stack"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+type (
+	// Handler is the handler definition to process a message.
+	// The message is acknowledged when the handler returns nil,
+	// otherwise it is delivered again once the visibility timeout has passed.
+	Handler func(ctx context.Context, msg *Message) error
+
+	// Interceptor is the middleware that will be executed before the current handler.
+	Interceptor func(ctx context.Context, msg *Message, handler Handler) error
+)
+
+// Chain returns a single interceptor from multiple interceptors.
+func Chain(interceptors ...Interceptor) Interceptor {
+	n := len(interceptors)
+
+	return func(ctx context.Context, msg *Message, handler Handler) error {
+		chainer := func(currentInter Interceptor, currentHandler Handler) Handler {
+			return func(currentCtx context.Context, currentMsg *Message) error {
+				return currentInter(currentCtx, currentMsg, currentHandler)
+			}
+		}
+
+		chainedHandler := handler
+		for i := n - 1; i >= 0; i-- {
+			chainedHandler = chainer(interceptors[i], chainedHandler)
+		}
+
+		return chainedHandler(ctx, msg)
+	}
+}
+
+// Recover is a middleware that recovers the worker from a handler panic.
+// The panic is returned as an error, so the message is delivered again.
+// The worker always runs it first, before the given interceptors.
+func Recover() Interceptor {
+	return func(ctx context.Context, msg *Message, handler Handler) (err error) {
+		const op errorx.Op = "stream.Recover"
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = errorx.E(fmt.Errorf("%v", r), op, errorx.CodeInternal, errorx.Fields{
+					tags.Key:        msg.Stream,
+					tags.Panic:      r,
+					tags.StackTrace: stack.ToArr(stack.Trim(debug.Stack())),
+				})
+				logx.ERR(ctx, err, string(op)+" recovered")
+			}
+		}()
+
+		return handler(ctx, msg)
+	}
+}
+
+// Logger is a middleware that logs the message processing.
+func Logger() Interceptor {
+	return func(ctx context.Context, msg *Message, handler Handler) error {
+		const op errorx.Op = "stream.Logger"
+
+		start := time.Now()
+		err := handler(ctx, msg)
+		if err != nil {
+			logx.ERR(ctx, errorx.E(err, op, errorx.Fields{
+				tags.Key:    msg.Stream,
+				tags.Detail: msg.ID,
+				tags.Count:  msg.Deliveries,
+			}), fmt.Sprintf("Operation stream %s failed", msg.Stream))
+		} else {
+			logx.DBG(
+				ctx,
+				map[string]string{tags.Latency: time.Since(start).String()},
+				fmt.Sprintf("Operation stream %s success", msg.Stream),
+			)
+		}
+		return err
+	}
+}
+
+// RequestID is a middleware that inject request id to the context if it doesn't exists.
+func RequestID(ctx context.Context, msg *Message, handler Handler) error {
+	return handler(logx.ContextWithRequestID(ctx), msg)
+}
@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) Interceptor {
+		return func(ctx context.Context, msg *Message, handler Handler) error {
+			calls = append(calls, name)
+			return handler(ctx, msg)
+		}
+	}
+
+	err := Chain(interceptor("a"), interceptor("b"))(context.Background(), &Message{}, func(context.Context, *Message) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "handler"}, calls)
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  Handler
+		wantCode errorx.Code
+	}{
+		{
+			name: "Panic",
+			handler: func(context.Context, *Message) error {
+				panic("boom")
+			},
+			wantCode: errorx.CodeInternal,
+		},
+		{
+			name: "Error",
+			handler: func(context.Context, *Message) error {
+				return errorx.E("failed", errorx.CodeGateway)
+			},
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Success",
+			handler: func(context.Context, *Message) error {
+				return nil
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Chain(Recover(), Logger(), RequestID)(context.Background(), &Message{Stream: "jobs"}, tt.handler)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+		})
+	}
+}
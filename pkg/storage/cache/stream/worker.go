@@ -0,0 +1,372 @@
+// Package stream provides a worker processing the messages of a redis stream through a consumer group,
+// to use the stream as a durable work queue.
+package stream
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/peractio/gdk/pkg/tags"
+	"github.com/segmentio/ksuid"
+)
+
+// Fields added to the message moved to the dead letter stream.
+const (
+	FieldOriginID   = "origin_id"
+	FieldDeliveries = "deliveries"
+)
+
+// reclaimCount is the maximum number of pending messages inspected on every reclaim.
+const reclaimCount = 100
+
+// Message is a message delivered to the worker.
+type Message struct {
+	cache.StreamMessage
+	Stream string
+	Group  string
+	// Deliveries is the number of times the message has been delivered, 1 on the first delivery.
+	Deliveries int64
+}
+
+// Config configuration.
+type Config struct {
+	// Consumer is the consumer name of the worker in the group, unique for every worker.
+	// Empty string means the hostname followed by a random id.
+	Consumer string
+	// Concurrency is the maximum number of messages processed at the same time.
+	Concurrency int
+	// Block is the maximum time to wait for new messages in a single read,
+	// it bounds the time to stop the worker.
+	Block time.Duration
+	// VisibilityTimeout is the time given to the handler to process a message,
+	// the message still pending after the timeout is delivered again.
+	VisibilityTimeout time.Duration
+	// ReclaimInterval is the interval between the reclaims of the idle pending messages,
+	// left by the failed handlers or the stopped workers.
+	ReclaimInterval time.Duration
+	// MaxDeliveries is the number of deliveries after which the message is moved to the dead letter stream.
+	// Zero value means the message is delivered until it is processed.
+	MaxDeliveries int64
+	// DeadLetterStream is the stream receiving the messages delivered too many times.
+	// Empty string means the stream name followed by ":dead".
+	DeadLetterStream string
+}
+
+// DefaultConfig is the default configuration of the worker.
+var DefaultConfig = Config{
+	Consumer:          "",
+	Concurrency:       1,
+	Block:             2 * time.Second,
+	VisibilityTimeout: 30 * time.Second,
+	ReclaimInterval:   10 * time.Second,
+	MaxDeliveries:     5,
+	DeadLetterStream:  "",
+}
+
+// Worker processes the messages of a stream as a consumer of a group.
+// Every message is processed by a single worker of the group,
+// and acknowledged once the handler has succeeded.
+type Worker struct {
+	streamer cache.StreamerItf
+	stream   string
+	group    string
+	handler  Handler
+	config   Config
+
+	// slots limits the number of messages processed at the same time.
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewWorker returns a worker with the default configuration.
+// The interceptors are executed before the handler in the given order, after Recover.
+func NewWorker(
+	streamer cache.StreamerItf,
+	stream, group string,
+	handler Handler,
+	interceptors ...Interceptor,
+) *Worker {
+	return NewWorkerWithConfig(streamer, stream, group, handler, DefaultConfig, interceptors...)
+}
+
+// NewWorkerWithConfig returns a worker.
+// The interceptors are executed before the handler in the given order, after Recover.
+func NewWorkerWithConfig(
+	streamer cache.StreamerItf,
+	stream, group string,
+	handler Handler,
+	config Config,
+	interceptors ...Interceptor,
+) *Worker {
+	// Defaults
+	if config.Consumer == "" {
+		hostname, _ := os.Hostname()
+		config.Consumer = hostname + "-" + ksuid.New().String()
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConfig.Concurrency
+	}
+	if config.Block <= 0 {
+		config.Block = DefaultConfig.Block
+	}
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = DefaultConfig.VisibilityTimeout
+	}
+	if config.ReclaimInterval <= 0 {
+		config.ReclaimInterval = DefaultConfig.ReclaimInterval
+	}
+	if config.DeadLetterStream == "" {
+		config.DeadLetterStream = stream + ":dead"
+	}
+
+	// The panics are always recovered, so a bad message is left pending
+	// instead of crashing every worker it is delivered to.
+	interceptor, next := Chain(append([]Interceptor{Recover()}, interceptors...)...), handler
+	handler = func(ctx context.Context, msg *Message) error {
+		return interceptor(ctx, msg, next)
+	}
+
+	return &Worker{
+		streamer: streamer,
+		stream:   stream,
+		group:    group,
+		handler:  handler,
+		config:   config,
+		slots:    make(chan struct{}, config.Concurrency),
+	}
+}
+
+// Consumer returns the consumer name of the worker.
+func (w *Worker) Consumer() string {
+	return w.config.Consumer
+}
+
+// Run creates the consumer group if it doesn't exist, and processes the messages until the context is done.
+// A new group reads the stream from the start.
+// On stop, the worker stops reading and waits for the messages being processed.
+func (w *Worker) Run(ctx context.Context) error {
+	const op errorx.Op = "stream/Worker.Run"
+
+	if err := w.streamer.XGroupCreate(ctx, w.stream, w.group, "0"); err != nil {
+		return errorx.E(err, op)
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.ReclaimInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.reclaim(ctx)
+			}
+		}
+	}()
+
+	for {
+		n := w.acquire(ctx)
+		if n == 0 {
+			break
+		}
+
+		msgs, err := w.streamer.XReadGroup(ctx, w.stream, w.group, w.config.Consumer, int64(n), w.config.Block)
+		if err != nil {
+			w.release(n)
+			if ctx.Err() != nil {
+				break
+			}
+
+			logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: w.stream}), string(op)+" read failed")
+			sleepFor(ctx, w.config.Block)
+			continue
+		}
+
+		w.release(n - len(msgs))
+		for _, v := range msgs {
+			w.process(ctx, v, 1)
+		}
+	}
+
+	w.wg.Wait()
+	return nil
+}
+
+// reclaim claims the pending messages idle for longer than the visibility timeout,
+// up to the free slots, and moves the messages delivered too many times to the dead letter stream.
+func (w *Worker) reclaim(ctx context.Context) {
+	const op errorx.Op = "stream/Worker.reclaim"
+
+	pending, err := w.streamer.XPending(ctx, w.stream, w.group, reclaimCount)
+	if err != nil {
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: w.stream}), string(op)+" failed")
+		return
+	}
+
+	var (
+		ids        []string
+		deliveries = make(map[string]int64)
+	)
+	for _, v := range pending {
+		if v.Idle < w.config.VisibilityTimeout {
+			continue
+		}
+
+		if w.config.MaxDeliveries > 0 && v.Deliveries >= w.config.MaxDeliveries {
+			w.deadLetter(ctx, v)
+			continue
+		}
+
+		ids = append(ids, v.ID)
+		deliveries[v.ID] = v.Deliveries
+	}
+
+	n := w.tryAcquire(len(ids))
+	if n == 0 {
+		return
+	}
+
+	// Other workers may have claimed the messages since they were listed,
+	// XCLAIM only returns the messages still idle.
+	msgs, err := w.streamer.XClaim(ctx, w.stream, w.group, w.config.Consumer, w.config.VisibilityTimeout, ids[:n]...)
+	if err != nil {
+		w.release(n)
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: w.stream}), string(op)+" failed")
+		return
+	}
+
+	w.release(n - len(msgs))
+	for _, v := range msgs {
+		// The message deleted from the stream can't be processed.
+		if v.Values == nil {
+			w.release(1)
+			w.ack(ctx, v.ID)
+			continue
+		}
+
+		w.process(ctx, v, deliveries[v.ID]+1)
+	}
+}
+
+// deadLetter moves a pending message to the dead letter stream.
+func (w *Worker) deadLetter(ctx context.Context, pending cache.PendingMessage) {
+	const op errorx.Op = "stream/Worker.deadLetter"
+
+	// Claim the message first, so it is moved by a single worker.
+	msgs, err := w.streamer.XClaim(ctx, w.stream, w.group, w.config.Consumer, w.config.VisibilityTimeout, pending.ID)
+	if err != nil {
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: w.stream}), string(op)+" failed")
+		return
+	}
+	if len(msgs) == 0 {
+		return
+	}
+
+	if msgs[0].Values != nil {
+		values := make(map[string]string, len(msgs[0].Values)+2)
+		for k, v := range msgs[0].Values {
+			values[k] = v
+		}
+		values[FieldOriginID] = pending.ID
+		values[FieldDeliveries] = strconv.FormatInt(pending.Deliveries, 10)
+
+		if _, err := w.streamer.XAdd(ctx, w.config.DeadLetterStream, 0, values); err != nil {
+			logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: w.stream}), string(op)+" failed")
+			return
+		}
+	}
+
+	w.ack(ctx, pending.ID)
+	logx.WRN(ctx, errorx.E("message delivered too many times", op, errorx.Fields{
+		tags.Key:    w.stream,
+		tags.Detail: pending.ID,
+		tags.Count:  pending.Deliveries,
+	}), string(op)+" message moved to "+w.config.DeadLetterStream)
+}
+
+// process processes a message in a new goroutine holding an acquired slot,
+// and acknowledges the message once the handler has succeeded.
+// The handler context isn't canceled when the worker stops, so the message can be completed.
+func (w *Worker) process(ctx context.Context, msg cache.StreamMessage, deliveries int64) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer w.release(1)
+
+		hctx, cancel := context.WithTimeout(logx.SetRequestID(context.Background(), logx.GetRequestID(ctx)), w.config.VisibilityTimeout)
+		defer cancel()
+
+		err := w.handler(hctx, &Message{
+			StreamMessage: msg,
+			Stream:        w.stream,
+			Group:         w.group,
+			Deliveries:    deliveries,
+		})
+		if err != nil {
+			return
+		}
+
+		w.ack(hctx, msg.ID)
+	}()
+}
+
+// ack acknowledges a message.
+func (w *Worker) ack(ctx context.Context, id string) {
+	const op errorx.Op = "stream/Worker.ack"
+
+	if _, err := w.streamer.XAck(ctx, w.stream, w.group, id); err != nil {
+		logx.WRN(ctx, errorx.E(err, op, errorx.Fields{tags.Key: w.stream, tags.Detail: id}), string(op)+" failed")
+	}
+}
+
+// acquire waits for a free slot, and acquires the other free slots without waiting.
+// It returns the number of acquired slots, or zero when the context is done.
+func (w *Worker) acquire(ctx context.Context) int {
+	select {
+	case <-ctx.Done():
+		return 0
+	case w.slots <- struct{}{}:
+	}
+
+	return 1 + w.tryAcquire(cap(w.slots)-1)
+}
+
+// tryAcquire acquires up to max free slots without waiting, and returns the number of acquired slots.
+func (w *Worker) tryAcquire(max int) int {
+	for n := 0; n < max; n++ {
+		select {
+		case w.slots <- struct{}{}:
+		default:
+			return n
+		}
+	}
+	return max
+}
+
+// release releases n acquired slots.
+func (w *Worker) release(n int) {
+	for i := 0; i < n; i++ {
+		<-w.slots
+	}
+}
+
+// sleepFor sleeps for the duration or until the context is done.
+func sleepFor(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,169 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/storage/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorker_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	streamer := cache.NewMockStreamerItf(ctrl)
+	streamer.EXPECT().XGroupCreate(gomock.Any(), "jobs", "group", "0").Return(nil)
+	gomock.InOrder(
+		streamer.EXPECT().XReadGroup(gomock.Any(), "jobs", "group", "consumer", int64(2), time.Second).
+			Return([]cache.StreamMessage{
+				{ID: "1-0", Values: map[string]string{"job": "ok"}},
+				{ID: "2-0", Values: map[string]string{"job": "fail"}},
+			}, nil),
+		streamer.EXPECT().XReadGroup(gomock.Any(), "jobs", "group", "consumer", gomock.Any(), time.Second).
+			DoAndReturn(func(ctx context.Context, _, _, _ string, _ int64, _ time.Duration) ([]cache.StreamMessage, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}),
+	)
+	// Only the processed message is acknowledged.
+	streamer.EXPECT().XAck(gomock.Any(), "jobs", "group", "1-0").Return(int64(1), nil)
+
+	var (
+		mu       sync.Mutex
+		received []*Message
+	)
+	handler := func(_ context.Context, msg *Message) error {
+		mu.Lock()
+		received = append(received, msg)
+		n := len(received)
+		mu.Unlock()
+
+		if n == 2 {
+			cancel()
+		}
+		if msg.Values["job"] == "fail" {
+			return errorx.E("job failed", errorx.CodeInternal)
+		}
+		return nil
+	}
+
+	worker := NewWorkerWithConfig(streamer, "jobs", "group", handler, Config{
+		Consumer:        "consumer",
+		Concurrency:     2,
+		Block:           time.Second,
+		ReclaimInterval: time.Hour,
+	}, Logger())
+
+	assert.NoError(t, worker.Run(ctx))
+	assert.Len(t, received, 2)
+	for _, v := range received {
+		assert.Equal(t, "jobs", v.Stream)
+		assert.Equal(t, "group", v.Group)
+		assert.Equal(t, int64(1), v.Deliveries)
+	}
+}
+
+func TestWorker_Run_groupError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	streamer := cache.NewMockStreamerItf(ctrl)
+	streamer.EXPECT().XGroupCreate(gomock.Any(), "jobs", "group", "0").
+		Return(errorx.E("WRONGTYPE", errorx.CodeGateway))
+
+	worker := NewWorker(streamer, "jobs", "group", func(context.Context, *Message) error { return nil })
+	err := worker.Run(context.Background())
+	assert.True(t, errorx.Is(errorx.CodeGateway, err))
+}
+
+func TestWorker_reclaim(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	streamer := cache.NewMockStreamerItf(ctrl)
+	streamer.EXPECT().XPending(gomock.Any(), "jobs", "group", int64(reclaimCount)).
+		Return([]cache.PendingMessage{
+			{ID: "1-0", Consumer: "other", Idle: time.Second, Deliveries: 1},
+			{ID: "2-0", Consumer: "other", Idle: time.Minute, Deliveries: 1},
+			{ID: "3-0", Consumer: "other", Idle: time.Minute, Deliveries: 3},
+			{ID: "4-0", Consumer: "other", Idle: time.Minute, Deliveries: 2},
+		}, nil)
+
+	// The message delivered too many times is moved to the dead letter stream.
+	streamer.EXPECT().XClaim(gomock.Any(), "jobs", "group", "consumer", 30*time.Second, "3-0").
+		Return([]cache.StreamMessage{{ID: "3-0", Values: map[string]string{"job": "dead"}}}, nil)
+	streamer.EXPECT().XAdd(gomock.Any(), "jobs:dead", int64(0), map[string]string{
+		"job":           "dead",
+		FieldOriginID:   "3-0",
+		FieldDeliveries: "3",
+	}).Return("5-0", nil)
+	streamer.EXPECT().XAck(gomock.Any(), "jobs", "group", "3-0").Return(int64(1), nil)
+
+	// The idle messages are claimed, the deleted one is acknowledged without being processed.
+	streamer.EXPECT().XClaim(gomock.Any(), "jobs", "group", "consumer", 30*time.Second, "2-0", "4-0").
+		Return([]cache.StreamMessage{
+			{ID: "2-0", Values: map[string]string{"job": "retry"}},
+			{ID: "4-0", Values: nil},
+		}, nil)
+	streamer.EXPECT().XAck(gomock.Any(), "jobs", "group", "4-0").Return(int64(1), nil)
+	streamer.EXPECT().XAck(gomock.Any(), "jobs", "group", "2-0").Return(int64(1), nil)
+
+	var received []*Message
+	worker := NewWorkerWithConfig(streamer, "jobs", "group", func(_ context.Context, msg *Message) error {
+		received = append(received, msg)
+		return nil
+	}, Config{
+		Consumer:          "consumer",
+		Concurrency:       2,
+		VisibilityTimeout: 30 * time.Second,
+		MaxDeliveries:     3,
+	})
+
+	worker.reclaim(context.Background())
+	worker.wg.Wait()
+
+	assert.Len(t, received, 1)
+	assert.Equal(t, "2-0", received[0].ID)
+	assert.Equal(t, int64(2), received[0].Deliveries)
+	assert.Len(t, worker.slots, 0)
+}
+
+func TestWorker_process_panic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The message of the panicking handler is left pending, without being acknowledged.
+	streamer := cache.NewMockStreamerItf(ctrl)
+	worker := NewWorkerWithConfig(streamer, "jobs", "group", func(context.Context, *Message) error {
+		panic("bad message")
+	}, Config{Consumer: "consumer", Concurrency: 1})
+
+	worker.slots <- struct{}{}
+	worker.process(context.Background(), cache.StreamMessage{ID: "1-0", Values: map[string]string{"job": "bad"}}, 1)
+	worker.wg.Wait()
+
+	assert.Len(t, worker.slots, 0)
+}
+
+func TestWorker_acquire(t *testing.T) {
+	worker := NewWorkerWithConfig(nil, "jobs", "group", nil, Config{Concurrency: 3})
+
+	assert.Equal(t, 3, worker.acquire(context.Background()))
+	assert.Equal(t, 0, worker.tryAcquire(1))
+
+	worker.release(2)
+	assert.Equal(t, 1, worker.tryAcquire(1))
+	assert.Equal(t, 1, worker.tryAcquire(5))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, 0, worker.acquire(ctx))
+}
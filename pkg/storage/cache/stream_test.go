@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamValues(t *testing.T) {
+	got := streamValues(map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, []interface{}{"a", "1", "b", "2"}, got)
+}
+
+func TestReplyStreams(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    interface{}
+		want     []StreamMessage
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Nil reply",
+			reply:    nil,
+			want:     []StreamMessage{},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Messages",
+			reply: []interface{}{
+				[]interface{}{
+					[]byte("jobs"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{[]byte("a"), []byte("1")}},
+						[]interface{}{[]byte("2-0"), nil},
+					},
+				},
+			},
+			want: []StreamMessage{
+				{ID: "1-0", Values: map[string]string{"a": "1"}},
+				{ID: "2-0", Values: nil},
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Odd number of fields",
+			reply: []interface{}{
+				[]interface{}{
+					[]byte("jobs"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{[]byte("a")}},
+					},
+				},
+			},
+			want:     nil,
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Unexpected reply",
+			reply:    []byte("OK"),
+			want:     nil,
+			wantCode: errorx.CodeInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replyStreams(tt.reply)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplyPendingMessages(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    interface{}
+		want     []PendingMessage
+		wantCode errorx.Code
+	}{
+		{
+			name: "Pending messages",
+			reply: []interface{}{
+				[]interface{}{[]byte("1-0"), []byte("consumer"), int64(1500), int64(2)},
+			},
+			want: []PendingMessage{
+				{ID: "1-0", Consumer: "consumer", Idle: 1500 * time.Millisecond, Deliveries: 2},
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Unexpected reply",
+			reply: []interface{}{
+				[]interface{}{[]byte("1-0"), []byte("consumer")},
+			},
+			want:     nil,
+			wantCode: errorx.CodeInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replyPendingMessages(tt.reply)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/peractio/gdk/pkg/logx"
+	"github.com/peractio/gdk/pkg/tags"
+)
+
+// Keys of the tag sets and the namespace versions.
+const (
+	tagKeyPrefix     = "tag:"
+	versionKeySuffix = ":version"
+)
+
+// InvalidateTag deletes the keys of a tag with the tag set, and evicts them from the first level of a MultiLevel store.
+// When the Tagger is a ScripterItf connected to a single instance, the keys and the tag set are deleted atomically,
+// so a key tagged meanwhile is either deleted or kept in the tag set.
+// In cluster mode, the tagged keys may live on other nodes than the tag set, so they are deleted by slot instead.
+func (c *Client) InvalidateTag(ctx context.Context, tag string) error {
+	const op errorx.Op = "cache/Client.InvalidateTag"
+
+	if c.config.Tagger == nil {
+		return errorx.E("missing tagger", op, errorx.CodeConfig)
+	}
+
+	keys, err := c.deleteTag(ctx, tagKeyPrefix+tag)
+	if err != nil {
+		return errorx.E(err, op, errorx.Fields{tags.Key: tag})
+	}
+
+	c.evict(ctx, keys)
+
+	logx.DBG(ctx, logx.KV{tags.Key: tag, tags.Count: len(keys)}, string(op)+" success")
+	return nil
+}
+
+// NamespaceKey returns the key in the current version of a namespace, "<namespace>:v<version>:<key>".
+// The keys of a namespace are invalidated at once by bumping its version, see InvalidateNamespace.
+// The version is read on every call, so the keys cached by the first level of a MultiLevel store
+// are never read again once the version is bumped.
+func (c *Client) NamespaceKey(ctx context.Context, namespace, key string) (string, error) {
+	const op errorx.Op = "cache/Client.NamespaceKey"
+
+	if c.config.Tagger == nil {
+		return "", errorx.E("missing tagger", op, errorx.CodeConfig)
+	}
+
+	var version int64
+	res, err := c.config.Tagger.Get(ctx, namespace+versionKeySuffix)
+	switch {
+	case IsNotFound(err):
+		// The namespace has never been invalidated.
+	case err != nil:
+		return "", errorx.E(err, op, errorx.Fields{tags.Key: namespace})
+	default:
+		if version, err = strconv.ParseInt(string(res), 10, 64); err != nil {
+			return "", errorx.E(err, op, errorx.Fields{tags.Key: namespace}, errorx.CodeInvalid)
+		}
+	}
+
+	return namespace + ":v" + strconv.FormatInt(version, 10) + ":" + key, nil
+}
+
+// InvalidateNamespace bumps the version of a namespace.
+// The keys of the former versions are never read again, and are left to expire after their ttl.
+// The version key has no ttl, so it should not be evicted by the redis maxmemory policy.
+func (c *Client) InvalidateNamespace(ctx context.Context, namespace string) error {
+	const op errorx.Op = "cache/Client.InvalidateNamespace"
+
+	if c.config.Tagger == nil {
+		return errorx.E("missing tagger", op, errorx.CodeConfig)
+	}
+
+	version, err := c.config.Tagger.Incr(ctx, namespace+versionKeySuffix)
+	if err != nil {
+		return errorx.E(err, op, errorx.Fields{tags.Key: namespace})
+	}
+
+	logx.DBG(ctx, logx.KV{tags.Key: namespace, tags.Detail: version}, string(op)+" success")
+	return nil
+}
+
+// tag adds a key to the tag sets, and refreshes their ttl.
+// The tag sets must outlive the key, so a ttl longer than the TagTTL is rejected.
+// When the Tagger is a ScripterItf, a key is added to a tag set with its ttl in a single atomic round trip,
+// so the tag set is never left without a ttl.
+func (c *Client) tag(ctx context.Context, key string, ttl time.Duration, keyTags []string) error {
+	const op errorx.Op = "cache/Client.tag"
+
+	if len(keyTags) == 0 {
+		return nil
+	}
+	if c.config.Tagger == nil {
+		return errorx.E("missing tagger", op, errorx.CodeConfig)
+	}
+	if ttl <= 0 || ttl > c.config.TagTTL {
+		return errorx.E("ttl of a tagged key must not be longer than the tag ttl", op, errorx.CodeInvalid)
+	}
+
+	seconds := toSeconds(c.config.TagTTL)
+	scripter, isScripter := c.config.Tagger.(ScripterItf)
+	for _, tag := range keyTags {
+		tagKey := tagKeyPrefix + tag
+		if isScripter {
+			if _, err := scripter.RunScript(ctx, ScriptTagKey, []string{tagKey}, key, seconds); err != nil {
+				return errorx.E(err, op, errorx.Fields{tags.Key: tag})
+			}
+			continue
+		}
+
+		if _, err := c.config.Tagger.SAdd(ctx, tagKey, key); err != nil {
+			return errorx.E(err, op, errorx.Fields{tags.Key: tag})
+		}
+		if _, err := c.config.Tagger.Expire(ctx, tagKey, seconds); err != nil {
+			return errorx.E(err, op, errorx.Fields{tags.Key: tag})
+		}
+	}
+
+	return nil
+}
+
+// deleteTag deletes the members of a tag set with the set itself, and returns the deleted members.
+func (c *Client) deleteTag(ctx context.Context, tagKey string) ([]string, error) {
+	const op errorx.Op = "cache/Client.deleteTag"
+
+	if scripter, ok := c.config.Tagger.(ScripterItf); ok && !isCluster(c.config.Tagger) {
+		res, err := scripter.RunScript(ctx, ScriptInvalidateTag, []string{tagKey})
+		if err != nil {
+			return nil, errorx.E(err, op)
+		}
+
+		keys, err := replyStrings(res)
+		if err != nil {
+			return nil, errorx.E(err, op, errorx.CodeGateway)
+		}
+
+		return keys, nil
+	}
+
+	keys, err := c.config.Tagger.SMembers(ctx, tagKey)
+	if err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	args := make([]interface{}, 0, len(keys)+1)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	if _, err := c.config.Tagger.Del(ctx, append(args, tagKey)...); err != nil {
+		return nil, errorx.E(err, op)
+	}
+
+	return keys, nil
+}
+
+// clusterItf is a client reporting whether it is connected to a cluster.
+type clusterItf interface {
+	isCluster() bool
+}
+
+// isCluster reports whether the client is connected to a cluster.
+// A script can only access the keys of a single slot of a cluster.
+func isCluster(client interface{}) bool {
+	c, ok := client.(clusterItf)
+	return ok && c.isCluster()
+}
+
+// evict removes the keys deleted from redis from the stores keeping a copy of them.
+func (c *Client) evict(ctx context.Context, keys []string) {
+	switch store := c.store.(type) {
+	case *RedisStore:
+		// The keys are already deleted.
+	case *MultiLevel:
+		store.evict(ctx, keys...)
+	default:
+		for _, key := range keys {
+			_ = c.store.Del(ctx, key)
+		}
+	}
+}
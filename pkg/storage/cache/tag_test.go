@@ -0,0 +1,339 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptTagger is a tagger running the scripts.
+type scriptTagger struct {
+	*MockTaggerItf
+	*MockScripterItf
+}
+
+// clusterTagger is a tagger running the scripts in cluster mode.
+type clusterTagger struct {
+	scriptTagger
+}
+
+func (clusterTagger) isCluster() bool {
+	return true
+}
+
+func TestClient_SetObject_Tags(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		tagger   func(ctrl *gomock.Controller) TaggerItf
+		mock     func(local *MockRistrettoItf)
+		wantCode errorx.Code
+	}{
+		{
+			name: "Missing tagger",
+			ttl:  time.Minute,
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				return nil
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name: "Ttl longer than the tag ttl",
+			ttl:  2 * time.Hour,
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				return NewMockTaggerItf(ctrl)
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name: "Ttl without expiry",
+			ttl:  0,
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				return NewMockTaggerItf(ctrl)
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name: "Tagging failed",
+			ttl:  time.Minute,
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				tagger := NewMockTaggerItf(ctrl)
+				tagger.EXPECT().SAdd(gomock.Any(), "tag:product:1", "key").Return(false, errorx.E("connection refused"))
+				return tagger
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeInternal,
+		},
+		{
+			name: "Tagging script failed",
+			ttl:  time.Minute,
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				scripter := NewMockScripterItf(ctrl)
+				scripter.EXPECT().RunScript(gomock.Any(), ScriptTagKey, []string{"tag:product:1"}, "key", int64(3600)).
+					Return(nil, errorx.E("connection refused"))
+				return scriptTagger{MockTaggerItf: NewMockTaggerItf(ctrl), MockScripterItf: scripter}
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeInternal,
+		},
+		{
+			name: "Success with script",
+			ttl:  time.Hour,
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				scripter := NewMockScripterItf(ctrl)
+				scripter.EXPECT().RunScript(gomock.Any(), ScriptTagKey, []string{"tag:product:1"}, "key", int64(3600)).Return(int64(1), nil)
+				scripter.EXPECT().RunScript(gomock.Any(), ScriptTagKey, []string{"tag:products"}, "key", int64(3600)).Return(int64(0), nil)
+				return scriptTagger{MockTaggerItf: NewMockTaggerItf(ctrl), MockScripterItf: scripter}
+			},
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().SetEX(gomock.Any(), "key", gomock.Any(), time.Hour).Return(true)
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Success",
+			ttl:  time.Minute,
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				tagger := NewMockTaggerItf(ctrl)
+				tagger.EXPECT().SAdd(gomock.Any(), "tag:product:1", "key").Return(true, nil)
+				tagger.EXPECT().Expire(gomock.Any(), "tag:product:1", int64(3600)).Return(true, nil)
+				tagger.EXPECT().SAdd(gomock.Any(), "tag:products", "key").Return(true, nil)
+				tagger.EXPECT().Expire(gomock.Any(), "tag:products", int64(3600)).Return(true, nil)
+				return tagger
+			},
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().SetEX(gomock.Any(), "key", gomock.Any(), time.Minute).Return(true)
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			local := NewMockRistrettoItf(ctrl)
+			tt.mock(local)
+
+			client := NewClientWithConfig(NewRistrettoStore(local), nil, ClientConfig{
+				Tagger: tt.tagger(ctrl),
+				TagTTL: time.Hour,
+			})
+			err := client.SetObject(context.Background(), "key", tt.ttl, codecTestValue{ID: 1}, "product:1", "products")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+		})
+	}
+}
+
+func TestClient_InvalidateTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tagger   func(ctrl *gomock.Controller) TaggerItf
+		mock     func(local *MockRistrettoItf)
+		wantCode errorx.Code
+	}{
+		{
+			name: "Missing tagger",
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				return nil
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeConfig,
+		},
+		{
+			name: "Script",
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				scripter := NewMockScripterItf(ctrl)
+				scripter.EXPECT().
+					RunScript(gomock.Any(), ScriptInvalidateTag, []string{"tag:product:1"}).
+					Return([]interface{}{[]byte("detail"), []byte("price")}, nil)
+				return scriptTagger{MockTaggerItf: NewMockTaggerItf(ctrl), MockScripterItf: scripter}
+			},
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Del(gomock.Any(), "detail")
+				local.EXPECT().Del(gomock.Any(), "price")
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Script failed",
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				scripter := NewMockScripterItf(ctrl)
+				scripter.EXPECT().
+					RunScript(gomock.Any(), ScriptInvalidateTag, []string{"tag:product:1"}).
+					Return(nil, errorx.E("connection refused", errorx.CodeGateway))
+				return scriptTagger{MockTaggerItf: NewMockTaggerItf(ctrl), MockScripterItf: scripter}
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Unexpected script reply",
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				scripter := NewMockScripterItf(ctrl)
+				scripter.EXPECT().
+					RunScript(gomock.Any(), ScriptInvalidateTag, []string{"tag:product:1"}).
+					Return(int64(1), nil)
+				return scriptTagger{MockTaggerItf: NewMockTaggerItf(ctrl), MockScripterItf: scripter}
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name: "Cluster without script",
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				tagger := NewMockTaggerItf(ctrl)
+				tagger.EXPECT().SMembers(gomock.Any(), "tag:product:1").Return([]string{"detail", "price"}, nil)
+				tagger.EXPECT().Del(gomock.Any(), "detail", "price", "tag:product:1").Return(int64(3), nil)
+				return clusterTagger{scriptTagger{MockTaggerItf: tagger, MockScripterItf: NewMockScripterItf(ctrl)}}
+			},
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Del(gomock.Any(), "detail")
+				local.EXPECT().Del(gomock.Any(), "price")
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Without script",
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				tagger := NewMockTaggerItf(ctrl)
+				tagger.EXPECT().SMembers(gomock.Any(), "tag:product:1").Return([]string{"detail", "price"}, nil)
+				tagger.EXPECT().Del(gomock.Any(), "detail", "price", "tag:product:1").Return(int64(3), nil)
+				return tagger
+			},
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Del(gomock.Any(), "detail")
+				local.EXPECT().Del(gomock.Any(), "price")
+			},
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Without script failed",
+			tagger: func(ctrl *gomock.Controller) TaggerItf {
+				tagger := NewMockTaggerItf(ctrl)
+				tagger.EXPECT().SMembers(gomock.Any(), "tag:product:1").Return(nil, errorx.E("connection refused"))
+				return tagger
+			},
+			mock:     func(local *MockRistrettoItf) {},
+			wantCode: errorx.CodeInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			local := NewMockRistrettoItf(ctrl)
+			tt.mock(local)
+
+			client := NewClientWithConfig(NewRistrettoStore(local), nil, ClientConfig{Tagger: tt.tagger(ctrl)})
+			err := client.InvalidateTag(context.Background(), "product:1")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+		})
+	}
+}
+
+func TestClient_InvalidateTag_MultiLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local := NewMockRistrettoItf(ctrl)
+	redis := NewMockRedisItf(ctrl)
+	pubsub := NewMockPubSubItf(ctrl)
+	scripter := NewMockScripterItf(ctrl)
+
+	// The keys are deleted from redis by the script, then evicted from the first level of all the instances.
+	scripter.EXPECT().
+		RunScript(gomock.Any(), ScriptInvalidateTag, []string{"tag:product:1"}).
+		Return([]interface{}{"detail"}, nil)
+	local.EXPECT().Del(gomock.Any(), "detail")
+	pubsub.EXPECT().Publish(gomock.Any(), "invalidation", gomock.Any()).Return(1, nil)
+
+	store := NewMultiLevel(local, redis, pubsub, MultiLevelConfig{InvalidationChannel: "invalidation"})
+	client := NewClientWithConfig(store, nil, ClientConfig{
+		Tagger: scriptTagger{MockTaggerItf: NewMockTaggerItf(ctrl), MockScripterItf: scripter},
+	})
+	assert.NoError(t, client.InvalidateTag(context.Background(), "product:1"))
+}
+
+func TestClient_NamespaceKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(tagger *MockTaggerItf)
+		want     string
+		wantCode errorx.Code
+	}{
+		{
+			name: "Never invalidated",
+			mock: func(tagger *MockTaggerItf) {
+				tagger.EXPECT().Get(gomock.Any(), "product:1:version").Return(nil, errorx.E("key not found", errorx.CodeNotFound))
+			},
+			want:     "product:1:v0:detail",
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Invalidated",
+			mock: func(tagger *MockTaggerItf) {
+				tagger.EXPECT().Get(gomock.Any(), "product:1:version").Return([]byte("3"), nil)
+			},
+			want:     "product:1:v3:detail",
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Invalid version",
+			mock: func(tagger *MockTaggerItf) {
+				tagger.EXPECT().Get(gomock.Any(), "product:1:version").Return([]byte("three"), nil)
+			},
+			want:     "",
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name: "Failed",
+			mock: func(tagger *MockTaggerItf) {
+				tagger.EXPECT().Get(gomock.Any(), "product:1:version").Return(nil, errorx.E("connection refused", errorx.CodeGateway))
+			},
+			want:     "",
+			wantCode: errorx.CodeGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tagger := NewMockTaggerItf(ctrl)
+			tt.mock(tagger)
+
+			client := NewClientWithConfig(NewRistrettoStore(NewMockRistrettoItf(ctrl)), nil, ClientConfig{Tagger: tagger})
+			got, err := client.NamespaceKey(context.Background(), "product:1", "detail")
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_InvalidateNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tagger := NewMockTaggerItf(ctrl)
+	tagger.EXPECT().Incr(gomock.Any(), "product:1:version").Return(int64(1), nil)
+	tagger.EXPECT().Get(gomock.Any(), "product:1:version").Return([]byte("1"), nil)
+
+	client := NewClientWithConfig(NewRistrettoStore(NewMockRistrettoItf(ctrl)), nil, ClientConfig{Tagger: tagger})
+	assert.NoError(t, client.InvalidateNamespace(context.Background(), "product:1"))
+
+	got, err := client.NamespaceKey(context.Background(), "product:1", "detail")
+	assert.NoError(t, err)
+	assert.Equal(t, "product:1:v1:detail", got)
+
+	err = NewClient(NewRistrettoStore(NewMockRistrettoItf(ctrl)), nil).InvalidateNamespace(context.Background(), "product:1")
+	assert.Equal(t, errorx.CodeConfig, errorx.GetCode(err))
+}
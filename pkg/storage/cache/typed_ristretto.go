@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// TypedRistretto is an in-process cache of the values of a single type,
+// so the callers get their values into a typed variable instead of type-asserting an interface{}.
+// The values are stored as is, the pointers and the maps are shared with the callers and must not be mutated.
+//
+// Example:
+//
+//	users := cache.NewTypedRistretto(local, User{})
+//	_, _ = users.Set(ctx, "user:1", User{ID: 1})
+//
+//	var user User
+//	err := users.Get(ctx, "user:1", &user)
+type TypedRistretto struct {
+	client RistrettoItf
+	typ    reflect.Type
+}
+
+// NewTypedRistretto returns the cache of the values of the same type as the prototype, e.g. User{} or &User{}.
+func NewTypedRistretto(client RistrettoItf, prototype interface{}) *TypedRistretto {
+	return &TypedRistretto{
+		client: client,
+		typ:    reflect.TypeOf(prototype),
+	}
+}
+
+// Get gets the value of a key into dst, a pointer to the type of the cache.
+// The missing key is reported as an error with errorx.CodeNotFound,
+// and a dst of another type with errorx.CodeInvalid.
+func (c *TypedRistretto) Get(ctx context.Context, key string, dst interface{}) error {
+	const op errorx.Op = "cache/TypedRistretto.Get"
+
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Type().Elem() != c.typ {
+		return errorx.E(fmt.Errorf("dst must be a non-nil pointer to %s", c.typ), op, errorx.CodeInvalid)
+	}
+
+	res, exists := c.client.Get(ctx, key)
+	if !exists || res == nil {
+		return errorx.E("key not found", op, errorx.CodeNotFound)
+	}
+
+	v := reflect.ValueOf(res)
+	if v.Type() != c.typ {
+		// The key was set by another client of the cache.
+		return errorx.E(fmt.Errorf("unexpected value of type %s", v.Type()), op, errorx.CodeInternal)
+	}
+
+	target.Elem().Set(v)
+	return nil
+}
+
+// Set adds the value of the type of the cache, see RistrettoItf.Set.
+// The value of another type is reported as an error with errorx.CodeInvalid.
+func (c *TypedRistretto) Set(ctx context.Context, key string, value interface{}) (bool, error) {
+	const op errorx.Op = "cache/TypedRistretto.Set"
+
+	if err := c.validate(value); err != nil {
+		return false, errorx.E(err, op)
+	}
+
+	return c.client.Set(ctx, key, value), nil
+}
+
+// SetEX adds the value of the type of the cache for the ttl, see RistrettoItf.SetEX.
+// The value of another type is reported as an error with errorx.CodeInvalid.
+func (c *TypedRistretto) SetEX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	const op errorx.Op = "cache/TypedRistretto.SetEX"
+
+	if err := c.validate(value); err != nil {
+		return false, errorx.E(err, op)
+	}
+
+	return c.client.SetEX(ctx, key, value, ttl), nil
+}
+
+// SetWithCost adds the value of the type of the cache with the given cost, see RistrettoItf.SetWithCost.
+// The value of another type is reported as an error with errorx.CodeInvalid.
+func (c *TypedRistretto) SetWithCost(ctx context.Context, key string, value interface{}, cost int64) (bool, error) {
+	const op errorx.Op = "cache/TypedRistretto.SetWithCost"
+
+	if err := c.validate(value); err != nil {
+		return false, errorx.E(err, op)
+	}
+
+	return c.client.SetWithCost(ctx, key, value, cost), nil
+}
+
+// Del deletes the key-value item from the cache if it exists.
+func (c *TypedRistretto) Del(ctx context.Context, key string) {
+	c.client.Del(ctx, key)
+}
+
+// validate checks the value has the type of the cache.
+func (c *TypedRistretto) validate(value interface{}) error {
+	if typ := reflect.TypeOf(value); typ != c.typ {
+		return errorx.E(fmt.Errorf("value must be of type %s, got %s", c.typ, typ), errorx.CodeInvalid)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type typedUser struct {
+	ID   int64
+	Name string
+}
+
+func TestTypedRistretto_Get(t *testing.T) {
+	user := typedUser{ID: 1, Name: "gopher"}
+
+	tests := []struct {
+		name     string
+		mock     func(local *MockRistrettoItf)
+		dst      func() interface{}
+		want     interface{}
+		wantCode errorx.Code
+	}{
+		{
+			name: "Found",
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Get(gomock.Any(), "key").Return(user, true)
+			},
+			dst:      func() interface{} { return &typedUser{} },
+			want:     &user,
+			wantCode: errorx.CodeUnknown,
+		},
+		{
+			name: "Not found",
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Get(gomock.Any(), "key").Return(nil, false)
+			},
+			dst:      func() interface{} { return &typedUser{} },
+			want:     &typedUser{},
+			wantCode: errorx.CodeNotFound,
+		},
+		{
+			name:     "Invalid dst",
+			mock:     func(local *MockRistrettoItf) {},
+			dst:      func() interface{} { return typedUser{} },
+			want:     typedUser{},
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name:     "Dst of another type",
+			mock:     func(local *MockRistrettoItf) {},
+			dst:      func() interface{} { return &user.Name },
+			want:     &user.Name,
+			wantCode: errorx.CodeInvalid,
+		},
+		{
+			name: "Value of another type",
+			mock: func(local *MockRistrettoItf) {
+				local.EXPECT().Get(gomock.Any(), "key").Return(&user, true)
+			},
+			dst:      func() interface{} { return &typedUser{} },
+			want:     &typedUser{},
+			wantCode: errorx.CodeInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			local := NewMockRistrettoItf(ctrl)
+			tt.mock(local)
+
+			dst := tt.dst()
+			err := NewTypedRistretto(local, typedUser{}).Get(context.Background(), "key", dst)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, dst)
+		})
+	}
+}
+
+func TestTypedRistretto_Set(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &typedUser{ID: 1, Name: "gopher"}
+	local := NewMockRistrettoItf(ctrl)
+	local.EXPECT().Set(gomock.Any(), "key", user).Return(true)
+	local.EXPECT().SetEX(gomock.Any(), "key", user, time.Minute).Return(false)
+	local.EXPECT().SetWithCost(gomock.Any(), "key", user, int64(10)).Return(true)
+
+	users := NewTypedRistretto(local, &typedUser{})
+	ctx := context.Background()
+
+	ok, err := users.Set(ctx, "key", user)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = users.SetEX(ctx, "key", user, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = users.SetWithCost(ctx, "key", user, 10)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// The values of another type are never stored.
+	ok, err = users.Set(ctx, "key", *user)
+	assert.Equal(t, errorx.CodeInvalid, errorx.GetCode(err))
+	assert.False(t, ok)
+}
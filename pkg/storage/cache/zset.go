@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"strconv"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+)
+
+// ZMember is a sorted set member with its score.
+type ZMember struct {
+	Member string
+	Score  float64
+}
+
+// toZMembers converts the flat member and score reply of WITHSCORES commands.
+func toZMembers(values []string) ([]ZMember, error) {
+	const op errorx.Op = "cache.toZMembers"
+
+	if len(values)%2 != 0 {
+		return nil, errorx.E("invalid member and score reply", op, errorx.CodeGateway)
+	}
+
+	res := make([]ZMember, 0, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		score, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			return nil, errorx.E(err, op, errorx.CodeGateway)
+		}
+
+		res = append(res, ZMember{
+			Member: values[i],
+			Score:  score,
+		})
+	}
+
+	return res, nil
+}
+
+// zrangeLimit returns the LIMIT count of a range by score, and reports whether the range is limited.
+// Zero count means no limit, so it is sent as -1 with a non-zero offset,
+// otherwise redis would return no member at all.
+func zrangeLimit(offset, count int64) (int64, bool) {
+	if offset == 0 && count == 0 {
+		return 0, false
+	}
+	if count == 0 {
+		return -1, true
+	}
+
+	return count, true
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/peractio/gdk/pkg/errorx/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToZMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		want     []ZMember
+		wantCode errorx.Code
+	}{
+		{
+			name:     "Odd reply",
+			values:   []string{"a"},
+			want:     nil,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name:     "Invalid score",
+			values:   []string{"a", "x"},
+			want:     nil,
+			wantCode: errorx.CodeGateway,
+		},
+		{
+			name:     "Success",
+			values:   []string{"a", "1", "b", "2.5"},
+			want:     []ZMember{{Member: "a", Score: 1}, {Member: "b", Score: 2.5}},
+			wantCode: errorx.CodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toZMembers(tt.values)
+			assert.Equal(t, tt.wantCode, errorx.GetCode(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestZRangeLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		offset      int64
+		count       int64
+		wantCount   int64
+		wantLimited bool
+	}{
+		{name: "No limit", offset: 0, count: 0, wantCount: 0, wantLimited: false},
+		{name: "Offset without count", offset: 2, count: 0, wantCount: -1, wantLimited: true},
+		{name: "Offset and count", offset: 2, count: 3, wantCount: 3, wantLimited: true},
+		{name: "Count without offset", offset: 0, count: 3, wantCount: 3, wantLimited: true},
+		{name: "Negative count", offset: 2, count: -1, wantCount: -1, wantLimited: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, limited := zrangeLimit(tt.offset, tt.count)
+			assert.Equal(t, tt.wantCount, count)
+			assert.Equal(t, tt.wantLimited, limited)
+		})
+	}
+}
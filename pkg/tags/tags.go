@@ -122,4 +122,6 @@ const (
 	Cache = "cache"
 	// Client
 	Client = "client"
+	// State
+	State = "state"
 )